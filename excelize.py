@@ -10,10 +10,21 @@ provided streaming API for generating or reading data from a worksheet with huge
 amounts of data. This library needs Python version 3.9 or later.
 """
 
-from dataclasses import fields
-from datetime import datetime, date, time
+from dataclasses import fields, is_dataclass
+from datetime import datetime, date, time, timedelta, timezone
 from enum import Enum
-from typing import Tuple, get_args, get_origin, Dict, List, Optional, Union
+from math import isinf
+from typing import (
+    Any,
+    Callable,
+    Tuple,
+    get_args,
+    get_origin,
+    Dict,
+    List,
+    Optional,
+    Union,
+)
 from ctypes import (
     byref,
     c_bool,
@@ -29,13 +40,17 @@ from ctypes import (
     create_string_buffer,
     POINTER,
     pointer,
+    sizeof,
     string_at,
 )
+import base64
+import json
 import os
 import platform
 import sys
 import struct
 import types_go
+from zoneinfo import ZoneInfo
 from types_py import *
 
 
@@ -99,9 +114,210 @@ def load_lib() -> Optional[str]:
 
 
 lib = CDLL(os.path.join(os.path.dirname(__file__), load_lib()))
+# restypes defines the result types of the functions of the shared library,
+# indexed by the function names. They are set once when the package is imported,
+# since reassigning the result type while the same function is being called in
+# another thread releases the result converter in use.
+restypes = {
+    "AddChart": types_go._ErrorResult,
+    "AddChartSheet": types_go._ErrorResult,
+    "AddComment": types_go._ErrorResult,
+    "AddDataValidation": types_go._ErrorResult,
+    "AddFormControl": types_go._ErrorResult,
+    "AddHeaderFooterImage": types_go._ErrorResult,
+    "AddIgnoredErrors": types_go._ErrorResult,
+    "AddPicture": types_go._ErrorResult,
+    "AddPictureFromBytes": types_go._ErrorResult,
+    "AddPivotTable": types_go._ErrorResult,
+    "AddShape": types_go._ErrorResult,
+    "AddSlicer": types_go._ErrorResult,
+    "AddSparkline": types_go._ErrorResult,
+    "AddTable": types_go._ErrorResult,
+    "AddVBAProject": types_go._ErrorResult,
+    "AutoFilter": types_go._ErrorResult,
+    "AutoFitColWidth": types_go._ErrorResult,
+    "CalcCellValue": types_go._StringErrorResult,
+    "CalcCellValueWithTimeout": types_go._StringErrorResult,
+    "CallJSON": types_go._StringErrorResult,
+    "CancelCalc": types_go._ErrorResult,
+    "CellNameToCoordinates": types_go._CellNameToCoordinatesResult,
+    "CheckConverters": types_go._StringArrayErrorResult,
+    "Close": types_go._ErrorResult,
+    "Cols": types_go._IntErrorResult,
+    "ColsClose": types_go._ErrorResult,
+    "ColsError": types_go._ErrorResult,
+    "ColsNext": types_go._BoolErrorResult,
+    "ColsRows": types_go._StringArrayErrorResult,
+    "ColumnNameToNumber": types_go._IntErrorResult,
+    "ColumnNumberToName": types_go._StringErrorResult,
+    "CoordinatesToCellName": types_go._StringErrorResult,
+    "CopySheet": types_go._ErrorResult,
+    "DeleteChart": types_go._ErrorResult,
+    "DeleteComment": types_go._ErrorResult,
+    "DeleteDefinedName": types_go._ErrorResult,
+    "DeleteFormControl": types_go._ErrorResult,
+    "DeletePicture": types_go._ErrorResult,
+    "DeleteSheet": types_go._ErrorResult,
+    "DeleteSlicer": types_go._ErrorResult,
+    "DuplicateRow": types_go._ErrorResult,
+    "DuplicateRowTo": types_go._ErrorResult,
+    "GetActiveSheetIndex": c_longlong,
+    "GetAppProps": types_go._GetAppPropsResult,
+    "GetBuildInfo": types_go._BuildInfoResult,
+    "GetCalcProps": types_go._GetCalcPropsResult,
+    "GetCellFormula": types_go._StringErrorResult,
+    "GetCellHyperLink": types_go._GetCellHyperLinkResult,
+    "GetCellRichText": types_go._GetCellRichTextResult,
+    "GetCellStyle": types_go._IntErrorResult,
+    "GetCellType": types_go._IntErrorResult,
+    "GetCellTypedValue": types_go._InterfaceErrorResult,
+    "GetCellValue": types_go._StringErrorResult,
+    "GetColOutlineLevel": types_go._IntErrorResult,
+    "GetColStyle": types_go._IntErrorResult,
+    "GetColVisible": types_go._BoolErrorResult,
+    "GetColWidth": types_go._Float64ErrorResult,
+    "GetCols": types_go._StringMatrixErrorResult,
+    "GetComments": types_go._GetCommentsResult,
+    "GetCustomProps": types_go._GetCustomPropsResult,
+    "GetDataValidations": types_go._GetDataValidationsResult,
+    "GetDefaultFont": types_go._StringErrorResult,
+    "GetDefinedName": types_go._GetDefinedNameResult,
+    "GetDocProps": types_go._GetDocPropsResult,
+    "GetFormControls": types_go._GetFormControlsResult,
+    "GetHyperLinkCells": types_go._StringArrayErrorResult,
+    "GetMergeCells": types_go._StringMatrixErrorResult,
+    "GetPageLayout": types_go._GetPageLayoutResult,
+    "GetPageMargins": types_go._GetPageMarginsResult,
+    "GetPictures": types_go._GetPicturesResult,
+    "GetPivotTables": types_go._GetPivotTablesResult,
+    "GetRange": types_go._StringMatrixErrorResult,
+    "GetRowHeight": types_go._Float64ErrorResult,
+    "GetRowOutlineLevel": types_go._IntErrorResult,
+    "GetRowVisible": types_go._BoolErrorResult,
+    "GetRows": types_go._StringMatrixErrorResult,
+    "GetSheetDimension": types_go._StringErrorResult,
+    "GetSheetIndex": types_go._IntErrorResult,
+    "GetSheetList": types_go._StringArrayErrorResult,
+    "GetSheetMap": types_go._GetSheetMapResult,
+    "GetSheetName": types_go._StringErrorResult,
+    "GetSheetProps": types_go._GetSheetPropsResult,
+    "GetSheetProtection": types_go._GetSheetProtectionResult,
+    "GetSheetView": types_go._GetSheetViewResult,
+    "GetSheetVisible": types_go._BoolErrorResult,
+    "GetSlicers": types_go._GetSlicersResult,
+    "GetStyle": types_go._GetStyleResult,
+    "GetTableRows": types_go._GetTableRowsResult,
+    "GetTables": types_go._GetTablesResult,
+    "GetTypedRows": types_go._TypedCellMatrixErrorResult,
+    "GetWorkbookProps": types_go._GetWorkbookPropsResult,
+    "GroupSheets": types_go._ErrorResult,
+    "InsertCols": types_go._ErrorResult,
+    "InsertPageBreak": types_go._ErrorResult,
+    "InsertRows": types_go._ErrorResult,
+    "JoinCellName": types_go._StringErrorResult,
+    "ListHandles": types_go._ListHandlesResult,
+    "MergeCell": types_go._ErrorResult,
+    "MoveSheet": types_go._ErrorResult,
+    "NewConditionalStyle": types_go._IntErrorResult,
+    "NewFile": c_longlong,
+    "NewSheet": types_go._IntErrorResult,
+    "NewStreamWriter": types_go._IntErrorResult,
+    "NewStreamWriterAppend": types_go._IntErrorResult,
+    "NewStyle": types_go._IntErrorResult,
+    "OpenFile": types_go._IntErrorResult,
+    "OpenFileWithProgress": types_go._IntErrorResult,
+    "OpenReader": types_go._IntErrorResult,
+    "OpenReaderWithProgress": types_go._IntErrorResult,
+    "ProtectSheet": types_go._ErrorResult,
+    "ProtectWorkbook": types_go._ErrorResult,
+    "RemoveCol": types_go._ErrorResult,
+    "RemovePageBreak": types_go._ErrorResult,
+    "RemoveRow": types_go._ErrorResult,
+    "Rows": types_go._IntErrorResult,
+    "RowsClose": types_go._ErrorResult,
+    "RowsColumns": types_go._StringArrayErrorResult,
+    "RowsError": types_go._ErrorResult,
+    "RowsGetRowOpts": types_go._GetRowOptsResult,
+    "RowsNext": types_go._BoolErrorResult,
+    "RowsNextBatch": types_go._StringMatrixErrorResult,
+    "Save": types_go._ErrorResult,
+    "SaveAs": types_go._ErrorResult,
+    "SearchSheet": types_go._StringArrayErrorResult,
+    "SetActiveSheet": types_go._ErrorResult,
+    "SetAppProps": types_go._ErrorResult,
+    "SetCalcProps": types_go._ErrorResult,
+    "SetCellBool": types_go._ErrorResult,
+    "SetCellDefault": types_go._ErrorResult,
+    "SetCellFloat": types_go._ErrorResult,
+    "SetCellFormula": types_go._ErrorResult,
+    "SetCellHyperLink": types_go._ErrorResult,
+    "SetCellInt": types_go._ErrorResult,
+    "SetCellRichText": types_go._ErrorResult,
+    "SetCellStr": types_go._ErrorResult,
+    "SetCellStyle": types_go._ErrorResult,
+    "SetCellValue": types_go._ErrorResult,
+    "SetColOutlineLevel": types_go._ErrorResult,
+    "SetColStyle": types_go._ErrorResult,
+    "SetColVisible": types_go._ErrorResult,
+    "SetColWidth": types_go._ErrorResult,
+    "SetConditionalFormat": types_go._ErrorResult,
+    "SetCustomProps": types_go._ErrorResult,
+    "SetDefaultFont": types_go._ErrorResult,
+    "SetDefinedName": types_go._ErrorResult,
+    "SetDocProps": types_go._ErrorResult,
+    "SetHeaderFooter": types_go._ErrorResult,
+    "SetLocking": types_go._ErrorResult,
+    "SetPageLayout": types_go._ErrorResult,
+    "SetPageMargins": types_go._ErrorResult,
+    "SetPanes": types_go._ErrorResult,
+    "SetProgressCallback": types_go._ErrorResult,
+    "SetRange": types_go._ErrorResult,
+    "SetRowHeight": types_go._ErrorResult,
+    "SetRowOutlineLevel": types_go._ErrorResult,
+    "SetRowStyle": types_go._ErrorResult,
+    "SetRowVisible": types_go._ErrorResult,
+    "SetSheetBackground": types_go._ErrorResult,
+    "SetSheetBackgroundFromBytes": types_go._ErrorResult,
+    "SetSheetCol": types_go._ErrorResult,
+    "SetSheetDimension": types_go._ErrorResult,
+    "SetSheetName": types_go._ErrorResult,
+    "SetSheetProps": types_go._ErrorResult,
+    "SetSheetRow": types_go._ErrorResult,
+    "SetSheetView": types_go._ErrorResult,
+    "SetSheetVisible": types_go._ErrorResult,
+    "SetWorkbookProps": types_go._ErrorResult,
+    "SplitCellName": types_go._StringIntErrorResult,
+    "StreamAddTable": types_go._ErrorResult,
+    "StreamDiscard": types_go._ErrorResult,
+    "StreamFlush": types_go._ErrorResult,
+    "StreamInsertPageBreak": types_go._ErrorResult,
+    "StreamMergeCell": types_go._ErrorResult,
+    "StreamNextRow": types_go._IntErrorResult,
+    "StreamSetColOutlineLevel": types_go._ErrorResult,
+    "StreamSetColWidth": types_go._ErrorResult,
+    "StreamSetPanes": types_go._ErrorResult,
+    "StreamSetRow": types_go._ErrorResult,
+    "StreamSetRowCells": types_go._ErrorResult,
+    "UngroupSheets": types_go._ErrorResult,
+    "UnmergeCell": types_go._ErrorResult,
+    "UnprotectSheet": types_go._ErrorResult,
+    "UnprotectWorkbook": types_go._ErrorResult,
+    "UnsetConditionalFormat": types_go._ErrorResult,
+    "UpdateLinkedValue": types_go._ErrorResult,
+    "WriteToBuffer": types_go._BytesErrorResult,
+}
+for name, restype in restypes.items():
+    # The missing functions are reported by check_abi_version after the package
+    # was loaded
+    if hasattr(lib, name):
+        getattr(lib, name).restype = restype
 ENCODE = "utf-8"
-__version__ = "0.0.9"
-uppercase_words = ["id", "rgb", "sq", "xml"]
+__version__ = "0.1.0"
+uppercase_words = ["abi", "id", "rgb", "sq", "xml"]
+# abi_version defines the ABI version of the shared library expected by the
+# Python package, it should be updated together with the ABI version of the
+# shared library.
+abi_version = 1
 
 
 def py_to_base_ctype(py_value, c_type):
@@ -114,7 +330,20 @@ def py_to_base_ctype(py_value, c_type):
 
     Returns:
         The converted value in the specified C type.
+
+    Raises:
+        OverflowError: If the numeric value is out of the range of the C type.
     """
+    code = getattr(c_type, "_type_", None)
+    if type(py_value) is int and isinstance(code, str) and code in "bBhHiIlLqQ":
+        bits = sizeof(c_type) * 8
+        if code.islower() and not -(2 ** (bits - 1)) <= py_value < 2 ** (bits - 1):
+            raise OverflowError(f"integer {py_value} out of {bits}-bit range")
+        if code.isupper() and not 0 <= py_value < 2**bits:
+            raise OverflowError(f"integer {py_value} out of unsigned {bits}-bit range")
+    if code == "f" and isinstance(py_value, (int, float)):
+        if not isinf(py_value) and isinf(c_type(py_value).value):
+            raise OverflowError(f"float {py_value} out of 32-bit range")
     return (
         c_type(py_value.encode(ENCODE)) if str is type(py_value) else c_type(py_value)
     )
@@ -447,6 +676,117 @@ def py_value_to_c(py_instance, ctypes_instance):
     return ctypes_instance
 
 
+def py_value_to_json(py_value):
+    """
+    Converts a Python value to a JSON compatible value which can be decoded
+    into the corresponding Go value. The fields of the data classes are named
+    in PascalCase and the fields with None value are omitted, the enums are
+    converted to their values, the bytes are encoded in base64 and the naive
+    datetime is the local time.
+
+    Args:
+        py_value: The Python value to be converted.
+
+    Returns:
+        The JSON compatible value.
+    """
+    if is_dataclass(py_value):
+        values = {
+            field.name: getattr(py_value, field.name, None)
+            for field in fields(py_value)
+        }
+        return {
+            snake_to_pascal(name): py_value_to_json(value)
+            for name, value in values.items()
+            if value is not None
+        }
+    if isinstance(py_value, Enum):
+        return py_value.value
+    if isinstance(py_value, (list, tuple)):
+        return [py_value_to_json(value) for value in py_value]
+    if isinstance(py_value, dict):
+        return {key: py_value_to_json(value) for key, value in py_value.items()}
+    if isinstance(py_value, bytes):
+        return base64.b64encode(py_value).decode(ENCODE)
+    if isinstance(py_value, datetime):
+        if py_value.utcoffset() is None:
+            py_value = py_value.astimezone()
+        return py_value.isoformat()
+    return py_value
+
+
+EPOCH = datetime(1970, 1, 1)
+
+
+def py_int_to_interface(value: int) -> Interface:
+    """
+    Converts a Python integer to the narrowest C interface integer type which
+    can hold it without loss.
+
+    Args:
+        value (int): The integer to be converted.
+
+    Returns:
+        Interface: An Interface object representing the integer.
+
+    Raises:
+        OverflowError: If the integer is out of the 64-bit integer range.
+    """
+    if -(2**31) <= value < 2**31:
+        return Interface(type=1, integer=value)
+    if -(2**63) <= value < 2**63:
+        return Interface(type=7, integer=value)
+    if 0 <= value < 2**64:
+        return Interface(type=8, u_integer64=value)
+    raise OverflowError(f"integer {value} out of 64-bit range")
+
+
+def py_datetime_to_interface(value: datetime) -> Interface:
+    """
+    Converts a Python datetime to the C interface time type. A naive datetime
+    is the local time as the `datetime.timestamp` function assumes, which is
+    sent in the "Local" location, and an aware datetime keeps its UTC offset and
+    the zone name if the tzinfo has one.
+
+    Args:
+        value (datetime): The datetime to be converted.
+
+    Returns:
+        Interface: An Interface object representing the datetime.
+    """
+    if value.utcoffset() is None:
+        value, location = value.astimezone(), "Local"
+    else:
+        location = getattr(value.tzinfo, "key", None) or value.tzname() or ""
+    offset = value.utcoffset()
+    delta = value.replace(tzinfo=None) - offset - EPOCH
+    return Interface(
+        type=6,
+        integer=delta.days * 86400 + delta.seconds,
+        nanosecond=delta.microseconds * 1000,
+        offset=int(offset.total_seconds()),
+        location=location,
+    )
+
+
+def py_timedelta_to_interface(value: timedelta) -> Interface:
+    """
+    Converts a Python timedelta to the C interface duration type in
+    nanoseconds.
+
+    Args:
+        value (timedelta): The timedelta to be converted.
+
+    Returns:
+        Interface: An Interface object representing the timedelta.
+    """
+    return Interface(
+        type=9,
+        integer=(value.days * 86400 + value.seconds) * 10**9
+        + value.microseconds * 1000,
+    )
+
+
 def py_value_to_c_interface(py_value):
     """
     Converts a Python value to a C interface representation.
@@ -461,18 +801,96 @@ def py_value_to_c_interface(py_value):
         TypeError: If the type of py_value is not supported.
     """
     type_mappings = {
-        int: lambda: Interface(type=1, integer=py_value),
+        int: lambda: py_int_to_interface(py_value),
         str: lambda: Interface(type=3, string=py_value),
+        CellError: lambda: Interface(type=10, string=py_value),
+        CellFormula: lambda: Interface(type=11, string=py_value),
         float: lambda: Interface(type=4, float64=py_value),
         bool: lambda: Interface(type=5, boolean=py_value),
-        datetime: lambda: Interface(type=6, integer=int(py_value.timestamp())),
-        date: lambda: Interface(
-            type=6,
-            integer=int(datetime.combine(py_value, time.min).timestamp()),
-        ),
+        datetime: lambda: py_datetime_to_interface(py_value),
+        date: lambda: py_datetime_to_interface(datetime.combine(py_value, time.min)),
+        timedelta: lambda: py_timedelta_to_interface(py_value),
     }
     interface = type_mappings.get(type(py_value), Interface)()
-    return py_value_to_c(interface, types_go._Interface())
+    return types_go._Interface(
+        Type=interface.type,
+        Integer=interface.integer,
+        String=interface.string.encode(ENCODE),
+        Float64=interface.float64,
+        Boolean=interface.boolean,
+        UInteger64=interface.u_integer64,
+        Nanosecond=interface.nanosecond,
+        Offset=interface.offset,
+        Location=interface.location.encode(ENCODE),
+    )
+
+
+def py_value_to_c_cell(py_value) -> types_go._Cell:
+    """
+    Convert the value of a stream row to the C cell structure. The value could
+    be a Cell, a list of RichTextRun, or the value of a cell.
+
+    Args:
+        py_value: The value of the stream row
+
+    Returns:
+        types_go._Cell: The C cell structure
+    """
+    if isinstance(py_value, list):
+        cell = Cell(rich_text=py_value)
+    elif isinstance(py_value, Cell):
+        cell = py_value
+    else:
+        cell = Cell(value=py_value)
+    prepare_args(
+        [cell.style_id, cell.formula],
+        [argsRule("style_id", [int]), argsRule("formula", [str])],
+    )
+    c_cell = types_go._Cell(
+        StyleID=cell.style_id,
+        Formula=cell.formula.encode(ENCODE),
+        Value=py_value_to_c_interface(cell.value),
+    )
+    if cell.rich_text:
+        runs = (types_go._RichTextRun * len(cell.rich_text))()
+        for i, run in enumerate(cell.rich_text):
+            prepare_args([run], [argsRule("rich_text", [RichTextRun])])
+            runs[i] = py_value_to_c(run, types_go._RichTextRun())
+        c_cell.RichTextLen = len(runs)
+        c_cell.RichText = runs
+    return c_cell
+
+
+def c_time_to_py(c_value) -> datetime:
+    """
+    Converts a C interface time to a Python datetime. The time without location
+    and offset, such as the date value of a cell, is returned as a naive
+    datetime of its wall clock. The time in UTC or in the "Local" location is
+    returned as a naive datetime in the local time as `datetime.fromtimestamp`
+    does, and the time in other locations or with an offset is returned as an
+    aware datetime.
+
+    Args:
+        c_value: The C interface value to be converted.
+
+    Returns:
+        datetime: The converted datetime.
+    """
+    value = EPOCH + timedelta(
+        seconds=c_value.Integer, microseconds=c_value.Nanosecond // 1000
+    )
+    location = c_value.Location.decode(ENCODE) if c_value.Location else ""
+    if location == "" and c_value.Offset == 0:
+        return value
+    if location in ("UTC", "Local"):
+        return value.replace(tzinfo=timezone.utc).astimezone().replace(tzinfo=None)
+    tz = timezone(timedelta(seconds=c_value.Offset))
+    if location != "":
+        try:
+            tz = ZoneInfo(location)
+        except (ValueError, LookupError):
+            pass
+    return value.replace(tzinfo=timezone.utc).astimezone(tz)
 
 
 def c_value_to_py_interface(c_value):
@@ -491,12 +909,20 @@ def c_value_to_py_interface(c_value):
     if c_value is None:
         return None
     type_mappings = {
+        0: lambda: None,
         1: lambda: c_value.Integer,
         2: lambda: c_value.Integer32,
         3: lambda: c_value.String.decode(ENCODE) if c_value.String else "",
         4: lambda: c_value.Float64,
         5: lambda: c_value.Boolean,
-        6: lambda: datetime.fromtimestamp(c_value.Integer),
+        6: lambda: c_time_to_py(c_value),
+        7: lambda: c_value.Integer,
+        8: lambda: c_value.UInteger64,
+        9: lambda: timedelta(microseconds=c_value.Integer // 1000),
+        10: lambda: CellError(c_value.String.decode(ENCODE) if c_value.String else ""),
+        11: lambda: CellFormula(
+            c_value.String.decode(ENCODE) if c_value.String else ""
+        ),
     }
     converter = type_mappings.get(c_value.Type)
     if converter:
@@ -504,6 +930,280 @@ def c_value_to_py_interface(c_value):
     raise TypeError(f"unsupported interface type code: {c_value.Type}")
 
 
+def c_typed_cells_to_py(c_rows, row_len: int) -> List[List[TypedCell]]:
+    """
+    Converts the C array of the typed cells rows to the Python typed cells
+    matrix.
+
+    Args:
+        c_rows: The pointer of the C array of the typed cells rows.
+        row_len (int): The number of the rows.
+
+    Returns:
+        List[List[TypedCell]]: The typed cells matrix.
+    """
+    rows = []
+    for i in range(row_len):
+        row = c_rows[i]
+        rows.append(
+            [
+                TypedCell(
+                    cell_type=CellType(row.Cell[j].CellType),
+                    value=c_value_to_py_interface(row.Cell[j].Value),
+                    raw=row.Cell[j].Raw.decode(ENCODE),
+                    formatted=row.Cell[j].Formatted.decode(ENCODE),
+                    style_id=row.Cell[j].StyleID,
+                )
+                for j in range(row.CellLen)
+            ]
+        )
+    return rows
+
+
+def free_result(result) -> None:
+    """
+    Release the memory allocated by the shared library for the result
+    structure, including the strings, arrays and structures referenced by it.
+
+    Args:
+        result: The ctypes instance of the result structure.
+    """
+    getattr(lib, "Free" + type(result).__name__.lstrip("_"))(byref(result))
+
+
+class ExcelizeError(RuntimeError):
+    """
+    ExcelizeError is the error raised by the functions when the shared library
+    returns an error, the `code` attribute is the stable numeric error code.
+    """
+
+    code: ErrorCode
+
+    def __init__(self, message: str, code: ErrorCode):
+        super().__init__(message)
+        self.code = code
+
+
+class HandleError(ExcelizeError):
+    """
+    HandleError is raised when the workbook, iterator or stream writer handle
+    can not be found or has been released.
+    """
+
+
+class SheetNotExistError(ExcelizeError):
+    """
+    SheetNotExistError is raised when the worksheet does not exist.
+    """
+
+
+class ParameterError(ExcelizeError):
+    """
+    ParameterError is raised when the parameter is invalid or required.
+    """
+
+
+class CoordinatesError(ExcelizeError):
+    """
+    CoordinatesError is raised when the cell reference, column name, row number
+    or coordinates are invalid.
+    """
+
+
+class WorkbookPasswordError(ExcelizeError):
+    """
+    WorkbookPasswordError is raised when the supplied open workbook password is
+    not correct.
+    """
+
+
+class CanceledError(ExcelizeError):
+    """
+    CanceledError is raised when the operation has been canceled by the
+    progress callback, or the calculation has been canceled. The interrupted
+    calculation can not be stopped, it keeps running in the background until it
+    returns.
+    """
+
+
+class TimeoutExceededError(CanceledError):
+    """
+    TimeoutExceededError is raised when the timeout of the calculation elapsed,
+    the calculation keeps running in the background until it returns.
+    """
+
+
+error_classes = {
+    ErrorCode.ErrCodeHandleNotFound: HandleError,
+    ErrorCode.ErrCodeHandleReleased: HandleError,
+    ErrorCode.ErrCodeSheetNotExist: SheetNotExistError,
+    ErrorCode.ErrCodeParameterInvalid: ParameterError,
+    ErrorCode.ErrCodeParameterRequired: ParameterError,
+    ErrorCode.ErrCodeCoordinates: CoordinatesError,
+    ErrorCode.ErrCodeWorkbookPassword: WorkbookPasswordError,
+    ErrorCode.ErrCodeCanceled: CanceledError,
+    ErrorCode.ErrCodeTimeout: TimeoutExceededError,
+}
+
+
+def new_error(message: str, code: int) -> ExcelizeError:
+    """
+    Create the error by given error message and error code returned by the
+    shared library.
+
+    Args:
+        message (str): The error message
+        code (int): The error code
+
+    Returns:
+        ExcelizeError: The error of the class corresponding to the error code.
+    """
+    try:
+        code = ErrorCode(code)
+    except ValueError:
+        code = ErrorCode.ErrCodeUnknown
+    return error_classes.get(code, ExcelizeError)(message, code)
+
+
+def check_error_result(result: types_go._ErrorResult) -> None:
+    """
+    Release the error result returned by the shared library, and raise the
+    error if the result contains an error message.
+
+    Args:
+        result (types_go._ErrorResult): The error result
+    """
+    try:
+        err = result.err.decode(ENCODE)
+        if err:
+            raise new_error(err, result.ErrCode)
+    finally:
+        free_result(result)
+
+
+def new_progress_callback(
+    callback: Optional[Callable[[ProgressEvent], Any]],
+) -> Optional[types_go._ProgressCallback]:
+    """
+    Create the C function pointer of the progress callback, which converts the
+    progress events for the given callback. Returning a true value from the
+    callback or raising an exception in it cancels the operation. The caller
+    should keep a reference to the function pointer as long as it can be called.
+
+    Args:
+        callback (Optional[Callable[[ProgressEvent], Any]]): The progress
+        callback
+
+    Returns:
+        Optional[types_go._ProgressCallback]: The C function pointer, or None if
+        the callback is None.
+
+    Raises:
+        TypeError: If the callback is not callable.
+    """
+    if callback is None:
+        return None
+    if not callable(callback):
+        raise TypeError(
+            "expected type Callable for argument 'callback', "
+            f"but got {type(callback).__name__}"
+        )
+
+    def progress(event) -> int:
+        try:
+            c_event = event.contents
+            py_event = ProgressEvent(
+                phase=ProgressPhase(c_event.Phase),
+                step=ProgressStep(c_event.Step) if c_event.Step else None,
+                sheet=c_event.Sheet.decode(ENCODE) if c_event.Sheet else "",
+                rows_processed=c_event.RowsProcessed,
+                bytes_processed=c_event.BytesProcessed,
+                bytes_total=c_event.BytesTotal,
+                done=c_event.Done,
+            )
+            return 1 if callback(py_event) else 0
+        except Exception:
+            return 1
+
+    return types_go._ProgressCallback(progress)
+
+
+class Cols:
+    """
+    Cols defines an iterator to a sheet.
+    """
+
+    index: int
+
+    def __init__(self, index: int):
+        self.index = index
+
+    def close(self) -> None:
+        """
+        Releases the columns iterator, the iterator can not be used after it was
+        closed.
+        """
+        res = lib.ColsClose(self.index)
+        check_error_result(res)
+
+    def error(self) -> None:
+        """
+        Return the error when the error occurs.
+
+        Returns:
+            None: Return None if no error occurred, otherwise raise a
+            RuntimeError with the message.
+        """
+        res = lib.ColsError(self.index)
+        check_error_result(res)
+
+    def next(self) -> bool:
+        """
+        Return `True` if the next column is found.
+
+        Returns:
+            bool: Return if the next column is found if no error occurred,
+            otherwise raise a RuntimeError with the message.
+        """
+        res = lib.ColsNext(self.index)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def rows(self, *opts: Options) -> List[str]:
+        """
+        Return the current column's row values. This fetches the worksheet data
+        as a stream, returns each cell in a column as is, and will not skip empty
+        columns in the tail of the worksheet.
+
+        Args:
+            *opts (Options): Optional parameters for get row cells value
+
+        Returns:
+            List[str]: Return the current column's row values if no error
+            occurred, otherwise raise a RuntimeError with the message.
+        """
+        prepare_args(
+            [opts[0]] if opts else [],
+            [argsRule("opts", [Options], True)],
+        )
+        options = None
+        if len(opts) > 0:
+            options = byref(py_value_to_c(opts[0], types_go._Options()))
+        res = lib.ColsRows(self.index, options)
+        try:
+            result = c_value_to_py(res, StringArrayErrorResult())
+            if not result.err:
+                return result.arr if result.arr else []
+            raise new_error(result.err, res.ErrCode)
+        finally:
+            free_result(res)
+
+
 class MergeCell:
     """
     MergeCell define a merged cell data. It consists of the following structure.
@@ -558,10 +1258,8 @@ class Rows:
         """
         Closes the open worksheet XML file in the system temporary directory.
         """
-        lib.RowsClose.restype = c_char_p
-        err = lib.RowsClose(self.index).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.RowsClose(self.index)
+        check_error_result(res)
 
     def columns(self, *opts: Options) -> List[str]:
         """
@@ -580,12 +1278,15 @@ class Rows:
             [opts[0]] if opts else [],
             [argsRule("opts", [Options], True)],
         )
-        lib.RowsColumns.restype, options = types_go._StringArrayErrorResult, None
+        options = None
         if len(opts) > 0:
             options = byref(py_value_to_c(opts[0], types_go._Options()))
         res = lib.RowsColumns(self.index, options)
-        arr = c_value_to_py(res, StringArrayErrorResult()).arr
-        return arr if arr else []
+        try:
+            arr = c_value_to_py(res, StringArrayErrorResult()).arr
+            return arr if arr else []
+        finally:
+            free_result(res)
 
     def error(self) -> None:
         """
@@ -595,10 +1296,8 @@ class Rows:
             None: Return None if no error occurred, otherwise raise a
             RuntimeError with the message.
         """
-        lib.RowsError.restype = c_char_p
-        err = lib.RowsError(self.index).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.RowsError(self.index)
+        check_error_result(res)
 
     def get_row_opts(self) -> Optional[RowOpts]:
         """
@@ -608,12 +1307,14 @@ class Rows:
             Optional[RowOpts]: Return the row options of the current row if no
             error occurred, otherwise raise a RuntimeError with the message.
         """
-        lib.RowsGetRowOpts.restype = types_go._GetRowOptsResult
         res = lib.RowsGetRowOpts(self.index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, RowOpts())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, RowOpts())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def next(self) -> bool:
         """
@@ -623,12 +1324,69 @@ class Rows:
             bool: Return if it finds the next row element if no error occurred,
             otherwise raise a RuntimeError with the message.
         """
-        lib.RowsNext.restype = types_go._BoolErrorResult
         res = lib.RowsNext(self.index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def next_batch(
+        self, n: int, *opts: Options
+    ) -> List[Tuple[int, List[str], RowOpts]]:
+        """
+        Advance the iterator by up to the given number of rows, and return the
+        row number, the column values and the row options of each row at once.
+        Fewer rows will be returned at the end of the worksheet, and an empty
+        list will be returned if there are no more rows.
+
+        Args:
+            n (int): The maximum number of rows to fetch
+            *opts (Options): Optional parameters for get column cells value
+
+        Returns:
+            List[Tuple[int, List[str], RowOpts]]: Return the rows if no error
+            occurred, otherwise raise a RuntimeError with the message.
+
+        Example:
+            For example:
+
+            ```python
+            try:
+                rows = f.rows("Sheet1")
+                while batch := rows.next_batch(1000):
+                    for row_num, cells, row_opts in batch:
+                        print(row_num, cells, row_opts.height)
+                rows.close()
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args(
+            [n, opts[0]] if opts else [n],
+            [argsRule("n", [int]), argsRule("opts", [Options], True)],
+        )
+        options = (
+            byref(py_value_to_c(opts[0], types_go._Options()))
+            if opts
+            else POINTER(types_go._Options)()
+        )
+        res = lib.RowsNextBatch(self.index, n, options)
+        try:
+            err = res.err.decode(ENCODE)
+            if err:
+                raise new_error(err, res.ErrCode)
+            result = c_value_to_py(res, StringMatrixErrorResult())
+            return [
+                (row_num, row.cell if row.cell else [], row_opts)
+                for row_num, row, row_opts in zip(
+                    result.row_num or [], result.row or [], result.row_opts or []
+                )
+            ]
+        finally:
+            free_result(res)
 
 
 class StreamWriter:
@@ -638,9 +1396,11 @@ class StreamWriter:
     """
 
     sw_index: int
+    progress_callback: Optional[types_go._ProgressCallback]
 
     def __init__(self, sw_index: int):
         self.sw_index = sw_index
+        self.progress_callback = None
 
     def add_table(self, table: Table) -> None:
         """
@@ -670,11 +1430,9 @@ class StreamWriter:
             ```
         """
         prepare_args([table], [argsRule("table", [Table])])
-        lib.StreamAddTable.restype = c_char_p
         options = py_value_to_c(table, types_go._Table())
-        err = lib.StreamAddTable(self.sw_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.StreamAddTable(self.sw_index, byref(options))
+        check_error_result(res)
 
     def insert_page_break(self, cell: str) -> None:
         """
@@ -691,12 +1449,8 @@ class StreamWriter:
             RuntimeError with the message.
         """
         prepare_args([cell], [argsRule("cell", [str])])
-        lib.StreamInsertPageBreak.restype = c_char_p
-        err = lib.StreamInsertPageBreak(self.sw_index, cell.encode(ENCODE)).decode(
-            ENCODE
-        )
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.StreamInsertPageBreak(self.sw_index, cell.encode(ENCODE))
+        check_error_result(res)
 
     def merge_cell(self, top_left_cell: str, bottom_right_cell: str) -> None:
         """
@@ -718,14 +1472,40 @@ class StreamWriter:
                 argsRule("bottom_right_cell", [str]),
             ],
         )
-        lib.StreamMergeCell.restype = c_char_p
-        err = lib.StreamMergeCell(
+        res = lib.StreamMergeCell(
             self.sw_index,
             top_left_cell.encode(ENCODE),
             bottom_right_cell.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
+
+    def next_row(self) -> int:
+        """
+        Returns the number of the row after the last row set by the stream
+        writer. For the stream writer in append mode, it returns the row after
+        the last row of the worksheet if no row has been appended yet.
+
+        Returns:
+            int: Return the row number if no error occurred, otherwise raise a
+            RuntimeError with the message.
+
+        Example:
+            For example, append a row after the existing rows of the worksheet:
+
+            ```python
+            sw = f.new_stream_writer("Sheet1", append=True)
+            sw.set_row(f"A{sw.next_row()}", ["Total", 100])
+            sw.flush()
+            ```
+        """
+        res = lib.StreamNextRow(self.sw_index)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def set_col_outline_level(self, col: int, level: int) -> None:
         """
@@ -755,12 +1535,10 @@ class StreamWriter:
             [col, level],
             [argsRule("col", [int]), argsRule("level", [int])],
         )
-        lib.StreamSetColOutlineLevel.restype = c_char_p
-        err = lib.StreamSetColOutlineLevel(
+        res = lib.StreamSetColOutlineLevel(
             self.sw_index, c_longlong(col), c_longlong(level)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_col_width(
         self, start_col: int, end_col: int, width: Union[int, float]
@@ -797,12 +1575,10 @@ class StreamWriter:
                 argsRule("width", [int, float]),
             ],
         )
-        lib.StreamSetColWidth.restype = c_char_p
-        err = lib.StreamSetColWidth(
+        res = lib.StreamSetColWidth(
             self.sw_index, c_longlong(start_col), c_longlong(end_col), c_double(width)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_panes(self, opts: Panes) -> None:
         """
@@ -818,60 +1594,162 @@ class StreamWriter:
             RuntimeError with the message.
         """
         prepare_args([opts], [argsRule("opts", [Panes])])
-        lib.StreamSetPanes.restype = c_char_p
         options = py_value_to_c(opts, types_go._Panes())
-        err = lib.StreamSetPanes(self.sw_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.StreamSetPanes(self.sw_index, byref(options))
+        check_error_result(res)
 
     def set_row(
         self,
         cell: str,
-        values: List[Union[bool, float, int, str, date, datetime, None]],
+        values: List[
+            Union[
+                bool,
+                float,
+                int,
+                str,
+                date,
+                datetime,
+                timedelta,
+                None,
+                Cell,
+                List[RichTextRun],
+            ]
+        ],
+        *opts: RowOpts,
     ) -> None:
         """
         Writes an array to stream rows by giving starting cell reference and a
-        pointer to an array of values. Note that you must call the `flush`
-        function to end the streaming writing process.
+        pointer to an array of values. A value could be a `Cell` to specify the
+        style, the formula, and the value or the rich text runs of the cell, or
+        a list of `RichTextRun`. Note that you must call the `flush` function to
+        end the streaming writing process.
 
         Args:
             cell (str): The cell reference
-            values (List[Union[bool, float, int, str, date, datetime, None]]):
-            The cell values
+            values (List[Union[bool, float, int, str, date, datetime, timedelta,
+            None, Cell, List[RichTextRun]]]): The cell values
+            *opts (RowOpts): Optional parameters for setting the height, style,
+            visibility and outline level of the row
 
         Returns:
             None: Return None if no error occurred, otherwise raise a
             RuntimeError with the message.
+
+        Example:
+            For example, write a hidden row with the height of 30 points and the
+            outline level 1 in streaming mode:
+
+            ```python
+            sw.set_row(
+                "A2",
+                ["Group", 1],
+                excelize.RowOpts(height=30, hidden=True, outline_level=1),
+            )
+            ```
+
+            Write a row with a styled value, a rich text and a formula:
+
+            ```python
+            sw.set_row(
+                "A3",
+                [
+                    excelize.Cell(style_id=style_id, value=1024.5),
+                    [
+                        excelize.RichTextRun(
+                            text="Rich ", font=excelize.Font(bold=True)
+                        ),
+                        excelize.RichTextRun(text="Text"),
+                    ],
+                    excelize.Cell(formula="SUM(A1:A3)"),
+                ],
+            )
+            ```
         """
         prepare_args(
-            [cell, values],
-            [argsRule("cell", [str]), argsRule("values", [list])],
+            [cell, values, opts[0]] if opts else [cell, values],
+            [
+                argsRule("cell", [str]),
+                argsRule("values", [list]),
+                argsRule("opts", [RowOpts], True),
+            ],
         )
-        lib.StreamSetRow.restype = c_char_p
-        vals = (types_go._Interface * len(values))()
-        for i, value in enumerate(values):
-            vals[i] = py_value_to_c_interface(value)
-        err = lib.StreamSetRow(
-            self.sw_index,
-            cell.encode(ENCODE),
-            byref(vals),
-            len(vals),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        options = (
+            byref(py_value_to_c(opts[0], types_go._RowOpts()))
+            if opts
+            else POINTER(types_go._RowOpts)()
+        )
+        if any(isinstance(value, (Cell, list)) for value in values):
+            cells = (types_go._Cell * len(values))()
+            for i, value in enumerate(values):
+                cells[i] = py_value_to_c_cell(value)
+            res = lib.StreamSetRowCells(
+                self.sw_index,
+                cell.encode(ENCODE),
+                byref(cells),
+                len(cells),
+                options,
+            )
+        else:
+            vals = (types_go._Interface * len(values))()
+            for i, value in enumerate(values):
+                vals[i] = py_value_to_c_interface(value)
+            res = lib.StreamSetRow(
+                self.sw_index,
+                cell.encode(ENCODE),
+                byref(vals),
+                len(vals),
+                options,
+            )
+        check_error_result(res)
+
+    def discard(self) -> None:
+        """
+        Discard the stream writer without flushing it and release the stream
+        writer. The workbook keeps the stream writer of the worksheet until it
+        was closed, so the stream writer is replaced by an empty one which has
+        been flushed, and the worksheet will lose its rows, merged cells and
+        tables, as flushing the stream writer would. The column settings, panes
+        and page breaks set by the stream writer are kept, the table parts added
+        by it are left unused, and the temporary file which it created for the
+        rows over 16 MiB can't be removed by the excelize library. In append
+        mode, the appended rows are removed, and the tables added by the stream
+        writer are deleted if the worksheet didn't contain tables, but the other
+        settings set by the stream writer are kept.
+
+        Returns:
+            None: Return None if no error occurred, otherwise raise a
+            RuntimeError with the message.
+
+        Example:
+            For example, discard the stream writer if the rows failed to be
+            generated:
+
+            ```python
+            sw = f.new_stream_writer("Sheet1")
+            try:
+                for row, values in enumerate(generate_rows(), start=1):
+                    sw.set_row(f"A{row}", values)
+            except Exception:
+                sw.discard()
+                raise
+            sw.flush()
+            ```
+        """
+        res = lib.StreamDiscard(self.sw_index)
+        check_error_result(res)
 
     def flush(self) -> None:
         """
-        Ending the streaming writing process.
+        Ending the streaming writing process. The stream writer will be
+        released after it was flushed, if the flushing failed or was canceled,
+        the stream writer can be flushed again or discarded.
 
         Returns:
             None: Return None if no error occurred, otherwise raise a
             RuntimeError with the message.
         """
-        lib.StreamFlush.restype = c_char_p
-        err = lib.StreamFlush(self.sw_index).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.StreamFlush(self.sw_index)
+        check_error_result(res)
 
 
 class File:
@@ -880,9 +1758,11 @@ class File:
     """
 
     file_index: int
+    progress_callback: Optional[types_go._ProgressCallback]
 
     def __init__(self, file_index: int):
         self.file_index = file_index
+        self.progress_callback = None
 
     def save(self, *opts: Options) -> None:
         """
@@ -899,16 +1779,14 @@ class File:
             [opts[0]] if opts else [],
             [argsRule("opts", [Options], True)],
         )
-        err, lib.Save.restype = None, c_char_p
         options = POINTER(types_go._Options)()
         options = (
             byref(py_value_to_c(opts[0], types_go._Options()))
             if opts
             else POINTER(types_go._Options)()
         )
-        err = lib.Save(self.file_index, options).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.Save(self.file_index, options)
+        check_error_result(res)
 
     def save_as(self, filename: str, *opts: Options) -> None:
         """
@@ -926,17 +1804,13 @@ class File:
             [filename, opts[0]] if opts else [filename],
             [argsRule("filename", [str]), argsRule("opts", [Options], True)],
         )
-        lib.SaveAs.restype = c_char_p
         options = (
             byref(py_value_to_c(opts[0], types_go._Options()))
             if opts
             else POINTER(types_go._Options)()
         )
-        err = lib.SaveAs(self.file_index, filename.encode(ENCODE), options).decode(
-            ENCODE
-        )
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SaveAs(self.file_index, filename.encode(ENCODE), options)
+        check_error_result(res)
 
     def add_chart(self, sheet: str, cell: str, chart: Chart, **combo: Chart) -> None:
         """
@@ -1518,20 +2392,18 @@ class File:
                     print(err)
             ```
         """
-        lib.AddChart.restype = c_char_p
         opts = [chart] + list(combo.values())
         charts = (types_go._Chart * len(opts))()
         for i, opt in enumerate(opts):
             charts[i] = py_value_to_c(opt, types_go._Chart())
-        err = lib.AddChart(
+        res = lib.AddChart(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             byref(charts),
             len(charts),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_chart_sheet(self, sheet: str, chart: Chart, **combo: Chart) -> None:
         """
@@ -1548,19 +2420,17 @@ class File:
             None: Return None if no error occurred, otherwise raise a
             RuntimeError with the message.
         """
-        lib.AddChartSheet.restype = c_char_p
         opts = [chart] + list(combo.values())
         charts = (types_go._Chart * len(opts))()
         for i, opt in enumerate(opts):
             charts[i] = py_value_to_c(opt, types_go._Chart())
-        err = lib.AddChartSheet(
+        res = lib.AddChartSheet(
             self.file_index,
             sheet.encode(ENCODE),
             byref(charts),
             len(charts),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_comment(self, sheet: str, opts: Comment) -> None:
         """
@@ -1600,13 +2470,9 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [Comment])],
         )
-        lib.AddComment.restype = c_char_p
         options = py_value_to_c(opts, types_go._Comment())
-        err = lib.AddComment(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.AddComment(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def add_data_validation(self, sheet: str, dv: DataValidation) -> None:
         """
@@ -1683,13 +2549,11 @@ class File:
             [sheet, dv],
             [argsRule("sheet", [str]), argsRule("dv", [DataValidation])],
         )
-        lib.AddDataValidation.restype = c_char_p
         options = py_value_to_c(dv, types_go._DataValidation())
-        err = lib.AddDataValidation(
+        res = lib.AddDataValidation(
             self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_form_control(self, sheet: str, opts: FormControl) -> None:
         """
@@ -1821,13 +2685,9 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [FormControl])],
         )
-        lib.AddFormControl.restype = c_char_p
         options = py_value_to_c(opts, types_go._FormControl())
-        err = lib.AddFormControl(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.AddFormControl(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def add_header_footer_image(
         self, sheet: str, opts: HeaderFooterImageOptions
@@ -1855,14 +2715,12 @@ class File:
                 argsRule("opts", [HeaderFooterImageOptions]),
             ],
         )
-        lib.AddHeaderFooterImage.restype = c_char_p
-        err = lib.AddHeaderFooterImage(
+        res = lib.AddHeaderFooterImage(
             self.file_index,
             sheet.encode(ENCODE),
             byref(py_value_to_c(opts, types_go._HeaderFooterImageOptions())),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_ignored_errors(
         self, sheet: str, range_ref: str, ignored_errors_type: IgnoredErrorsType
@@ -1887,15 +2745,13 @@ class File:
                 argsRule("ignored_errors_type", [IgnoredErrorsType]),
             ],
         )
-        lib.AddIgnoredErrors.restype = c_char_p
-        err = lib.AddIgnoredErrors(
+        res = lib.AddIgnoredErrors(
             self.file_index,
             sheet.encode(ENCODE),
             range_ref.encode(ENCODE),
             c_longlong(ignored_errors_type),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_picture(
         self, sheet: str, cell: str, name: str, opts: Optional[GraphicOptions]
@@ -1968,21 +2824,19 @@ class File:
                 argsRule("opts", [GraphicOptions, type(None)]),
             ],
         )
-        lib.AddPicture.restype = c_char_p
         options = (
             byref(py_value_to_c(opts, types_go._GraphicOptions()))
             if opts
             else POINTER(types_go._GraphicOptions)()
         )
-        err = lib.AddPicture(
+        res = lib.AddPicture(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             name.encode(ENCODE),
             options,
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_picture_from_bytes(self, sheet: str, cell: str, picture: Picture) -> None:
         """
@@ -2042,15 +2896,13 @@ class File:
                 argsRule("picture", [Picture]),
             ],
         )
-        lib.AddPictureFromBytes.restype = c_char_p
-        err = lib.AddPictureFromBytes(
+        res = lib.AddPictureFromBytes(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             byref(py_value_to_c(picture, types_go._Picture())),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_pivot_table(self, opts: Optional[PivotTableOptions]) -> None:
         """
@@ -2128,13 +2980,11 @@ class File:
             [opts],
             [argsRule("opts", [PivotTableOptions, type(None)])],
         )
-        lib.AddPivotTable.restype = c_char_p
-        err = lib.AddPivotTable(
+        res = lib.AddPivotTable(
             self.file_index,
             byref(py_value_to_c(opts, types_go._PivotTableOptions())),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def add_shape(self, sheet: str, opts: Shape) -> None:
         """
@@ -2418,13 +3268,9 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [Shape])],
         )
-        lib.AddShape.restype = c_char_p
         options = py_value_to_c(opts, types_go._Shape())
-        err = lib.AddShape(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.AddShape(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def add_slicer(self, sheet: str, opts: SlicerOptions) -> None:
         """
@@ -2464,13 +3310,9 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [SlicerOptions])],
         )
-        lib.AddSlicer.restype = c_char_p
         options = py_value_to_c(opts, types_go._SlicerOptions())
-        err = lib.AddSlicer(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.AddSlicer(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def add_sparkline(self, sheet: str, opts: SparklineOptions) -> None:
         """
@@ -2529,13 +3371,9 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [SparklineOptions])],
         )
-        lib.AddSparkline.restype = c_char_p
         options = py_value_to_c(opts, types_go._SparklineOptions())
-        err = lib.AddSparkline(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.AddSparkline(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def add_table(self, sheet: str, table: Table) -> None:
         """
@@ -2569,13 +3407,9 @@ class File:
             [sheet, table],
             [argsRule("sheet", [str]), argsRule("table", [Table])],
         )
-        lib.AddTable.restype = c_char_p
         options = py_value_to_c(table, types_go._Table())
-        err = lib.AddTable(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.AddTable(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def add_vba_project(self, file: bytes) -> None:
         """
@@ -2590,14 +3424,12 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([file], [argsRule("file", [bytes])])
-        lib.AddVBAProject.restype = c_char_p
-        err = lib.AddVBAProject(
+        res = lib.AddVBAProject(
             self.file_index,
             cast(file, POINTER(c_ubyte)),
             len(file),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def auto_filter(
         self,
@@ -2635,19 +3467,17 @@ class File:
                 argsRule("opts", [list]),
             ],
         )
-        lib.AutoFilter.restype = c_char_p
         options = (types_go._AutoFilterOptions * len(opts))()
         for i, opt in enumerate(opts):
             options[i] = py_value_to_c(opt, types_go._AutoFilterOptions())
-        err = lib.AutoFilter(
+        res = lib.AutoFilter(
             self.file_index,
             sheet.encode(ENCODE),
             range_ref.encode(ENCODE),
             byref(options),
             len(options),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def auto_fit_col_width(self, sheet: str, columns: str) -> None:
         """
@@ -2691,16 +3521,20 @@ class File:
             [sheet, columns],
             [argsRule("sheet", [str]), argsRule("columns", [str])],
         )
-        lib.AutoFitColWidth.restype = c_char_p
-        err = lib.AutoFitColWidth(
+        res = lib.AutoFitColWidth(
             self.file_index,
             sheet.encode(ENCODE),
             columns.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
-    def calc_cell_value(self, sheet: str, cell: str, *opts: Options) -> str:
+    def calc_cell_value(
+        self,
+        sheet: str,
+        cell: str,
+        *opts: Options,
+        timeout: Optional[Union[int, float]] = None,
+    ) -> str:
         """
         Get calculated cell value. This feature is currently in working
         processing. Iterative calculation, implicit intersection, explicit
@@ -3169,10 +4003,29 @@ class File:
             sheet (str): The worksheet name
             cell (str): The cell reference
             *opts (Options): Optional parameters for calculate cell value
+            timeout (Optional[Union[int, float]]): Optional timeout of the
+            calculation in seconds, 0 means no timeout. A TimeoutExceededError
+            will be raised once it has elapsed. The interrupted calculation
+            can not be stopped, it keeps running in the background until it
+            returns, and the functions modifying the workbook wait for it. The
+            number of the calculations running in the background is limited
+            by the number of CPUs.
 
         Returns:
             str: Return the calculation result as a string if no
             error occurred, otherwise raise a RuntimeError with the message.
+            Raise a CanceledError if the calculation with the timeout has
+            been canceled by the `cancel_calc` function.
+
+        Example:
+            For example, calculate the cell value with a timeout of 5 seconds:
+
+            ```python
+            try:
+                value = f.calc_cell_value("Sheet1", "A1", timeout=5)
+            except excelize.TimeoutExceededError:
+                print("calculation timed out")
+            ```
         """
         prepare_args(
             [sheet, cell, opts[0]] if opts else [sheet, cell],
@@ -3182,19 +4035,60 @@ class File:
                 argsRule("opts", [Options], opts=True),
             ],
         )
-        lib.CalcCellValue.restype = types_go._StringErrorResult
+        if timeout is not None:
+            prepare_args([timeout], [argsRule("timeout", [int, float])])
         options = (
             byref(py_value_to_c(opts[0], types_go._Options()))
             if opts
             else POINTER(types_go._Options)()
         )
-        res = lib.CalcCellValue(
-            self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE), options
-        )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val.decode(ENCODE)
-        raise RuntimeError(err)
+        if timeout is None:
+            res = lib.CalcCellValue(
+                self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE), options
+            )
+        else:
+            res = lib.CalcCellValueWithTimeout(
+                self.file_index,
+                sheet.encode(ENCODE),
+                cell.encode(ENCODE),
+                options,
+                max(round(timeout * 1000), 1) if timeout > 0 else round(timeout),
+            )
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val.decode(ENCODE)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def cancel_calc(self) -> None:
+        """
+        Cancel the calculations with the timeout in flight on the workbook,
+        which may be called from other threads. The canceled calculations raise
+        a CanceledError, and the calculations started after it will not be
+        affected.
+
+        Returns:
+            None: Return None if no error occurred, otherwise raise a
+            RuntimeError with the message.
+
+        Example:
+            For example, cancel the calculation after 5 seconds:
+
+            ```python
+            timer = threading.Timer(5, f.cancel_calc)
+            timer.start()
+            try:
+                value = f.calc_cell_value("Sheet1", "A1", timeout=0)
+            except excelize.CanceledError:
+                print("calculation canceled")
+            finally:
+                timer.cancel()
+            ```
+        """
+        res = lib.CancelCalc(self.file_index)
+        check_error_result(res)
 
     def close(self) -> Optional[Exception]:
         """
@@ -3204,9 +4098,97 @@ class File:
             Optional[Exception]: Returns None if no error occurred,
             otherwise returns an Exception with the message.
         """
-        err, lib.Close.restype = None, c_char_p
-        err = lib.Close(self.file_index).decode(ENCODE)
-        return None if err == "" else Exception(err)
+        res = lib.Close(self.file_index)
+        try:
+            err = res.err.decode(ENCODE)
+            return None if err == "" else new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def call_json(self, method: str, *args: Any) -> Any:
+        """
+        Call the method of the workbook by given method name, with the
+        arguments encoded as JSON. The arguments are decoded into the parameter
+        types of the method in the excelize library, such as `Chart`, `Style`
+        and `PivotTableOptions`, so the fields added in the excelize library can
+        be used without the declarations of the types. The data classes,
+        dictionaries with the field names in PascalCase, and the basic data
+        types can be used as the arguments.
+
+        Args:
+            method (str): The method name of the workbook in the excelize
+            library, for example: "SetCellValue"
+            *args (Any): The arguments of the method
+
+        Returns:
+            Any: Return the results of the method decoded from JSON if no error
+            occurred, otherwise raise a RuntimeError with the message. It
+            returns None if the method has no results, the value if it has one
+            result, or a list of the results. The structures are returned as
+            dictionaries with the field names in PascalCase.
+
+        Example:
+            For example, create a style and get the number format of it:
+
+            ```python
+            f = excelize.new_file()
+            try:
+                style = f.call_json("NewStyle", excelize.Style(num_fmt=2))
+                print(f.call_json("GetStyle", style)["NumFmt"])
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args([method], [argsRule("method", [str])])
+        res = lib.CallJSON(
+            self.file_index,
+            method.encode(ENCODE),
+            json.dumps(py_value_to_json(list(args))).encode(ENCODE),
+        )
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return json.loads(res.val.decode(ENCODE))
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def cols(self, sheet: str) -> Cols:
+        """
+        Returns a columns iterator, used for streaming reading data for a
+        worksheet with a large data.
+
+        Args:
+            sheet (str): The worksheet name
+
+        Returns:
+            Cols: Return the columns iterator object if no error occurred,
+            otherwise raise a RuntimeError with the message.
+
+        Example:
+            For example:
+
+            ```python
+            try:
+                cols = f.cols("Sheet1")
+                while cols.next():
+                    for cell in cols.rows():
+                        print(f"{cell}\\t", end="")
+                    print()
+                cols.close()
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args([sheet], [argsRule("sheet", [str])])
+        res = lib.Cols(self.file_index, sheet.encode(ENCODE))
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return Cols(res.val)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def copy_sheet(self, src: int, to: int) -> None:
         """
@@ -3235,12 +4217,8 @@ class File:
             ```
         """
         prepare_args([src, to], [argsRule("src", [int]), argsRule("to", [int])])
-        err, lib.CopySheet.restype = None, c_char_p
-        err = lib.CopySheet(self.file_index, c_longlong(src), c_longlong(to)).decode(
-            ENCODE
-        )
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.CopySheet(self.file_index, c_longlong(src), c_longlong(to))
+        check_error_result(res)
 
     def delete_chart(self, sheet: str, cell: str) -> None:
         """
@@ -3258,12 +4236,10 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        err, lib.DeleteChart.restype = None, c_char_p
-        err = lib.DeleteChart(
+        res = lib.DeleteChart(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def delete_comment(self, sheet: str, cell: str) -> None:
         """
@@ -3288,12 +4264,10 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        err, lib.DeleteComment.restype = None, c_char_p
-        err = lib.DeleteComment(
+        res = lib.DeleteComment(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def delete_defined_name(self, defined_name: DefinedName) -> None:
         """
@@ -3321,11 +4295,9 @@ class File:
             ```
         """
         prepare_args([defined_name], [argsRule("defined_name", [DefinedName])])
-        lib.DeleteDefinedName.restype = c_char_p
         options = py_value_to_c(defined_name, types_go._DefinedName())
-        err = lib.DeleteDefinedName(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.DeleteDefinedName(self.file_index, byref(options))
+        check_error_result(res)
 
     def delete_form_control(self, sheet: str, cell: str) -> None:
         """
@@ -3354,12 +4326,10 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        err, lib.DeleteFormControl.restype = None, c_char_p
-        err = lib.DeleteFormControl(
+        res = lib.DeleteFormControl(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def delete_picture(self, sheet: str, cell: str) -> None:
         """
@@ -3377,12 +4347,10 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        err, lib.DeletePicture.restype = None, c_char_p
-        err = lib.DeletePicture(
+        res = lib.DeletePicture(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def delete_sheet(self, sheet: str) -> None:
         """
@@ -3400,10 +4368,8 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        err, lib.DeleteSheet.restype = None, c_char_p
-        err = lib.DeleteSheet(self.file_index, sheet.encode(ENCODE)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.DeleteSheet(self.file_index, sheet.encode(ENCODE))
+        check_error_result(res)
 
     def delete_slicer(self, name: str) -> None:
         """
@@ -3417,10 +4383,8 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([name], [argsRule("name", [str])])
-        err, lib.DeleteSlicer.restype = None, c_char_p
-        err = lib.DeleteSlicer(self.file_index, name.encode(ENCODE)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.DeleteSlicer(self.file_index, name.encode(ENCODE))
+        check_error_result(res)
 
     def duplicate_row(self, sheet: str, row: int) -> None:
         """
@@ -3452,12 +4416,8 @@ class File:
             [sheet, row],
             [argsRule("sheet", [str]), argsRule("row", [int])],
         )
-        err, lib.DuplicateRow.restype = None, c_char_p
-        err = lib.DuplicateRow(
-            self.file_index, sheet.encode(ENCODE), c_longlong(row)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.DuplicateRow(self.file_index, sheet.encode(ENCODE), c_longlong(row))
+        check_error_result(res)
 
     def duplicate_row_to(self, sheet: str, row: int, row2: int) -> None:
         """
@@ -3495,12 +4455,10 @@ class File:
                 argsRule("row2", [int]),
             ],
         )
-        err, lib.DuplicateRowTo.restype = None, c_char_p
-        err = lib.DuplicateRowTo(
+        res = lib.DuplicateRowTo(
             self.file_index, sheet.encode(ENCODE), c_longlong(row), c_longlong(row2)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def get_active_sheet_index(self) -> int:
         """
@@ -3510,7 +4468,6 @@ class File:
         Returns:
             int: The active sheet index
         """
-        lib.GetActiveSheetIndex.restype = c_longlong
         res = lib.GetActiveSheetIndex(self.file_index)
         return res
 
@@ -3522,12 +4479,14 @@ class File:
             Optional[AppProperties]: Return the app properties if no error
             occurred, otherwise raise a RuntimeError with the message.
         """
-        lib.GetAppProps.restype = types_go._GetAppPropsResult
         res = lib.GetAppProps(self.file_index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, AppProperties())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, AppProperties())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_calc_props(self) -> Optional[CalcPropsOptions]:
         """
@@ -3537,12 +4496,14 @@ class File:
             Optional[CalcPropsOptions]: Return the calculation properties if no
             error occurred, otherwise raise a RuntimeError with the message.
         """
-        lib.GetCalcProps.restype = types_go._GetCalcPropsResult
         res = lib.GetCalcProps(self.file_index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, CalcPropsOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, CalcPropsOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_cell_formula(self, sheet: str, cell: str) -> str:
         """
@@ -3561,14 +4522,16 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        lib.GetCellFormula.restype = types_go._StringErrorResult
         res = lib.GetCellFormula(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val.decode(ENCODE)
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val.decode(ENCODE)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_cell_hyperlink(self, sheet: str, cell: str) -> Tuple[bool, str]:
         """
@@ -3600,17 +4563,19 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        lib.GetCellHyperLink.restype = types_go._GetCellHyperLinkResult
         res = lib.GetCellHyperLink(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return (
-                res.link,
-                res.target.decode(ENCODE),
-            )
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return (
+                    res.link,
+                    res.target.decode(ENCODE),
+                )
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_cell_rich_text(self, sheet: str, cell: str) -> List[RichTextRun]:
         """
@@ -3628,15 +4593,17 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        lib.GetCellRichText.restype = types_go._GetCellRichTextResult
         res = lib.GetCellRichText(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
         )
-        runs = c_value_to_py(res, GetCellRichTextResult()).runs
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return runs if runs else []
-        raise RuntimeError(err)
+        try:
+            runs = c_value_to_py(res, GetCellRichTextResult()).runs
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return runs if runs else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_cell_style(self, sheet: str, cell: str) -> int:
         """
@@ -3654,14 +4621,91 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        lib.GetCellStyle.restype = types_go._IntErrorResult
         res = lib.GetCellStyle(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def get_cell_type(self, sheet: str, cell: str) -> CellType:
+        """
+        Get the cell's data type by given worksheet name and cell reference in
+        spreadsheet file.
+
+        Args:
+            sheet (str): The worksheet name
+            cell (str): The cell reference
+
+        Returns:
+            CellType: Return the cell type if no error occurred, otherwise raise
+            a RuntimeError with the message.
+        """
+        prepare_args(
+            [sheet, cell],
+            [argsRule("sheet", [str]), argsRule("cell", [str])],
+        )
+        res = lib.GetCellType(
+            self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
+        )
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return CellType(res.val)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def get_cell_typed_value(
+        self, sheet: str, cell: str
+    ) -> Union[bool, float, str, datetime, None]:
+        """
+        Get typed value from cell by given worksheet name and cell reference in
+        spreadsheet. The value is built from the cell type and the raw value of
+        the cell: the number cell returns a `float`, or a `datetime` if the
+        number format of the cell is a date or time number format, the boolean
+        cell returns a `bool`, the date cell returns a `datetime`, the empty
+        cell returns `None`, the error cell returns a `CellError` with the error
+        code, the formula string cell returns a `CellFormula` with the formula
+        result, and other cells return a `str`.
+
+        Args:
+            sheet (str): The worksheet name
+            cell (str): The cell reference
+
+        Returns:
+            Union[bool, float, str, datetime, None]: Return the typed cell value
+            if no error occurred, otherwise raise a RuntimeError with the
+            message.
+
+        Example:
+            For example, get the typed value of cell `A1` on `Sheet1`:
+
+            ```python
+            try:
+                value = f.get_cell_typed_value("Sheet1", "A1")
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args(
+            [sheet, cell],
+            [argsRule("sheet", [str]), argsRule("cell", [str])],
+        )
+        res = lib.GetCellTypedValue(
+            self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
+        )
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py_interface(res.val)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_cell_value(self, sheet: str, cell: str, *opts: Options) -> str:
         """
@@ -3688,7 +4732,6 @@ class File:
                 argsRule("opts", [Options], True),
             ],
         )
-        lib.GetCellValue.restype = types_go._StringErrorResult
         options = (
             byref(py_value_to_c(opts[0], types_go._Options()))
             if opts
@@ -3697,10 +4740,13 @@ class File:
         res = lib.GetCellValue(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE), options
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val.decode(ENCODE)
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val.decode(ENCODE)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_col_outline_level(self, sheet: str, col: str) -> int:
         """
@@ -3729,14 +4775,16 @@ class File:
             [sheet, col],
             [argsRule("sheet", [str]), argsRule("col", [str])],
         )
-        lib.GetColOutlineLevel.restype = types_go._IntErrorResult
         res = lib.GetColOutlineLevel(
             self.file_index, sheet.encode(ENCODE), col.encode(ENCODE)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_col_style(self, sheet: str, col: str) -> int:
         """
@@ -3754,12 +4802,14 @@ class File:
             [sheet, col],
             [argsRule("sheet", [str]), argsRule("col", [str])],
         )
-        lib.GetColStyle.restype = types_go._IntErrorResult
         res = lib.GetColStyle(self.file_index, sheet.encode(ENCODE), col.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_col_visible(self, sheet: str, col: str) -> bool:
         """
@@ -3787,14 +4837,16 @@ class File:
             [sheet, col],
             [argsRule("sheet", [str]), argsRule("col", [str])],
         )
-        lib.GetColVisible.restype = types_go._BoolErrorResult
         res = lib.GetColVisible(
             self.file_index, sheet.encode(ENCODE), col.encode(ENCODE)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_col_width(self, sheet: str, col: str) -> float:
         """
@@ -3822,12 +4874,14 @@ class File:
             [sheet, col],
             [argsRule("sheet", [str]), argsRule("col", [str])],
         )
-        lib.GetColWidth.restype = types_go._Float64ErrorResult
         res = lib.GetColWidth(self.file_index, sheet.encode(ENCODE), col.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_cols(self, sheet: str, *opts: Options) -> List[List[str]]:
         """
@@ -3862,7 +4916,6 @@ class File:
             [sheet, opts[0]] if opts else [sheet],
             [argsRule("sheet", [str]), argsRule("opts", [Options], True)],
         )
-        lib.GetCols.restype = types_go._StringMatrixErrorResult
         cols = []
         options = (
             byref(py_value_to_c(opts[0], types_go._Options()))
@@ -3870,14 +4923,17 @@ class File:
             else POINTER(types_go._Options)()
         )
         res = lib.GetCols(self.file_index, sheet.encode(ENCODE), options)
-        err = res.err.decode(ENCODE)
-        result = c_value_to_py(res, StringMatrixErrorResult()).row
-        if result:
-            for row in result:
-                cols.append([cell for cell in row.cell] if row.cell else [])
-        if not err:
-            return cols
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            result = c_value_to_py(res, StringMatrixErrorResult()).row
+            if result:
+                for row in result:
+                    cols.append([cell for cell in row.cell] if row.cell else [])
+            if not err:
+                return cols
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_comments(self, sheet: str) -> List[Comment]:
         """
@@ -3889,14 +4945,16 @@ class File:
             otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetComments.restype = types_go._GetCommentsResult
         res = lib.GetComments(self.file_index, sheet.encode(ENCODE))
-        result = c_value_to_py(res, GetCommentsResult())
-        if res.Err:
-            err = res.Err.decode(ENCODE)
-            if err:
-                raise RuntimeError(err)
-        return result.comments if result and result.comments else []
+        try:
+            result = c_value_to_py(res, GetCommentsResult())
+            if res.Err:
+                err = res.Err.decode(ENCODE)
+                if err:
+                    raise new_error(err, res.ErrCode)
+            return result.comments if result and result.comments else []
+        finally:
+            free_result(res)
 
     def get_custom_props(self) -> List[CustomProperty]:
         """
@@ -3906,21 +4964,23 @@ class File:
             List[CustomProperty]: Return the custom file properties if no error
             occurred, otherwise raise a RuntimeError with the message.
         """
-        lib.GetCustomProps.restype = types_go._GetCustomPropsResult
         res = lib.GetCustomProps(self.file_index)
-        err = res.Err.decode(ENCODE)
-        if err == "":
-            arr = []
-            if res.CustomProps:
-                for i in range(res.CustomPropsLen):
-                    arr.append(
-                        CustomProperty(
-                            name=res.CustomProps[i].Name.decode(ENCODE),
-                            value=c_value_to_py_interface(res.CustomProps[i].Value),
+        try:
+            err = res.Err.decode(ENCODE)
+            if err == "":
+                arr = []
+                if res.CustomProps:
+                    for i in range(res.CustomPropsLen):
+                        arr.append(
+                            CustomProperty(
+                                name=res.CustomProps[i].Name.decode(ENCODE),
+                                value=c_value_to_py_interface(res.CustomProps[i].Value),
+                            )
                         )
-                    )
-            return arr
-        raise RuntimeError(err)
+                return arr
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_data_validations(self, sheet: str) -> List[DataValidation]:
         """
@@ -3934,13 +4994,15 @@ class File:
             otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetDataValidations.restype = types_go._GetDataValidationsResult
         res = lib.GetDataValidations(self.file_index, sheet.encode(ENCODE))
-        dvs = c_value_to_py(res, GetDataValidationsResult()).dvs
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return dvs if dvs else []
-        raise RuntimeError(err)
+        try:
+            dvs = c_value_to_py(res, GetDataValidationsResult()).dvs
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return dvs if dvs else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_default_font(self) -> str:
         """
@@ -3951,12 +5013,14 @@ class File:
             str: Return the default font name if no error occurred, otherwise
             raise a RuntimeError with the message.
         """
-        lib.GetDefaultFont.restype = types_go._StringErrorResult
         res = lib.GetDefaultFont(self.file_index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val.decode(ENCODE)
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val.decode(ENCODE)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_defined_name(self) -> List[DefinedName]:
         """
@@ -3978,16 +5042,18 @@ class File:
                 print(err)
             ```
         """
-        lib.GetDefinedName.restype = types_go._GetDefinedNameResult
         res = lib.GetDefinedName(self.file_index)
-        err = res.Err.decode(ENCODE)
-        if err == "":
-            arr = []
-            if res.DefinedNames:
-                for i in range(res.DefinedNamesLen):
-                    arr.append(c_value_to_py(res.DefinedNames[i], DefinedName()))
-            return arr
-        raise RuntimeError(err)
+        try:
+            err = res.Err.decode(ENCODE)
+            if err == "":
+                arr = []
+                if res.DefinedNames:
+                    for i in range(res.DefinedNamesLen):
+                        arr.append(c_value_to_py(res.DefinedNames[i], DefinedName()))
+                return arr
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_doc_props(self) -> DocProperties:
         """
@@ -3997,12 +5063,14 @@ class File:
             DocProperties: Return the document core properties if no error
             occurred, otherwise raise a RuntimeError with the message.
         """
-        lib.GetDocProps.restype = types_go._GetDocPropsResult
         res = lib.GetDocProps(self.file_index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, DocProperties())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, DocProperties())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_form_controls(self, sheet: str) -> List[FormControl]:
         """
@@ -4018,16 +5086,18 @@ class File:
             occurred, otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetFormControls.restype = types_go._GetFormControlsResult
         res = lib.GetFormControls(self.file_index, sheet.encode(ENCODE))
-        err = res.Err.decode(ENCODE)
-        if err == "":
-            arr = []
-            if res.FormControls:
-                for i in range(res.FormControlsLen):
-                    arr.append(c_value_to_py(res.FormControls[i], FormControl()))
-            return arr
-        raise RuntimeError(err)
+        try:
+            err = res.Err.decode(ENCODE)
+            if err == "":
+                arr = []
+                if res.FormControls:
+                    for i in range(res.FormControlsLen):
+                        arr.append(c_value_to_py(res.FormControls[i], FormControl()))
+                return arr
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_hyperlink_cells(self, sheet: str, link_type: str) -> List[str]:
         """
@@ -4050,17 +5120,19 @@ class File:
             [sheet, link_type],
             [argsRule("sheet", [str]), argsRule("link_type", [str])],
         )
-        lib.GetHyperLinkCells.restype = types_go._StringArrayErrorResult
         res = lib.GetHyperLinkCells(
             self.file_index,
             sheet.encode(ENCODE),
             link_type.encode(ENCODE),
         )
-        arr = c_value_to_py(res, StringArrayErrorResult()).arr
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return arr if arr else []
-        raise RuntimeError(err)
+        try:
+            arr = c_value_to_py(res, StringArrayErrorResult()).arr
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return arr if arr else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_merge_cells(self, sheet: str, *without_values: bool) -> List[MergeCell]:
         """
@@ -4094,22 +5166,24 @@ class File:
             [sheet, without_values[0]] if without_values else [sheet],
             [argsRule("sheet", [str]), argsRule("without_values", [bool], True)],
         )
-        lib.GetMergeCells.restype = types_go._StringMatrixErrorResult
         merge_cells = []
         res = lib.GetMergeCells(
             self.file_index,
             sheet.encode(ENCODE),
             without_values[0] if without_values else False,
         )
-        err = res.err.decode(ENCODE)
-        result = c_value_to_py(res, StringMatrixErrorResult()).row
-        if result:
-            for row in result:
-                if row.cell:
-                    merge_cells.append(MergeCell([cell for cell in row.cell]))
-        if not err:
-            return merge_cells
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            result = c_value_to_py(res, StringMatrixErrorResult()).row
+            if result:
+                for row in result:
+                    if row.cell:
+                        merge_cells.append(MergeCell([cell for cell in row.cell]))
+            if not err:
+                return merge_cells
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_page_layout(self, sheet: str) -> PageLayoutOptions:
         """
@@ -4120,12 +5194,14 @@ class File:
             occurred, otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetPageLayout.restype = types_go._GetPageLayoutResult
         res = lib.GetPageLayout(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, PageLayoutOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, PageLayoutOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_page_margins(self, sheet: str) -> PageLayoutMarginsOptions:
         """
@@ -4136,12 +5212,14 @@ class File:
             error occurred, otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetPageMargins.restype = types_go._GetPageMarginsResult
         res = lib.GetPageMargins(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, PageLayoutMarginsOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, PageLayoutMarginsOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_pictures(self, sheet: str, cell: str) -> List[Picture]:
         """
@@ -4180,15 +5258,17 @@ class File:
             ```
         """
         prepare_args([sheet, cell], [argsRule("sheet", [str]), argsRule("cell", [str])])
-        lib.GetPictures.restype = types_go._GetPicturesResult
         res = lib.GetPictures(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
         )
-        pics = c_value_to_py(res, GetPicturesResult()).pictures
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return pics if pics else []
-        raise RuntimeError(err)
+        try:
+            pics = c_value_to_py(res, GetPicturesResult()).pictures
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return pics if pics else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_pivot_tables(self, sheet: str) -> List[PivotTableOptions]:
         """
@@ -4205,13 +5285,74 @@ class File:
             occurred, otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetPivotTables.restype = types_go._GetPivotTablesResult
         res = lib.GetPivotTables(self.file_index, sheet.encode(ENCODE))
-        pivot_tables = c_value_to_py(res, GetPivotTablesResult()).pivot_tables
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return pivot_tables if pivot_tables else []
-        raise RuntimeError(err)
+        try:
+            pivot_tables = c_value_to_py(res, GetPivotTablesResult()).pivot_tables
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return pivot_tables if pivot_tables else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def get_range(
+        self, sheet: str, range_ref: str, *opts: Options
+    ) -> List[List[str]]:
+        """
+        Get the values of the cells in a range by given worksheet name and range
+        reference, for example `C10:H5000`, or a defined name which refers to a
+        range. The whole column reference such as `A:C` and the whole row
+        reference such as `1:3` are clamped to the used range of the worksheet.
+        The result is a rectangle matrix of the range, and the value of each
+        cell is resolved as the `get_cell_value` function does, including the
+        merged cells and the `raw_cell_value` option.
+
+        Args:
+            sheet (str): The worksheet name
+            range_ref (str): The range reference or the defined name
+            *opts (Options): Optional parameters for get cell value
+
+        Returns:
+            List[List[str]]: Return the values of the cells in the range if no
+            error occurred, otherwise raise a RuntimeError with the message.
+
+        Example:
+            For example, get the values in range `C10:H5000` on `Sheet1`:
+
+            ```python
+            try:
+                rows = f.get_range("Sheet1", "C10:H5000")
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args(
+            [sheet, range_ref, opts[0]] if opts else [sheet, range_ref],
+            [
+                argsRule("sheet", [str]),
+                argsRule("range_ref", [str]),
+                argsRule("opts", [Options], True),
+            ],
+        )
+        options = (
+            byref(py_value_to_c(opts[0], types_go._Options()))
+            if opts
+            else POINTER(types_go._Options)()
+        )
+        res = lib.GetRange(
+            self.file_index,
+            sheet.encode(ENCODE),
+            range_ref.encode(ENCODE),
+            options,
+        )
+        try:
+            err = res.err.decode(ENCODE)
+            if err:
+                raise new_error(err, res.ErrCode)
+            result = c_value_to_py(res, StringMatrixErrorResult()).row
+            return [row.cell if row.cell else [] for row in result or []]
+        finally:
+            free_result(res)
 
     def get_row_height(self, sheet: str, row: int) -> float:
         """
@@ -4239,12 +5380,14 @@ class File:
             [sheet, row],
             [argsRule("sheet", [str]), argsRule("row", [int])],
         )
-        lib.GetRowHeight.restype = types_go._Float64ErrorResult
         res = lib.GetRowHeight(self.file_index, sheet.encode(ENCODE), c_longlong(row))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_row_outline_level(self, sheet: str, row: int) -> int:
         """
@@ -4273,14 +5416,16 @@ class File:
             [sheet, row],
             [argsRule("sheet", [str]), argsRule("row", [int])],
         )
-        lib.GetRowOutlineLevel.restype = types_go._IntErrorResult
         res = lib.GetRowOutlineLevel(
             self.file_index, sheet.encode(ENCODE), c_longlong(row)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_row_visible(self, sheet: str, row: int) -> bool:
         """
@@ -4308,12 +5453,14 @@ class File:
             [sheet, row],
             [argsRule("sheet", [str]), argsRule("row", [int])],
         )
-        lib.GetRowVisible.restype = types_go._BoolErrorResult
         res = lib.GetRowVisible(self.file_index, sheet.encode(ENCODE), c_longlong(row))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_rows(self, sheet: str, *opts: Options) -> List[List[str]]:
         """
@@ -4350,7 +5497,6 @@ class File:
             [sheet, opts[0]] if opts else [sheet],
             [argsRule("sheet", [str]), argsRule("opts", [Options], True)],
         )
-        lib.GetRows.restype = types_go._StringMatrixErrorResult
         rows = []
         options = (
             byref(py_value_to_c(opts[0], types_go._Options()))
@@ -4358,14 +5504,17 @@ class File:
             else POINTER(types_go._Options)()
         )
         res = lib.GetRows(self.file_index, sheet.encode(ENCODE), options)
-        err = res.err.decode(ENCODE)
-        result = c_value_to_py(res, StringMatrixErrorResult()).row
-        if result:
-            for row in result:
-                rows.append([cell for cell in row.cell] if row.cell else [])
-        if not err:
-            return rows
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            result = c_value_to_py(res, StringMatrixErrorResult()).row
+            if result:
+                for row in result:
+                    rows.append([cell for cell in row.cell] if row.cell else [])
+            if not err:
+                return rows
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_dimension(self, sheet: str) -> str:
         """
@@ -4379,12 +5528,14 @@ class File:
             raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetSheetDimension.restype = types_go._StringErrorResult
         res = lib.GetSheetDimension(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val.decode(ENCODE)
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val.decode(ENCODE)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_index(self, sheet: str) -> int:
         """
@@ -4400,12 +5551,14 @@ class File:
             a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetSheetIndex.restype = types_go._IntErrorResult
         res = lib.GetSheetIndex(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_list(self) -> List[str]:
         """
@@ -4416,10 +5569,12 @@ class File:
             List[str]: Return the sheet name list if no error occurred,
             otherwise return an empty list.
         """
-        lib.GetSheetList.restype = types_go._StringArrayErrorResult
         res = lib.GetSheetList(self.file_index)
-        arr = c_value_to_py(res, StringArrayErrorResult()).arr
-        return arr if arr else []
+        try:
+            arr = c_value_to_py(res, StringArrayErrorResult()).arr
+            return arr if arr else []
+        finally:
+            free_result(res)
 
     def get_sheet_map(self) -> Dict[int, str]:
         """
@@ -4450,14 +5605,16 @@ class File:
                     print(err)
             ```
         """
-        lib.GetSheetMap.restype = types_go._GetSheetMapResult
         sheet_map = dict()
         res = lib.GetSheetMap(self.file_index)
-        result = c_value_to_py(res, GetSheetMapResult()).arr
-        if result:
-            for item in result:
-                sheet_map[item.k] = item.v
-        return sheet_map
+        try:
+            result = c_value_to_py(res, GetSheetMapResult()).arr
+            if result:
+                for item in result:
+                    sheet_map[item.k] = item.v
+            return sheet_map
+        finally:
+            free_result(res)
 
     def get_sheet_name(self, sheet: int) -> str:
         """
@@ -4473,12 +5630,14 @@ class File:
             error occurred, otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [int])])
-        lib.GetSheetName.restype = types_go._StringErrorResult
         res = lib.GetSheetName(self.file_index, c_longlong(sheet))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val.decode(ENCODE)
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val.decode(ENCODE)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_props(self, sheet: str) -> Optional[SheetPropsOptions]:
         """
@@ -4492,12 +5651,14 @@ class File:
             error occurred, otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetSheetProps.restype = types_go._GetSheetPropsResult
         res = lib.GetSheetProps(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, SheetPropsOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, SheetPropsOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_protection(self, sheet: str) -> Optional[SheetProtectionOptions]:
         """
@@ -4513,12 +5674,14 @@ class File:
             the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetSheetProtection.restype = types_go._GetSheetProtectionResult
         res = lib.GetSheetProtection(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, SheetProtectionOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, SheetProtectionOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_view(self, sheet: str, view_index: int) -> Optional[ViewOptions]:
         """
@@ -4536,14 +5699,16 @@ class File:
             [sheet, view_index],
             [argsRule("sheet", [str]), argsRule("view_index", [int])],
         )
-        lib.GetSheetView.restype = types_go._GetSheetViewResult
         res = lib.GetSheetView(
             self.file_index, sheet.encode(ENCODE), c_longlong(view_index)
         )
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, ViewOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, ViewOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_sheet_visible(self, sheet: str) -> bool:
         """
@@ -4567,12 +5732,14 @@ class File:
             ```
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetSheetVisible.restype = types_go._BoolErrorResult
         res = lib.GetSheetVisible(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_slicers(self, sheet: str) -> List[SlicerOptions]:
         """
@@ -4588,13 +5755,15 @@ class File:
             otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetSlicers.restype = types_go._GetSlicersResult
         res = lib.GetSlicers(self.file_index, sheet.encode(ENCODE))
-        slicers = c_value_to_py(res, GetSlicersResult()).slicers
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return slicers if slicers else []
-        raise RuntimeError(err)
+        try:
+            slicers = c_value_to_py(res, GetSlicersResult()).slicers
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return slicers if slicers else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def get_style(self, style_id: int) -> Optional[Style]:
         """
@@ -4608,12 +5777,61 @@ class File:
             otherwise raise a RuntimeError with the message.
         """
         prepare_args([style_id], [argsRule("style_id", [int])])
-        lib.GetStyle.restype = types_go._GetStyleResult
         res = lib.GetStyle(self.file_index, c_longlong(style_id))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.style, Style())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.style, Style())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def get_table_rows(self, name: str) -> TableRows:
+        """
+        Get the data of a table by given table name, the table will be looked
+        up on all worksheets in the workbook. It returns the name of the
+        worksheet and the range reference of the table, the header names of the
+        table columns, and the typed cells of the data rows without the header
+        row and the totals row of the table. Each cell carries the same fields
+        as the `get_typed_rows` function returns.
+
+        Args:
+            name (str): The table name
+
+        Returns:
+            TableRows: Return the table data if no error occurred, otherwise
+            raise a RuntimeError with the message.
+
+        Example:
+            For example, read the rows of the table `Table1` as records:
+
+            ```python
+            try:
+                table = f.get_table_rows("Table1")
+                for row in table.rows:
+                    record = dict(zip(table.header, (cell.value for cell in row)))
+                    print(record)
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args([name], [argsRule("name", [str])])
+        res = lib.GetTableRows(self.file_index, name.encode(ENCODE))
+        try:
+            err = res.Err.decode(ENCODE)
+            if err:
+                raise new_error(err, res.ErrCode)
+            return TableRows(
+                sheet=res.Sheet.decode(ENCODE),
+                range=res.Range.decode(ENCODE),
+                header=[
+                    string_at(res.Header[i]).decode(ENCODE)
+                    for i in range(res.HeaderLen)
+                ],
+                rows=c_typed_cells_to_py(res.Row, res.RowLen),
+            )
+        finally:
+            free_result(res)
 
     def get_tables(self, sheet: str) -> List[Table]:
         """
@@ -4627,13 +5845,64 @@ class File:
             raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.GetTables.restype = types_go._GetTablesResult
         res = lib.GetTables(self.file_index, sheet.encode(ENCODE))
-        tables = c_value_to_py(res, GetTablesResult()).tables
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return tables if tables else []
-        raise RuntimeError(err)
+        try:
+            tables = c_value_to_py(res, GetTablesResult()).tables
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return tables if tables else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
+
+    def get_typed_rows(self, sheet: str, *range_ref: str) -> List[List[TypedCell]]:
+        """
+        Get the typed cells in a worksheet by given worksheet name and range
+        reference, for example `C10:H5000`, or a defined name which refers to a
+        range. The whole column and row references are clamped to the used range
+        of the worksheet. All the rows in the worksheet will be returned if the
+        range reference is omitted. Each cell carries the cell type, the typed
+        value built from the raw value as the `get_cell_typed_value` function
+        does, the raw value, the formatted value and the style ID, so that
+        reading them needs only one call.
+
+        Args:
+            sheet (str): The worksheet name
+            *range_ref (str): Optional parameter, the range reference or the
+            defined name
+
+        Returns:
+            List[List[TypedCell]]: Return the typed cells matrix if no error
+            occurred, otherwise raise a RuntimeError with the message.
+
+        Example:
+            For example, get the typed cells in range `A1:C10` on `Sheet1`:
+
+            ```python
+            try:
+                for row in f.get_typed_rows("Sheet1", "A1:C10"):
+                    for cell in row:
+                        print(cell.cell_type, cell.value, cell.style_id)
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args(
+            [sheet, range_ref[0]] if range_ref else [sheet],
+            [argsRule("sheet", [str]), argsRule("range_ref", [str], True)],
+        )
+        res = lib.GetTypedRows(
+            self.file_index,
+            sheet.encode(ENCODE),
+            (range_ref[0] if range_ref else "").encode(ENCODE),
+        )
+        try:
+            err = res.Err.decode(ENCODE)
+            if err:
+                raise new_error(err, res.ErrCode)
+            return c_typed_cells_to_py(res.Row, res.RowLen)
+        finally:
+            free_result(res)
 
     def get_workbook_props(self) -> WorkbookPropsOptions:
         """
@@ -4643,12 +5912,14 @@ class File:
             WorkbookPropsOptions: Return the workbook property options if no
             error occurred, otherwise raise a RuntimeError with the message.
         """
-        lib.GetWorkbookProps.restype = types_go._GetWorkbookPropsResult
         res = lib.GetWorkbookProps(self.file_index)
-        err = res.err.decode(ENCODE)
-        if not err:
-            return c_value_to_py(res.opts, WorkbookPropsOptions())
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return c_value_to_py(res.opts, WorkbookPropsOptions())
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def group_sheets(self, sheets: List[str]) -> None:
         """
@@ -4663,15 +5934,11 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([sheets], [argsRule("sheets", [list])])
-        lib.GroupSheets.restype = c_char_p
         array = (c_char_p * len(sheets))()
         for i, value in enumerate(sheets):
             array[i] = value.encode(ENCODE)
-        err = lib.GroupSheets(self.file_index, array, c_longlong(len(sheets))).decode(
-            ENCODE
-        )
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.GroupSheets(self.file_index, array, c_longlong(len(sheets)))
+        check_error_result(res)
 
     def insert_cols(self, sheet: str, col: str, n: int) -> None:
         """
@@ -4708,15 +5975,13 @@ class File:
                 argsRule("n", [int]),
             ],
         )
-        lib.InsertCols.restype = c_char_p
-        err = lib.InsertCols(
+        res = lib.InsertCols(
             self.file_index,
             sheet.encode(ENCODE),
             col.encode(ENCODE),
             c_longlong(n),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def insert_page_break(self, sheet: str, cell: str) -> None:
         """
@@ -4737,14 +6002,12 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        lib.InsertPageBreak.restype = c_char_p
-        err = lib.InsertPageBreak(
+        res = lib.InsertPageBreak(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def insert_rows(self, sheet: str, row: int, n: int) -> None:
         """
@@ -4781,15 +6044,13 @@ class File:
                 argsRule("n", [int]),
             ],
         )
-        lib.InsertRows.restype = c_char_p
-        err = lib.InsertRows(
+        res = lib.InsertRows(
             self.file_index,
             sheet.encode(ENCODE),
             c_longlong(row),
             c_longlong(n),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def merge_cell(
         self, sheet: str, top_left_cell: str, bottom_right_cell: str
@@ -4825,15 +6086,13 @@ class File:
                 argsRule("bottom_right_cell", [str]),
             ],
         )
-        lib.MergeCell.restype = c_char_p
-        err = lib.MergeCell(
+        res = lib.MergeCell(
             self.file_index,
             sheet.encode(ENCODE),
             top_left_cell.encode(ENCODE),
             bottom_right_cell.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def move_sheet(self, source: str, target: str) -> None:
         """
@@ -4865,14 +6124,12 @@ class File:
             [source, target],
             [argsRule("source", [str]), argsRule("target", [str])],
         )
-        lib.MoveSheet.restype = c_char_p
-        err = lib.MoveSheet(
+        res = lib.MoveSheet(
             self.file_index,
             source.encode(ENCODE),
             target.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def new_conditional_style(self, style: Style) -> int:
         """
@@ -4887,13 +6144,15 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([style], [argsRule("style", [Style])])
-        lib.NewConditionalStyle.restype = types_go._IntErrorResult
         options = py_value_to_c(style, types_go._Style())
         res = lib.NewConditionalStyle(self.file_index, byref(options))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def new_sheet(self, sheet: str) -> int:
         """
@@ -4909,14 +6168,16 @@ class File:
             otherwise raise a RuntimeError with the message.
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.NewSheet.restype = types_go._IntErrorResult
         res = lib.NewSheet(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
-    def new_stream_writer(self, sheet: str) -> StreamWriter:
+    def new_stream_writer(self, sheet: str, append: bool = False) -> StreamWriter:
         """
         Returns stream writer struct by given worksheet name used for writing
         data on a new existing empty worksheet with large amounts of data. Note
@@ -4928,8 +6189,22 @@ class File:
         temporary files on disk to reduce the memory usage when in-memory chunks
         data over 16MB, and you can't get cell value at this time.
 
+        In append mode, the stream writer appends data after the last row of a
+        worksheet which already contains data. The existing rows, merged cells,
+        column settings and tables of the worksheet will be kept, and the rows
+        should be set starting from the row returned by `StreamWriter.next_row`.
+        When flushing, the ranges of the tables which end at the last row of the
+        worksheet will be extended to the last row set by the stream writer,
+        except the tables with the totals row. The stream writer in append mode
+        can't add table on the worksheet which already contains tables. The
+        rows and the other settings of the stream writer are written by the
+        functions of the workbook when they are set, such as
+        `File.set_cell_value`, `File.set_col_width` and `File.merge_cell`.
+
         Args:
             sheet (str): The worksheet name
+            append (bool): Append data after the existing rows of the worksheet,
+                default is False
 
         Returns:
             StreamWriter: Return the stream writer object if no error occurred,
@@ -4958,14 +6233,32 @@ class File:
                 if err:
                     print(err)
             ```
+
+            Append the rows of today to the existing log worksheet:
+
+            ```python
+            sw = f.new_stream_writer("Log", append=True)
+            for r, row in enumerate(rows_of_today(), start=sw.next_row()):
+                sw.set_row(f"A{r}", row)
+            sw.flush()
+            ```
         """
-        prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.NewStreamWriter.restype = types_go._IntErrorResult
-        res = lib.NewStreamWriter(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return StreamWriter(res.val)
-        raise RuntimeError(err)
+        prepare_args(
+            [sheet, append], [argsRule("sheet", [str]), argsRule("append", [bool])]
+        )
+        if append:
+            res = lib.NewStreamWriterAppend(self.file_index, sheet.encode(ENCODE))
+        else:
+            res = lib.NewStreamWriter(self.file_index, sheet.encode(ENCODE))
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                stream_writer = StreamWriter(res.val)
+                stream_writer.progress_callback = self.progress_callback
+                return stream_writer
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def new_style(self, style: Style) -> int:
         """
@@ -5109,13 +6402,15 @@ class File:
             ```
         """
         prepare_args([style], [argsRule("style", [Style])])
-        lib.NewStyle.restype = types_go._IntErrorResult
         options = py_value_to_c(style, types_go._Style())
         res = lib.NewStyle(self.file_index, byref(options))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return res.val
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return res.val
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def protect_sheet(self, sheet: str, opts: SheetProtectionOptions) -> None:
         """
@@ -5156,13 +6451,9 @@ class File:
                 argsRule("opts", [SheetProtectionOptions]),
             ],
         )
-        lib.ProtectSheet.restype = c_char_p
         options = py_value_to_c(opts, types_go._SheetProtectionOptions())
-        err = lib.ProtectSheet(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.ProtectSheet(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def protect_workbook(self, opts: WorkbookProtectionOptions) -> None:
         """
@@ -5194,11 +6485,9 @@ class File:
             ```
         """
         prepare_args([opts], [argsRule("opts", [WorkbookProtectionOptions])])
-        lib.ProtectWorkbook.restype = c_char_p
         options = py_value_to_c(opts, types_go._WorkbookProtectionOptions())
-        err = lib.ProtectWorkbook(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.ProtectWorkbook(self.file_index, byref(options))
+        check_error_result(res)
 
     def remove_col(self, sheet: str, col: str) -> None:
         """
@@ -5230,12 +6519,8 @@ class File:
             [sheet, col],
             [argsRule("sheet", [str]), argsRule("col", [str])],
         )
-        lib.RemoveCol.restype = c_char_p
-        err = lib.RemoveCol(
-            self.file_index, sheet.encode(ENCODE), col.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.RemoveCol(self.file_index, sheet.encode(ENCODE), col.encode(ENCODE))
+        check_error_result(res)
 
     def remove_page_break(self, sheet: str, cell: str) -> None:
         """
@@ -5253,12 +6538,10 @@ class File:
             [sheet, cell],
             [argsRule("sheet", [str]), argsRule("cell", [str])],
         )
-        lib.RemovePageBreak.restype = c_char_p
-        err = lib.RemovePageBreak(
+        res = lib.RemovePageBreak(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def remove_row(self, sheet: str, row: int) -> None:
         """
@@ -5290,12 +6573,8 @@ class File:
             [sheet, row],
             [argsRule("sheet", [str]), argsRule("row", [int])],
         )
-        lib.RemoveRow.restype = c_char_p
-        err = lib.RemoveRow(
-            self.file_index, sheet.encode(ENCODE), c_longlong(row)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.RemoveRow(self.file_index, sheet.encode(ENCODE), c_longlong(row))
+        check_error_result(res)
 
     def rows(self, sheet: str) -> Rows:
         """
@@ -5325,12 +6604,14 @@ class File:
             ```
         """
         prepare_args([sheet], [argsRule("sheet", [str])])
-        lib.Rows.restype = types_go._IntErrorResult
         res = lib.Rows(self.file_index, sheet.encode(ENCODE))
-        err = res.err.decode(ENCODE)
-        if not err:
-            return Rows(res.val)
-        raise RuntimeError(err)
+        try:
+            err = res.err.decode(ENCODE)
+            if not err:
+                return Rows(res.val)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def search_sheet(self, sheet: str, value: str, *reg: bool) -> List[str]:
         """
@@ -5378,18 +6659,20 @@ class File:
                 argsRule("reg", [bool], True),
             ],
         )
-        lib.SearchSheet.restype = types_go._StringArrayErrorResult
         res = lib.SearchSheet(
             self.file_index,
             sheet.encode(ENCODE),
             value.encode(ENCODE),
             reg[0] if reg else False,
         )
-        arr = c_value_to_py(res, StringArrayErrorResult()).arr
-        err = res.Err.decode(ENCODE)
-        if not err:
-            return arr if arr else []
-        raise RuntimeError(err)
+        try:
+            arr = c_value_to_py(res, StringArrayErrorResult()).arr
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return arr if arr else []
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
     def set_active_sheet(self, index: int) -> None:
         """
@@ -5406,10 +6689,8 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([index], [argsRule("index", [int])])
-        err, lib.SetActiveSheet.restype = None, c_char_p
-        err = lib.SetActiveSheet(self.file_index, c_longlong(index)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetActiveSheet(self.file_index, c_longlong(index))
+        check_error_result(res)
 
     def set_app_props(self, app_properties: AppProperties) -> None:
         """
@@ -5477,11 +6758,9 @@ class File:
             [app_properties],
             [argsRule("app_properties", [AppProperties])],
         )
-        lib.SetAppProps.restype = c_char_p
         options = py_value_to_c(app_properties, types_go._AppProperties())
-        err = lib.SetAppProps(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetAppProps(self.file_index, byref(options))
+        check_error_result(res)
 
     def set_calc_props(self, opts: CalcPropsOptions) -> None:
         """
@@ -5497,11 +6776,9 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([opts], [argsRule("opts", [CalcPropsOptions])])
-        lib.SetCalcProps.restype = c_char_p
         options = py_value_to_c(opts, types_go._CalcPropsOptions())
-        err = lib.SetCalcProps(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetCalcProps(self.file_index, byref(options))
+        check_error_result(res)
 
     def set_cell_bool(self, sheet: str, cell: str, value: bool) -> None:
         """
@@ -5525,12 +6802,10 @@ class File:
                 argsRule("value", [bool]),
             ],
         )
-        err, lib.SetCellBool.restype = None, c_char_p
-        err = lib.SetCellBool(
+        res = lib.SetCellBool(
             self.file_index, sheet.encode(ENCODE), cell.encode(ENCODE), value
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_default(self, sheet: str, cell: str, value: str) -> None:
         """
@@ -5554,15 +6829,13 @@ class File:
                 argsRule("value", [str]),
             ],
         )
-        err, lib.SetCellDefault.restype = None, c_char_p
-        err = lib.SetCellDefault(
+        res = lib.SetCellDefault(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             value.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_float(
         self,
@@ -5610,17 +6883,15 @@ class File:
                 argsRule("bit_size", [int]),
             ],
         )
-        err, lib.SetCellFloat.restype = None, c_char_p
-        err = lib.SetCellFloat(
+        res = lib.SetCellFloat(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             c_double(value),
             c_longlong(precision),
             c_longlong(bit_size),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_formula(
         self, sheet: str, cell: str, formula: str, *opts: FormulaOpts
@@ -5741,21 +7012,19 @@ class File:
                 argsRule("opts", [FormulaOpts], True),
             ],
         )
-        err, lib.SetCellFormula.restype = None, c_char_p
         options = (
             byref(py_value_to_c(opts[0], types_go._FormulaOpts()))
             if opts
             else POINTER(types_go._FormulaOpts)()
         )
-        err = lib.SetCellFormula(
+        res = lib.SetCellFormula(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             formula.encode(ENCODE),
             options,
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_hyperlink(
         self,
@@ -5830,22 +7099,20 @@ class File:
                 argsRule("opts", [HyperlinkOpts], True),
             ],
         )
-        err, lib.SetCellHyperLink.restype = None, c_char_p
         options = (
             byref(py_value_to_c(opts[0], types_go._HyperlinkOpts()))
             if opts
             else POINTER(types_go._HyperlinkOpts)()
         )
-        err = lib.SetCellHyperLink(
+        res = lib.SetCellHyperLink(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             link.encode(ENCODE),
             link_type.encode(ENCODE),
             options,
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_int(self, sheet: str, cell: str, value: int) -> None:
         """
@@ -5869,15 +7136,13 @@ class File:
                 argsRule("value", [int]),
             ],
         )
-        lib.SetCellInt.restype = c_char_p
-        err = lib.SetCellInt(
+        res = lib.SetCellInt(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             c_longlong(value),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_rich_text(
         self,
@@ -6010,19 +7275,17 @@ class File:
                 argsRule("runs", [list]),
             ],
         )
-        lib.SetCellRichText.restype = c_char_p
         vals = (types_go._RichTextRun * len(runs))()
         for i, value in enumerate(runs):
             vals[i] = py_value_to_c(value, types_go._RichTextRun())
-        err = lib.SetCellRichText(
+        res = lib.SetCellRichText(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             byref(vals),
             len(vals),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_str(self, sheet: str, cell: str, value: str) -> None:
         """
@@ -6046,15 +7309,13 @@ class File:
                 argsRule("value", [str]),
             ],
         )
-        lib.SetCellStr.restype = c_char_p
-        err = lib.SetCellStr(
+        res = lib.SetCellStr(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             value.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_style(
         self,
@@ -6090,22 +7351,20 @@ class File:
                 argsRule("style_id", [int]),
             ],
         )
-        lib.SetCellStyle.restype = c_char_p
-        err = lib.SetCellStyle(
+        res = lib.SetCellStyle(
             self.file_index,
             sheet.encode(ENCODE),
             top_left_cell.encode(ENCODE),
             bottom_right_cell.encode(ENCODE),
             c_longlong(style_id),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_cell_value(
         self,
         sheet: str,
         cell: str,
-        value: Union[bool, float, int, str, date, datetime, None],
+        value: Union[bool, float, int, str, date, datetime, timedelta, None],
     ) -> None:
         """
         Set the value of a cell. The specified coordinates should not be in the
@@ -6121,8 +7380,8 @@ class File:
         Args:
             sheet (str): The worksheet name
             cell (str): The cell reference
-            value (Union[bool, float, int, str, date, datetime, None]): The cell
-            value to be write
+            value (Union[bool, float, int, str, date, datetime, timedelta,
+            None]): The cell value to be write
 
         Returns:
             None: Return None if no error occurred, otherwise raise a
@@ -6135,19 +7394,17 @@ class File:
                 argsRule("cell", [str]),
                 argsRule(
                     "value",
-                    [bool, float, int, str, date, datetime, type(None)],
+                    [bool, float, int, str, date, datetime, timedelta, type(None)],
                 ),
             ],
         )
-        lib.SetCellValue.restype = c_char_p
-        err = lib.SetCellValue(
+        res = lib.SetCellValue(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             byref(py_value_to_c_interface(value)),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_col_outline_level(self, sheet: str, col: str, level: int) -> None:
         """
@@ -6181,12 +7438,10 @@ class File:
                 argsRule("level", [int]),
             ],
         )
-        lib.SetColOutlineLevel.restype = c_char_p
-        err = lib.SetColOutlineLevel(
+        res = lib.SetColOutlineLevel(
             self.file_index, sheet.encode(ENCODE), col.encode(ENCODE), c_longlong(level)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_col_style(self, sheet: str, columns: str, style_id: int) -> None:
         """
@@ -6230,15 +7485,13 @@ class File:
                 argsRule("style_id", [int]),
             ],
         )
-        lib.SetColStyle.restype = c_char_p
-        err = lib.SetColStyle(
+        res = lib.SetColStyle(
             self.file_index,
             sheet.encode(ENCODE),
             columns.encode(ENCODE),
             c_longlong(style_id),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_col_visible(self, sheet: str, columns: str, visible: bool) -> None:
         """
@@ -6281,12 +7534,10 @@ class File:
                 argsRule("visible", [bool]),
             ],
         )
-        lib.SetColVisible.restype = c_char_p
-        err = lib.SetColVisible(
+        res = lib.SetColVisible(
             self.file_index, sheet.encode(ENCODE), columns.encode(ENCODE), visible
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_col_width(
         self, sheet: str, start_col: str, end_col: str, width: Union[float, int]
@@ -6323,16 +7574,14 @@ class File:
                 argsRule("width", [int, float]),
             ],
         )
-        lib.SetColWidth.restype = c_char_p
-        err = lib.SetColWidth(
+        res = lib.SetColWidth(
             self.file_index,
             sheet.encode(ENCODE),
             start_col.encode(ENCODE),
             end_col.encode(ENCODE),
             c_double(width),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_conditional_format(
         self,
@@ -6362,19 +7611,17 @@ class File:
                 argsRule("opts", [list]),
             ],
         )
-        lib.SetConditionalFormat.restype = c_char_p
         vals = (types_go._ConditionalFormatOptions * len(opts))()
         for i, value in enumerate(opts):
             vals[i] = py_value_to_c(value, types_go._ConditionalFormatOptions())
-        err = lib.SetConditionalFormat(
+        res = lib.SetConditionalFormat(
             self.file_index,
             sheet.encode(ENCODE),
             range_ref.encode(ENCODE),
             byref(vals),
             len(vals),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_custom_props(self, prop: CustomProperty) -> None:
         """
@@ -6393,19 +7640,17 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([prop], [argsRule("prop", [CustomProperty])])
-        lib.SetCustomProps.restype = c_char_p
         options = types_go._CustomProperty()
         setattr(options, "Name", (prop.name or "").encode(ENCODE))
         val = types_go._Interface()
-        if type(prop.value) is int:
+        if type(prop.value) is int and -(2**31) <= prop.value < 2**31:
             setattr(val, "Type", c_int(2))
             setattr(val, "Integer32", c_int32(prop.value))
         else:
             val = py_value_to_c_interface(prop.value)
         setattr(options, "Value", val)
-        err = lib.SetCustomProps(self.file_index, options).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetCustomProps(self.file_index, options)
+        check_error_result(res)
 
     def set_default_font(self, font_name: str) -> None:
         """
@@ -6420,12 +7665,8 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([font_name], [argsRule("font_name", [str])])
-        lib.SetDefaultFont.restype = c_char_p
-        err = lib.SetDefaultFont(self.file_index, font_name.encode(ENCODE)).decode(
-            ENCODE
-        )
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetDefaultFont(self.file_index, font_name.encode(ENCODE))
+        check_error_result(res)
 
     def set_defined_name(self, defined_name: DefinedName) -> None:
         """
@@ -6476,11 +7717,9 @@ class File:
             ```
         """
         prepare_args([defined_name], [argsRule("defined_name", [DefinedName])])
-        lib.SetDefinedName.restype = c_char_p
         options = py_value_to_c(defined_name, types_go._DefinedName())
-        err = lib.SetDefinedName(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetDefinedName(self.file_index, byref(options))
+        check_error_result(res)
 
     def set_doc_props(self, doc_properties: DocProperties) -> None:
         """
@@ -6524,11 +7763,9 @@ class File:
             [doc_properties],
             [argsRule("doc_properties", [DocProperties])],
         )
-        lib.SetDocProps.restype = c_char_p
         options = py_value_to_c(doc_properties, types_go._DocProperties())
-        err = lib.SetDocProps(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetDocProps(self.file_index, byref(options))
+        check_error_result(res)
 
     def set_header_footer(self, sheet: str, opts: HeaderFooterOptions) -> None:
         """
@@ -6571,13 +7808,38 @@ class File:
                 argsRule("opts", [HeaderFooterOptions]),
             ],
         )
-        lib.SetHeaderFooter.restype = c_char_p
         options = py_value_to_c(opts, types_go._HeaderFooterOptions())
-        err = lib.SetHeaderFooter(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetHeaderFooter(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
+
+    def set_locking(self, enabled: bool) -> None:
+        """
+        Enable or disable the read/write lock of the workbook. The lock is
+        enabled by default, so a workbook can be used from many threads: the
+        functions that only read the workbook take the read lock, and the
+        functions that modify it take the write lock. Callers that guarantee
+        the workbook will be used from a single thread can disable it to avoid
+        the locking overhead.
+
+        Args:
+            enabled (bool): Whether to enable the lock of the workbook
+
+        Returns:
+            None: Return None if no error occurred, otherwise raise a
+            RuntimeError with the message.
+
+        Example:
+            For example, disable the lock of a workbook which is only used in
+            the current thread:
+
+            ```python
+            f = excelize.new_file()
+            f.set_locking(False)
+            ```
+        """
+        prepare_args([enabled], [argsRule("enabled", [bool])])
+        res = lib.SetLocking(self.file_index, enabled)
+        check_error_result(res)
 
     def set_page_layout(self, sheet: str, opts: PageLayoutOptions) -> None:
         """
@@ -6598,13 +7860,9 @@ class File:
                 argsRule("opts", [PageLayoutOptions]),
             ],
         )
-        lib.SetPageLayout.restype = c_char_p
         options = py_value_to_c(opts, types_go._PageLayoutOptions())
-        err = lib.SetPageLayout(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetPageLayout(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def set_page_margins(self, sheet: str, opts: PageLayoutMarginsOptions) -> None:
         """
@@ -6625,13 +7883,9 @@ class File:
                 argsRule("opts", [PageLayoutMarginsOptions]),
             ],
         )
-        lib.SetPageMargins.restype = c_char_p
         options = py_value_to_c(opts, types_go._PageLayoutMarginsOptions())
-        err = lib.SetPageMargins(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetPageMargins(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def set_panes(self, sheet: str, opts: Panes) -> None:
         """
@@ -6650,13 +7904,120 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [Panes])],
         )
-        lib.SetPanes.restype = c_char_p
         options = py_value_to_c(opts, types_go._Panes())
-        err = lib.SetPanes(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetPanes(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
+
+    def set_progress_callback(
+        self, callback: Optional[Callable[[ProgressEvent], Any]]
+    ) -> None:
+        """
+        Register the progress callback of the workbook, or remove it by None.
+        The callback receives the progress events of saving the workbook and
+        calculating the cell values, and the stream writers created after it
+        has been registered report the flushing progress to it. Saving the
+        workbook reports the number of bytes serialized and then the number of
+        bytes written in the serialize and write steps. Returning a
+        true value from the callback or raising an exception in it cancels the
+        operation, and the function raises a CanceledError. The callbacks may
+        be called from other threads.
+
+        Args:
+            callback (Optional[Callable[[ProgressEvent], Any]]): The progress
+            callback
+
+        Returns:
+            None: Return None if no error occurred, otherwise raise a
+            RuntimeError with the message.
+
+        Example:
+            For example, show the progress of saving the workbook, and cancel
+            it if the workbook is larger than 100 MB:
+
+            ```python
+            def progress(event: excelize.ProgressEvent) -> bool:
+                print(f"{event.bytes_processed}/{event.bytes_total} bytes")
+                return event.bytes_total > 100 << 20
+
+            f.set_progress_callback(progress)
+            f.save_as("Book1.xlsx")
+            ```
+        """
+        progress_callback = new_progress_callback(callback)
+        res = lib.SetProgressCallback(self.file_index, progress_callback)
+        check_error_result(res)
+        self.progress_callback = progress_callback
+
+    def set_range(
+        self,
+        sheet: str,
+        cell: str,
+        values: List[
+            List[Union[bool, float, int, str, date, datetime, timedelta, None]]
+        ],
+        *style_id: int,
+    ) -> None:
+        """
+        Writes a block of cells by given worksheet name, starting cell reference
+        and the row-major matrix of the cell values in one call. The shorter
+        rows in the matrix will be padded with `None`, which clears the cells.
+        The style will be applied to the whole block if the style ID is given.
+
+        Args:
+            sheet (str): The worksheet name
+            cell (str): The top left cell reference of the block
+            values (List[List[Union[bool, float, int, str, date, datetime,
+            timedelta, None]]]): The cell values matrix
+            *style_id (int): Optional parameter, the style ID applied to the
+            block
+
+        Returns:
+            None: Return None if no error occurred, otherwise raise a
+            RuntimeError with the message.
+
+        Example:
+            For example, writes a 2 x 3 block start with the cell `B6` on
+            `Sheet1` and apply the style to it:
+
+            ```python
+            try:
+                f.set_range("Sheet1", "B6", [[1, 2, 3], ["a", "b", "c"]], style)
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args(
+            (
+                [sheet, cell, values, style_id[0]]
+                if style_id
+                else [sheet, cell, values]
+            ),
+            [
+                argsRule("sheet", [str]),
+                argsRule("cell", [str]),
+                argsRule("values", [list]),
+                argsRule("style_id", [int], True),
+            ],
+        )
+        rows = len(values)
+        cols = max((len(row) for row in values if isinstance(row, list)), default=0)
+        vals = (types_go._Interface * (rows * cols))()
+        for i, row in enumerate(values):
+            prepare_args([row], [argsRule("values", [list])])
+            for j in range(cols):
+                vals[i * cols + j] = py_value_to_c_interface(
+                    row[j] if j < len(row) else None
+                )
+        res = lib.SetRange(
+            self.file_index,
+            sheet.encode(ENCODE),
+            cell.encode(ENCODE),
+            byref(vals),
+            rows,
+            cols,
+            style_id[0] if style_id else -1,
+        )
+        check_error_result(res)
 
     def set_row_height(self, sheet: str, row: int, height: Union[int, float]) -> None:
         """
@@ -6691,12 +8052,10 @@ class File:
                 argsRule("height", [int, float]),
             ],
         )
-        lib.SetRowHeight.restype = c_char_p
-        err = lib.SetRowHeight(
+        res = lib.SetRowHeight(
             self.file_index, sheet.encode(ENCODE), c_longlong(row), c_double(height)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_row_outline_level(self, sheet: str, row: int, level: int) -> None:
         """
@@ -6730,12 +8089,10 @@ class File:
                 argsRule("level", [int]),
             ],
         )
-        lib.SetRowOutlineLevel.restype = c_char_p
-        err = lib.SetRowOutlineLevel(
+        res = lib.SetRowOutlineLevel(
             self.file_index, sheet.encode(ENCODE), c_longlong(row), c_longlong(level)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_row_style(self, sheet: str, start: int, end: int, style_id: int) -> None:
         """
@@ -6781,16 +8138,14 @@ class File:
                 argsRule("style_id", [int]),
             ],
         )
-        lib.SetRowStyle.restype = c_char_p
-        err = lib.SetRowStyle(
+        res = lib.SetRowStyle(
             self.file_index,
             sheet.encode(ENCODE),
             c_longlong(start),
             c_longlong(end),
             c_longlong(style_id),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_row_visible(self, sheet: str, row: int, visible: bool) -> None:
         """
@@ -6824,15 +8179,13 @@ class File:
                 argsRule("visible", [bool]),
             ],
         )
-        lib.SetRowVisible.restype = c_char_p
-        err = lib.SetRowVisible(
+        res = lib.SetRowVisible(
             self.file_index,
             sheet.encode(ENCODE),
             c_longlong(row),
             c_bool(visible),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_background(self, sheet: str, picture: str) -> None:
         """
@@ -6852,14 +8205,12 @@ class File:
             [sheet, picture],
             [argsRule("sheet", [str]), argsRule("picture", [str])],
         )
-        lib.SetSheetBackground.restype = c_char_p
-        err = lib.SetSheetBackground(
+        res = lib.SetSheetBackground(
             self.file_index,
             sheet.encode(ENCODE),
             picture.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_background_from_bytes(
         self, sheet: str, extension: str, picture: bytes
@@ -6886,22 +8237,20 @@ class File:
                 argsRule("picture", [bytes]),
             ],
         )
-        lib.SetSheetBackgroundFromBytes.restype = c_char_p
-        err = lib.SetSheetBackgroundFromBytes(
+        res = lib.SetSheetBackgroundFromBytes(
             self.file_index,
             sheet.encode(ENCODE),
             extension.encode(ENCODE),
             cast(picture, POINTER(c_ubyte)),
             len(picture),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_col(
         self,
         sheet: str,
         cell: str,
-        values: List[Union[bool, float, int, str, date, datetime, None]],
+        values: List[Union[bool, float, int, str, date, datetime, timedelta, None]],
     ) -> None:
         """
         Writes cells to column by given worksheet name, starting cell reference
@@ -6910,8 +8259,8 @@ class File:
         Args:
             sheet (str): The worksheet name
             cell (str): The cell reference
-            values (List[Union[bool, float, int, str, date, datetime, None]):
-            The cell values
+            values (List[Union[bool, float, int, str, date, datetime, timedelta,
+            None]): The cell values
 
         Returns:
             None: Return None if no error occurred, otherwise raise a
@@ -6936,19 +8285,17 @@ class File:
                 argsRule("values", [list]),
             ],
         )
-        lib.SetSheetCol.restype = c_char_p
         vals = (types_go._Interface * len(values))()
         for i, value in enumerate(values):
             vals[i] = py_value_to_c_interface(value)
-        err = lib.SetSheetCol(
+        res = lib.SetSheetCol(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             byref(vals),
             len(vals),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_dimension(self, sheet: str, range_ref: str) -> None:
         """
@@ -6970,14 +8317,12 @@ class File:
             [sheet, range_ref],
             [argsRule("sheet", [str]), argsRule("range_ref", [str])],
         )
-        lib.SetSheetDimension.restype = c_char_p
-        err = lib.SetSheetDimension(
+        res = lib.SetSheetDimension(
             self.file_index,
             sheet.encode(ENCODE),
             range_ref.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_name(self, source: str, target: str) -> None:
         """
@@ -6999,14 +8344,12 @@ class File:
             [source, target],
             [argsRule("source", [str]), argsRule("target", [str])],
         )
-        lib.SetSheetName.restype = c_char_p
-        err = lib.SetSheetName(
+        res = lib.SetSheetName(
             self.file_index,
             source.encode(ENCODE),
             target.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_props(self, sheet: str, opts: SheetPropsOptions) -> None:
         """
@@ -7024,19 +8367,15 @@ class File:
             [sheet, opts],
             [argsRule("sheet", [str]), argsRule("opts", [SheetPropsOptions])],
         )
-        lib.SetSheetProps.restype = c_char_p
         options = py_value_to_c(opts, types_go._SheetPropsOptions())
-        err = lib.SetSheetProps(
-            self.file_index, sheet.encode(ENCODE), byref(options)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetSheetProps(self.file_index, sheet.encode(ENCODE), byref(options))
+        check_error_result(res)
 
     def set_sheet_row(
         self,
         sheet: str,
         cell: str,
-        values: List[Union[bool, float, int, str, date, datetime, None]],
+        values: List[Union[bool, float, int, str, date, datetime, timedelta, None]],
     ) -> None:
         """
         Writes cells to row by given worksheet name, starting cell reference and
@@ -7045,8 +8384,8 @@ class File:
         Args:
             sheet (str): The worksheet name
             cell (str): The cell reference
-            values (List[Union[bool, float, int, str, date, datetime, None]]):
-            The cell values
+            values (List[Union[bool, float, int, str, date, datetime, timedelta,
+            None]]): The cell values
 
         Returns:
             None: Return None if no error occurred, otherwise raise a
@@ -7071,19 +8410,17 @@ class File:
                 argsRule("values", [list]),
             ],
         )
-        lib.SetSheetRow.restype = c_char_p
         vals = (types_go._Interface * len(values))()
         for i, value in enumerate(values):
             vals[i] = py_value_to_c_interface(value)
-        err = lib.SetSheetRow(
+        res = lib.SetSheetRow(
             self.file_index,
             sheet.encode(ENCODE),
             cell.encode(ENCODE),
             byref(vals),
             len(vals),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_view(self, sheet: str, view_index: int, opts: ViewOptions) -> None:
         """
@@ -7107,16 +8444,14 @@ class File:
                 argsRule("opts", [ViewOptions]),
             ],
         )
-        lib.SetSheetView.restype = c_char_p
         options = py_value_to_c(opts, types_go._ViewOptions())
-        err = lib.SetSheetView(
+        res = lib.SetSheetView(
             self.file_index,
             sheet.encode(ENCODE),
             c_longlong(view_index),
             byref(options),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def set_sheet_visible(self, sheet: str, visible: bool, *very_hidden: bool) -> None:
         """
@@ -7149,15 +8484,11 @@ class File:
                 argsRule("very_hidden", [bool], True),
             ],
         )
-        lib.SetSheetVisible.restype = c_char_p
         vh = False
         if len(very_hidden) > 0:
             vh = very_hidden[0]
-        err = lib.SetSheetVisible(
-            self.file_index, sheet.encode(ENCODE), visible, vh
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetSheetVisible(self.file_index, sheet.encode(ENCODE), visible, vh)
+        check_error_result(res)
 
     def set_workbook_props(self, opts: WorkbookPropsOptions) -> None:
         """
@@ -7171,11 +8502,9 @@ class File:
             RuntimeError with the message.
         """
         prepare_args([opts], [argsRule("opts", [WorkbookPropsOptions])])
-        lib.SetWorkbookProps.restype = c_char_p
         options = py_value_to_c(opts, types_go._WorkbookPropsOptions())
-        err = lib.SetWorkbookProps(self.file_index, byref(options)).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.SetWorkbookProps(self.file_index, byref(options))
+        check_error_result(res)
 
     def ungroup_sheets(self) -> None:
         """
@@ -7185,10 +8514,8 @@ class File:
             None: Return None if no error occurred, otherwise raise a
             RuntimeError with the message.
         """
-        lib.UngroupSheets.restype = c_char_p
-        err = lib.UngroupSheets(self.file_index).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.UngroupSheets(self.file_index)
+        check_error_result(res)
 
     def unmerge_cell(
         self, sheet: str, top_left_cell: str, bottom_right_cell: str
@@ -7223,15 +8550,13 @@ class File:
                 argsRule("bottom_right_cell", [str]),
             ],
         )
-        lib.UnmergeCell.restype = c_char_p
-        err = lib.UnmergeCell(
+        res = lib.UnmergeCell(
             self.file_index,
             sheet.encode(ENCODE),
             top_left_cell.encode(ENCODE),
             bottom_right_cell.encode(ENCODE),
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def unprotect_sheet(self, sheet: str, *password: str) -> None:
         """
@@ -7250,16 +8575,14 @@ class File:
             [sheet, password[0]] if password else [sheet],
             [argsRule("sheet", [str]), argsRule("password", [str], True)],
         )
-        lib.UnprotectSheet.restype = c_char_p
         passwd = password[0] if len(password) > 0 else ""
-        err = lib.UnprotectSheet(
+        res = lib.UnprotectSheet(
             self.file_index,
             sheet.encode(ENCODE),
             passwd.encode(ENCODE),
             len(password) > 0,
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def unprotect_workbook(self, *password: str) -> None:
         """
@@ -7278,13 +8601,11 @@ class File:
             [password[0]] if password else [],
             [argsRule("password", [str], True)],
         )
-        lib.UnprotectWorkbook.restype = c_char_p
         passwd = password[0] if len(password) > 0 else ""
-        err = lib.UnprotectWorkbook(
+        res = lib.UnprotectWorkbook(
             self.file_index, passwd.encode(ENCODE), len(password) > 0
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def unset_conditional_format(self, sheet: str, range_ref: str) -> None:
         """
@@ -7303,12 +8624,10 @@ class File:
             [sheet, range_ref],
             [argsRule("sheet", [str]), argsRule("range_ref", [str])],
         )
-        lib.UnsetConditionalFormat.restype = c_char_p
-        err = lib.UnsetConditionalFormat(
+        res = lib.UnsetConditionalFormat(
             self.file_index, sheet.encode(ENCODE), range_ref.encode(ENCODE)
-        ).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        )
+        check_error_result(res)
 
     def update_linked_value(self) -> None:
         """
@@ -7324,10 +8643,48 @@ class File:
             None: Return None if no error occurred, otherwise raise a
             RuntimeError with the message.
         """
-        lib.UpdateLinkedValue.restype = c_char_p
-        err = lib.UpdateLinkedValue(self.file_index).decode(ENCODE)
-        if err != "":
-            raise RuntimeError(err)
+        res = lib.UpdateLinkedValue(self.file_index)
+        check_error_result(res)
+
+    def write_to_buffer(self, *opts: Options) -> bytes:
+        """
+        Get the serialized workbook bytes in memory without saving it to disk,
+        it allocates space in memory. Be careful when the file size is large.
+
+        Args:
+            *opts (Options): Optional parameters for writing the file.
+
+        Returns:
+            bytes: Return the workbook contents if no error occurred, otherwise
+            raise a RuntimeError with the message.
+
+        Example:
+            For example, get the workbook contents protected by password:
+
+            ```python
+            try:
+                buffer = f.write_to_buffer(excelize.Options(password="password"))
+            except (RuntimeError, TypeError) as err:
+                print(err)
+            ```
+        """
+        prepare_args(
+            [opts[0]] if opts else [],
+            [argsRule("opts", [Options], True)],
+        )
+        options = (
+            byref(py_value_to_c(opts[0], types_go._Options()))
+            if opts
+            else POINTER(types_go._Options)()
+        )
+        res = lib.WriteToBuffer(self.file_index, options)
+        try:
+            err = res.Err.decode(ENCODE)
+            if not err:
+                return string_at(res.Arr, res.ArrLen)
+            raise new_error(err, res.ErrCode)
+        finally:
+            free_result(res)
 
 
 def cell_name_to_coordinates(cell: str) -> Tuple[int, int]:
@@ -7342,12 +8699,66 @@ def cell_name_to_coordinates(cell: str) -> Tuple[int, int]:
         if no error occurred, otherwise raise a RuntimeError with the message.
     """
     prepare_args([cell], [argsRule("cell", [str])])
-    lib.CellNameToCoordinates.restype = types_go._CellNameToCoordinatesResult
     res = lib.CellNameToCoordinates(cell.encode(ENCODE))
-    err = res.err.decode(ENCODE)
-    if not err:
-        return res.col, res.row
-    raise RuntimeError(err)
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            return res.col, res.row
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
+
+
+def check_abi_version() -> None:
+    """
+    Check the shared library exports the functions used by the Python package,
+    and its ABI version is the version expected by the Python package, this
+    function is called when the package was imported.
+
+    Raises:
+        ImportError: If the shared library doesn't match the Python package.
+    """
+    prefix = (
+        "the shared library doesn't match the excelize Python package "
+        f"{__version__}"
+    )
+    missing = [name for name in restypes if not hasattr(lib, name)]
+    if missing:
+        raise ImportError(f"{prefix}, missing functions: {', '.join(missing)}")
+    version = get_build_info().abi_version
+    if version != abi_version:
+        raise ImportError(
+            f"{prefix}, expected ABI version {abi_version}, got {version}"
+        )
+
+
+def check_converters() -> List[str]:
+    """
+    Check the converters of the option types exchanged with the shared library.
+    It walks the fields of all option types, and returns the fields which can
+    not be converted between the Go and C structures. This is useful to verify
+    the types declarations are consistent with the excelize library.
+
+    Returns:
+        List[str]: Return the fields which can not be converted with the
+        reasons, or an empty list if all fields can be converted.
+
+    Example:
+        For example, print the fields which can not be converted:
+
+        ```python
+        for problem in excelize.check_converters():
+            print(problem)
+        ```
+    """
+    res = lib.CheckConverters()
+    try:
+        result = c_value_to_py(res, StringArrayErrorResult())
+        if not result.err:
+            return result.arr if result.arr else []
+        raise new_error(result.err, res.ErrCode)
+    finally:
+        free_result(res)
 
 
 def column_name_to_number(name: str) -> int:
@@ -7363,12 +8774,14 @@ def column_name_to_number(name: str) -> int:
         otherwise raise a RuntimeError with the message.
     """
     prepare_args([name], [argsRule("name", [str])])
-    lib.ColumnNameToNumber.restype = types_go._IntErrorResult
     res = lib.ColumnNameToNumber(name.encode(ENCODE))
-    err = res.err.decode(ENCODE)
-    if not err:
-        return res.val
-    raise RuntimeError(err)
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            return res.val
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
 
 
 def column_number_to_name(num: int) -> str:
@@ -7383,12 +8796,14 @@ def column_number_to_name(num: int) -> str:
         raise a RuntimeError with the message.
     """
     prepare_args([num], [argsRule("num", [int])])
-    lib.ColumnNumberToName.restype = types_go._StringErrorResult
     res = lib.ColumnNumberToName(c_longlong(num))
-    err = res.err.decode(ENCODE)
-    if not err:
-        return res.val.decode(ENCODE)
-    raise RuntimeError(err)
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            return res.val.decode(ENCODE)
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
 
 
 def coordinates_to_cell_name(col: int, row: int, *is_absolute: bool) -> str:
@@ -7414,15 +8829,47 @@ def coordinates_to_cell_name(col: int, row: int, *is_absolute: bool) -> str:
             argsRule("is_absolute", [bool], True),
         ],
     )
-    lib.CoordinatesToCellName.restype = types_go._StringErrorResult
     options = False
     if len(is_absolute) > 0:
         options = is_absolute[0]
     res = lib.CoordinatesToCellName(c_longlong(col), c_longlong(row), options)
-    err = res.err.decode(ENCODE)
-    if not err:
-        return res.val.decode(ENCODE)
-    raise RuntimeError(err)
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            return res.val.decode(ENCODE)
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
+
+
+def get_build_info() -> BuildInfoResult:
+    """
+    Get the build information of the shared library, including the version and
+    the ABI version of the library, the linked excelize library and Go, and the
+    registered image decoders. This is useful to verify the Python package and
+    the shared library are matched.
+
+    Returns:
+        BuildInfoResult: Return the build information if no error occurred,
+        otherwise raise a RuntimeError with the message.
+
+    Example:
+        For example, verify the version of the shared library on startup:
+
+        ```python
+        info = excelize.get_build_info()
+        if info.version != excelize.__version__:
+            raise RuntimeError(f"mismatched shared library {info.version}")
+        ```
+    """
+    res = lib.GetBuildInfo()
+    try:
+        err = res.Err.decode(ENCODE)
+        if not err:
+            return c_value_to_py(res, BuildInfoResult())
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
 
 
 def join_cell_name(col: str, row: int) -> str:
@@ -7438,12 +8885,42 @@ def join_cell_name(col: str, row: int) -> str:
         RuntimeError with the message.
     """
     prepare_args([col, row], [argsRule("col", [str]), argsRule("row", [int])])
-    lib.JoinCellName.restype = types_go._StringErrorResult
     res = lib.JoinCellName(col.encode(ENCODE), c_longlong(row))
-    err = res.err.decode(ENCODE)
-    if not err:
-        return res.val.decode(ENCODE)
-    raise RuntimeError(err)
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            return res.val.decode(ENCODE)
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
+
+
+def list_handles() -> ListHandlesResult:
+    """
+    Get the live handles of the workbooks, rows iterators, stream writers and
+    columns iterators, which have not been released by the `close`,
+    `Rows.close`, `StreamWriter.flush` or `Cols.close` functions. This is useful
+    to diagnose the handles leaks.
+
+    Returns:
+        ListHandlesResult: Return the live handles if no error occurred,
+        otherwise raise a RuntimeError with the message.
+
+    Example:
+        For example, print the handles of the workbooks that are not closed:
+
+        ```python
+        print(excelize.list_handles().files)
+        ```
+    """
+    res = lib.ListHandles()
+    try:
+        err = res.Err.decode(ENCODE)
+        if not err:
+            return c_value_to_py(res, ListHandlesResult())
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
 
 
 def new_file() -> File:
@@ -7456,7 +8933,11 @@ def new_file() -> File:
     return File(lib.NewFile())
 
 
-def open_file(filename: str, *opts: Options) -> File:
+def open_file(
+    filename: str,
+    *opts: Options,
+    progress: Optional[Callable[[ProgressEvent], Any]] = None,
+) -> File:
     """
     This function take the name of a spreadsheet file and returns a populated
     spreadsheet file struct for it.
@@ -7464,6 +8945,10 @@ def open_file(filename: str, *opts: Options) -> File:
     Args:
         filename (str): The path to the Excel file to open.
         *opts (Options): Optional parameters for opening the file.
+        progress (Optional[Callable[[ProgressEvent], Any]]): Optional progress
+        callback, which receives the number of bytes read in the read step and
+        then the parse step of the workbook, and will be registered for the
+        workbook, see `File.set_progress_callback`.
 
     Returns:
         File: Return a File object if if no error occurred, otherwise raise a
@@ -7476,23 +8961,41 @@ def open_file(filename: str, *opts: Options) -> File:
             argsRule("opts", [Options], True),
         ],
     )
-    lib.OpenFile.restype, options = types_go._IntErrorResult, None
+    progress_callback = new_progress_callback(progress)
+    options = None
     if len(opts) > 0:
         options = byref(py_value_to_c(opts[0], types_go._Options()))
-    res = lib.OpenFile(filename.encode(ENCODE), options)
-    err = res.err.decode(ENCODE)
-    if not err:
-        return File(res.val)
-    raise RuntimeError(err)
-
-
-def open_reader(buffer: bytes, *opts: Options) -> Optional[File]:
+    if progress_callback is None:
+        res = lib.OpenFile(filename.encode(ENCODE), options)
+    else:
+        res = lib.OpenFileWithProgress(
+            filename.encode(ENCODE), options, progress_callback
+        )
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            f = File(res.val)
+            f.progress_callback = progress_callback
+            return f
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
+
+
+def open_reader(
+    buffer: bytes,
+    *opts: Options,
+    progress: Optional[Callable[[ProgressEvent], Any]] = None,
+) -> Optional[File]:
     """
     Read data stream from bytes and return a populated spreadsheet file.
 
     Args:
         buffer (bytes): The contents buffer of the file
         *opts (Options): Optional parameters for opening the file.
+        progress (Optional[Callable[[ProgressEvent], Any]]): Optional progress
+        callback, which receives the parse step of the workbook and will be
+        registered for the workbook, see `File.set_progress_callback`.
 
     Returns:
         Tuple[Optional[File], Optional[Exception]]: A tuple containing a File
@@ -7505,14 +9008,25 @@ def open_reader(buffer: bytes, *opts: Options) -> Optional[File]:
             argsRule("opts", [Options], True),
         ],
     )
-    lib.OpenReader.restype, options = types_go._IntErrorResult, None
+    progress_callback = new_progress_callback(progress)
+    options = None
     if len(opts) > 0:
         options = byref(py_value_to_c(opts[0], types_go._Options()))
-    res = lib.OpenReader(cast(buffer, POINTER(c_ubyte)), len(buffer), options)
-    err = res.err.decode(ENCODE)
-    if err == "":
-        return File(res.val)
-    raise RuntimeError(err)
+    if progress_callback is None:
+        res = lib.OpenReader(cast(buffer, POINTER(c_ubyte)), len(buffer), options)
+    else:
+        res = lib.OpenReaderWithProgress(
+            cast(buffer, POINTER(c_ubyte)), len(buffer), options, progress_callback
+        )
+    try:
+        err = res.err.decode(ENCODE)
+        if err == "":
+            f = File(res.val)
+            f.progress_callback = progress_callback
+            return f
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
 
 
 def new_data_validation(allow_blank: bool) -> DataValidation:
@@ -7550,12 +9064,17 @@ def split_cell_name(cell: str) -> Tuple[str, int]:
         ```
     """
     prepare_args([cell], [argsRule("cell", [str])])
-    lib.SplitCellName.restype = types_go._StringIntErrorResult
     res = lib.SplitCellName(cell.encode(ENCODE))
-    err = res.err.decode(ENCODE)
-    if not err:
-        return (
-            res.strVal.decode(ENCODE),
-            res.intVal,
-        )
-    raise RuntimeError(err)
+    try:
+        err = res.err.decode(ENCODE)
+        if not err:
+            return (
+                res.strVal.decode(ENCODE),
+                res.intVal,
+            )
+        raise new_error(err, res.ErrCode)
+    finally:
+        free_result(res)
+
+
+check_abi_version()
@@ -19,9 +19,26 @@ import "C"
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 	"unsafe"
@@ -39,439 +56,2780 @@ type Cells struct {
 	Cell []string
 }
 type StringMatrixErrorResult struct {
-	Row []Cells
+	Row     []Cells
+	RowNum  []int
+	RowOpts []excelize.RowOpts
+}
+
+// rowsReader wraps the rows iterator to track the number of the current row,
+// which is the count of the found row elements.
+type rowsReader struct {
+	*excelize.Rows
+	rowNum int
+}
+
+// next will return true if it finds the next row element, and move the current
+// row number forward.
+func (r *rowsReader) next() bool {
+	if r.Next() {
+		r.rowNum++
+		return true
+	}
+	return false
 }
 
 const (
-	Nil     C.int = 0
-	Int     C.int = 1
-	Int32   C.int = 2
-	String  C.int = 3
-	Float   C.int = 4
-	Boolean C.int = 5
-	Time    C.int = 6
+	Nil      C.int = 0
+	Int      C.int = 1
+	Int32    C.int = 2
+	String   C.int = 3
+	Float    C.int = 4
+	Boolean  C.int = 5
+	Time     C.int = 6
+	Int64    C.int = 7
+	Uint64   C.int = 8
+	Duration C.int = 9
+	Error    C.int = 10
+	Formula  C.int = 11
 )
 
-var (
-	files, rowsIterator, sw = sync.Map{}, sync.Map{}, sync.Map{}
-	emptyString             string
-	errFilePtr              = "can not find file pointer"
-	errRowsIteratorPtr      = "can not find rows iterator pointer"
-	errStreamWriterPtr      = "can not find stream writer pointer"
-	errArgType              = errors.New("invalid argument data type")
-
-	// goBaseTypes defines Go's basic data types.
-	goBaseTypes = map[reflect.Kind]bool{
-		reflect.Bool:    true,
-		reflect.Int:     true,
-		reflect.Int8:    true,
-		reflect.Int16:   true,
-		reflect.Int32:   true,
-		reflect.Int64:   true,
-		reflect.Uint:    true,
-		reflect.Uint8:   true,
-		reflect.Uint16:  true,
-		reflect.Uint32:  true,
-		reflect.Uint64:  true,
-		reflect.Uintptr: true,
-		reflect.Float32: true,
-		reflect.Float64: true,
-		reflect.Map:     true,
-		reflect.String:  true,
+// handleRegistry allocates the handles of the workbooks, rows iterators and
+// stream writers exchanged with the caller. The handles are monotonic and
+// never reused, so a released handle can not refer to another live object,
+// and will be rejected with a distinct error.
+type handleRegistry struct {
+	last        atomic.Int32
+	items       sync.Map
+	locks       sync.Map
+	callbacks   sync.Map
+	scopes      sync.Map
+	parents     sync.Map
+	errNotFound error
+	errReleased error
+}
+
+// handleError is the error of the handle which was never allocated or has been
+// released.
+type handleError struct {
+	name     string
+	released bool
+}
+
+// Error returns the message of the handle error.
+func (err handleError) Error() string {
+	if err.released {
+		return fmt.Sprintf("%s pointer has been released", err.name)
 	}
-	// cToBaseGoTypeFuncs defined functions mapping for G to Go basic data types
-	// convention.
-	cToBaseGoTypeFuncs = map[reflect.Kind]func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error){
-		reflect.Bool: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(cVal.Bool()), nil
-		},
-		reflect.Uint: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(uint(cVal.Interface().(C.uint))), nil
-		},
-		reflect.Uint8: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(uint8(cVal.Interface().(C.uchar))), nil
-		},
-		reflect.Uint64: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(uint64(cVal.Interface().(C.uint))), nil
-		},
-		reflect.Int: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(int(cVal.Int())), nil
-		},
-		reflect.Int64: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(cVal.Int()), nil
-		},
-		reflect.Float64: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(float64(cVal.Float())), nil
-		},
-		reflect.String: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			if cVal.Elem().CanAddr() {
-				return reflect.ValueOf(C.GoString(cVal.Interface().(*C.char))), nil
+	return fmt.Sprintf("can not find %s pointer", err.name)
+}
+
+// tableNotExistError is the error of the table which does not exist in the
+// workbook.
+type tableNotExistError struct {
+	name string
+}
+
+// Error returns the message of the table not exist error.
+func (err tableNotExistError) Error() string {
+	return fmt.Sprintf("table %s does not exist", err.name)
+}
+
+// handleLock is the read/write lock of a handle, the exports that only read
+// the object take the read lock, and the exports that modify it take the
+// write lock. The exports of the rows and columns iterators take the read lock
+// of the workbook they were created from, and the exports of the stream
+// writers take the write lock of it. The lock can be disabled for callers that
+// guarantee the handle will be used from a single thread.
+type handleLock struct {
+	sync.RWMutex
+	disabled atomic.Bool
+}
+
+// newHandleRegistry returns a handle registry with the given name of the
+// objects in it, the name used in the errors of the invalid handles.
+func newHandleRegistry(name string) *handleRegistry {
+	return &handleRegistry{
+		errNotFound: handleError{name: name},
+		errReleased: handleError{name: name, released: true},
+	}
+}
+
+// store saves the given object in the registry and returns a new handle of it.
+func (r *handleRegistry) store(val interface{}) int {
+	idx := int(r.last.Add(1))
+	r.locks.Store(idx, &handleLock{})
+	r.items.Store(idx, val)
+	return idx
+}
+
+// storeChild saves the iterator or stream writer created from the workbook of
+// the given handle, and returns a new handle of it.
+func (r *handleRegistry) storeChild(val interface{}, parent int) int {
+	idx := r.store(val)
+	r.parents.Store(idx, parent)
+	return idx
+}
+
+// parent returns the handle of the workbook which the iterator or stream
+// writer of the given handle was created from, it returns 0, the handle which
+// is never allocated, if the handle doesn't exist.
+func (r *handleRegistry) parent(idx int) int {
+	if parent, ok := r.parents.Load(idx); ok {
+		return parent.(int)
+	}
+	return 0
+}
+
+// load returns the object of the given handle, it returns an error if the
+// handle was never allocated or has been released.
+func (r *handleRegistry) load(idx int) (interface{}, error) {
+	if val, ok := r.items.Load(idx); ok {
+		return val, nil
+	}
+	if idx > 0 && idx <= int(r.last.Load()) {
+		return nil, r.errReleased
+	}
+	return nil, r.errNotFound
+}
+
+// release removes the given handle from the registry and returns its object.
+func (r *handleRegistry) release(idx int) (interface{}, error) {
+	if val, ok := r.items.LoadAndDelete(idx); ok {
+		r.locks.Delete(idx)
+		r.callbacks.Delete(idx)
+		r.parents.Delete(idx)
+		if scope, ok := r.scopes.LoadAndDelete(idx); ok {
+			scope.(*cancelScope).cancel()
+		}
+		return val, nil
+	}
+	return r.load(idx)
+}
+
+// rlock acquires the read lock of the given handle and returns the function
+// to release it. It does nothing if the handle doesn't exist or the locking
+// of it has been disabled.
+func (r *handleRegistry) rlock(idx int) func() {
+	if l, ok := r.locks.Load(idx); ok && !l.(*handleLock).disabled.Load() {
+		l.(*handleLock).RLock()
+		return l.(*handleLock).RUnlock
+	}
+	return func() {}
+}
+
+// lock acquires the write lock of the given handle and returns the function
+// to release it. It does nothing if the handle doesn't exist or the locking
+// of it has been disabled.
+func (r *handleRegistry) lock(idx int) func() {
+	if l, ok := r.locks.Load(idx); ok && !l.(*handleLock).disabled.Load() {
+		l.(*handleLock).Lock()
+		return l.(*handleLock).Unlock
+	}
+	return func() {}
+}
+
+// setLocking enables or disables the locking of the given handle.
+func (r *handleRegistry) setLocking(idx int, enabled bool) error {
+	if _, err := r.load(idx); err != nil {
+		return err
+	}
+	if l, ok := r.locks.Load(idx); ok {
+		l.(*handleLock).disabled.Store(!enabled)
+	}
+	return nil
+}
+
+// progressHandle is the progress callback registered for a handle, and the
+// number of rows written by the stream writer of the handle.
+type progressHandle struct {
+	callback C.ProgressCallback
+	rows     atomic.Int64
+}
+
+// setProgressCallback registers the progress callback of the given handle, a
+// nil callback removes the registered one.
+func (r *handleRegistry) setProgressCallback(idx int, callback C.ProgressCallback) error {
+	if _, err := r.load(idx); err != nil {
+		return err
+	}
+	if callback == nil {
+		r.callbacks.Delete(idx)
+		return nil
+	}
+	r.callbacks.Store(idx, &progressHandle{callback: callback})
+	return nil
+}
+
+// progress returns the reporter of the given operation on the handle, it
+// returns nil if no progress callback was registered for the handle.
+func (r *handleRegistry) progress(idx int, phase C.int, sheet string) *progressReporter {
+	if h, ok := r.callbacks.Load(idx); ok {
+		return &progressReporter{handle: h.(*progressHandle), phase: phase, sheet: sheet}
+	}
+	return nil
+}
+
+// cancelScope is the context shared by the operations in flight on a handle,
+// which will be canceled together.
+type cancelScope struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// context returns the context of the operations in flight on the given
+// handle, it will be canceled when the handle was canceled or released.
+func (r *handleRegistry) context(idx int) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	scope, loaded := r.scopes.LoadOrStore(idx, &cancelScope{ctx: ctx, cancel: cancel})
+	if loaded {
+		cancel()
+	}
+	return scope.(*cancelScope).ctx
+}
+
+// cancel cancels the operations in flight on the given handle, the operations
+// started after it will not be affected.
+func (r *handleRegistry) cancel(idx int) error {
+	if _, err := r.load(idx); err != nil {
+		return err
+	}
+	if scope, ok := r.scopes.LoadAndDelete(idx); ok {
+		scope.(*cancelScope).cancel()
+	}
+	return nil
+}
+
+// handles returns the live handles in the registry in ascending order.
+func (r *handleRegistry) handles() []int {
+	var handles []int
+	r.items.Range(func(key, _ interface{}) bool {
+		handles = append(handles, key.(int))
+		return true
+	})
+	sort.Ints(handles)
+	return handles
+}
+
+// progressChunkSize is the number of bytes read, serialized or written between
+// the progress events, and progressInterval is the interval of the progress
+// events of the operations which can not report the processed bytes.
+const (
+	progressChunkSize = 1 << 20
+	progressInterval  = 100 * time.Millisecond
+)
+
+// progressReporter reports the progress events of an operation to the progress
+// callback. All the methods can be called on a nil reporter, which does
+// nothing.
+type progressReporter struct {
+	handle *progressHandle
+	phase  C.int
+	sheet  string
+}
+
+// report sends a progress event of the given step to the callback, it returns
+// errCanceled if the callback returned a non-zero value. The return value of
+// the callback is ignored for the last event, since the operation has been
+// completed.
+func (p *progressReporter) report(step C.int, bytes, total int64, done bool) error {
+	if p == nil {
+		return nil
+	}
+	event := C.struct_ProgressEvent{
+		Phase:          p.phase,
+		Step:           step,
+		RowsProcessed:  C.longlong(p.handle.rows.Load()),
+		BytesProcessed: C.longlong(bytes),
+		BytesTotal:     C.longlong(total),
+		Done:           C.bool(done),
+	}
+	if p.sheet != "" {
+		event.Sheet = C.CString(p.sheet)
+		defer C.free(unsafe.Pointer(event.Sheet))
+	}
+	if C.invokeProgressCallback(p.handle.callback, &event) != 0 && !done {
+		return errCanceled
+	}
+	return nil
+}
+
+// progressReader reports the number of bytes read from the underlying reader
+// every progressChunkSize bytes, and stops reading once the operation has been
+// canceled.
+type progressReader struct {
+	io.Reader
+	progress    *progressReporter
+	read, total int64
+	reported    int64
+}
+
+// Read implements the io.Reader interface.
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	r.read += int64(n)
+	if r.read-r.reported >= progressChunkSize {
+		r.reported = r.read
+		if err := r.progress.report(C.ProgressStepRead, r.read, r.total, false); err != nil {
+			return n, err
+		}
+	}
+	return n, err
+}
+
+// progressZipWriter reports the number of bytes serialized into the parts of
+// the workbook every progressChunkSize bytes. Once the operation has been
+// canceled, all the following writes and creating parts return the error,
+// since the excelize library only returns the error of the last part.
+type progressZipWriter struct {
+	excelize.ZipWriter
+	progress          *progressReporter
+	written, reported int64
+	err               error
+}
+
+// progressZipEntry is a part of the workbook created by the progressZipWriter.
+type progressZipEntry struct {
+	io.Writer
+	zw *progressZipWriter
+}
+
+// Create implements the excelize.ZipWriter interface.
+func (zw *progressZipWriter) Create(name string) (io.Writer, error) {
+	if zw.err != nil {
+		return nil, zw.err
+	}
+	w, err := zw.ZipWriter.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return &progressZipEntry{Writer: w, zw: zw}, nil
+}
+
+// Write implements the io.Writer interface, which writes the part in chunks
+// of progressChunkSize bytes.
+func (w *progressZipEntry) Write(b []byte) (int, error) {
+	var written int
+	for written < len(b) {
+		if w.zw.err != nil {
+			return written, w.zw.err
+		}
+		n, err := w.Writer.Write(b[written:min(len(b), written+progressChunkSize)])
+		written += n
+		w.zw.written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		if w.zw.written-w.zw.reported >= progressChunkSize {
+			w.zw.reported = w.zw.written
+			w.zw.err = w.zw.progress.report(C.ProgressStepSerialize, w.zw.written, 0, false)
+		}
+	}
+	return written, w.zw.err
+}
+
+// progressWriter writes the workbook to the file in chunks of
+// progressChunkSize bytes and reports the progress after each chunk. The file
+// is created on the first write, so nothing will be created if the workbook
+// failed to be serialized.
+type progressWriter struct {
+	progress       *progressReporter
+	name           string
+	file           *os.File
+	written, total int64
+}
+
+// Write implements the io.Writer interface. The workbook is serialized into
+// memory before being written, so the total size is known from the first
+// write.
+func (w *progressWriter) Write(b []byte) (int, error) {
+	if w.file == nil {
+		if err := w.progress.report(C.ProgressStepWrite, 0, int64(len(b)), false); err != nil {
+			return 0, err
+		}
+		file, err := os.OpenFile(filepath.Clean(w.name), os.O_WRONLY|os.O_TRUNC|os.O_CREATE, os.ModePerm)
+		if err != nil {
+			return 0, err
+		}
+		w.file = file
+	}
+	w.total = max(w.total, w.written+int64(len(b)))
+	var written int
+	for written < len(b) {
+		n, err := w.file.Write(b[written:min(len(b), written+progressChunkSize)])
+		written += n
+		w.written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		if err := w.progress.report(C.ProgressStepWrite, w.written, w.total, false); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// openReader reads the workbook from the reader and reports the number of
+// bytes read, the total is the size of the workbook, or 0 if unknown. The
+// workbook will be parsed after it has been read completely.
+func openReader(r io.Reader, total int64, progress *progressReporter, opts excelize.Options) (*excelize.File, error) {
+	if err := progress.report(C.ProgressStepRead, 0, total, false); err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, total))
+	if _, err := buf.ReadFrom(&progressReader{Reader: r, progress: progress, total: total}); err != nil {
+		return nil, err
+	}
+	return openBytes(buf.Bytes(), progress, opts)
+}
+
+// openBytes parses the workbook from the bytes, and reports the parse step
+// before and after it, since the excelize library can not report the progress
+// of parsing the workbook.
+func openBytes(b []byte, progress *progressReporter, opts excelize.Options) (*excelize.File, error) {
+	size := int64(len(b))
+	if err := progress.report(C.ProgressStepParse, 0, size, false); err != nil {
+		return nil, err
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(b), opts)
+	if err != nil {
+		return nil, err
+	}
+	return f, progress.report(C.ProgressStepParse, size, size, true)
+}
+
+// openFile opens the workbook by given file name and reports the number of
+// bytes read, as the excelize.OpenFile function does.
+func openFile(filename string, progress *progressReporter, opts excelize.Options) (*excelize.File, error) {
+	file, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var total int64
+	if info, err := file.Stat(); err == nil {
+		total = info.Size()
+	}
+	f, err := openReader(file, total, progress, opts)
+	if err != nil {
+		return nil, err
+	}
+	f.Path = filename
+	return f, nil
+}
+
+// saveAs saves the workbook to the given path and reports the number of bytes
+// serialized into the parts of the workbook, and then the number of bytes
+// written, as the SaveAs function of the workbook does. The partially written
+// file will be removed if the operation failed or has been canceled.
+func saveAs(f *excelize.File, name string, progress *progressReporter, opts ...excelize.Options) error {
+	if len(name) > excelize.MaxFilePathLength {
+		return excelize.ErrMaxFilePathLength
+	}
+	if err := progress.report(C.ProgressStepSerialize, 0, 0, false); err != nil {
+		return err
+	}
+	zipWriter := f.ZipWriter
+	defer func() { f.ZipWriter = zipWriter }()
+	f.ZipWriter = func(w io.Writer) excelize.ZipWriter {
+		return &progressZipWriter{ZipWriter: zipWriter(w), progress: progress}
+	}
+	f.Path = name
+	w := &progressWriter{progress: progress, name: name}
+	err := f.Write(w, opts...)
+	if w.file != nil {
+		if closeErr := w.file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(w.name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return progress.report(C.ProgressStepWrite, w.written, w.total, true)
+}
+
+// save saves the workbook to its origin path and reports the progress, as the
+// Save function of the workbook does.
+func save(f *excelize.File, progress *progressReporter, opts ...excelize.Options) error {
+	if f.Path == emptyString {
+		return excelize.ErrSave
+	}
+	return saveAs(f, f.Path, progress, opts...)
+}
+
+// calcSlots limits the number of the calculations running in the background,
+// including the interrupted calculations which are still running, since the
+// excelize library can not stop them.
+var calcSlots = make(chan struct{}, runtime.NumCPU())
+
+// runCalc runs the calculation in a new goroutine until it returns or the
+// context is done, and reports a progress event every progressInterval if the
+// progress reporter isn't nil. The excelize library can not stop the
+// calculation, so the interrupted calculation keeps running in the background
+// until it returns, and the release function will be called after that, which
+// should release the lock of the handle and the slot of the calculation, before
+// the result was sent to the caller. The panic in the calculation is raised
+// again in the caller.
+func runCalc(ctx context.Context, progress *progressReporter, release func(), fn func() (string, error)) (string, error) {
+	type result struct {
+		val   string
+		err   error
+		panic interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		var res result
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					res.panic = fmt.Sprintf("%v\n%s", r, debug.Stack())
+				}
+			}()
+			res.val, res.err = fn()
+		}()
+		release()
+		done <- res
+	}()
+	var tick <-chan time.Time
+	if progress != nil {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case r := <-done:
+			if r.panic != nil {
+				panic(r.panic)
 			}
-			return reflect.ValueOf(""), nil
-		},
+			_ = progress.report(0, 0, 0, true)
+			return r.val, r.err
+		case <-tick:
+			if err := progress.report(0, 0, 0, false); err != nil {
+				return emptyString, errCalcAbandoned
+			}
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return emptyString, fmt.Errorf("%w, the calculation keeps running in the background until it returns", errCalcTimeout)
+			}
+			return emptyString, errCalcAbandoned
+		}
 	}
-	// goBaseValueToCFuncs defined functions mapping for Go basic data types
-	// value to C convention.
-	goBaseValueToCFuncs = map[reflect.Kind]func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error){
-		reflect.Bool: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C._Bool(goVal.Bool())), nil
-		},
-		reflect.Uint: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.uint(uint32(goVal.Uint()))), nil
-		},
-		reflect.Uint8: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.uint(uint32(goVal.Uint()))), nil
-		},
-		reflect.Uint32: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.uint(uint32(goVal.Uint()))), nil
-		},
-		reflect.Uint64: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.ulong(goVal.Uint())), nil
-		},
-		reflect.Int: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.int(int32(goVal.Int()))), nil
-		},
-		reflect.Int32: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.long(int32(goVal.Int()))), nil
-		},
-		reflect.Int64: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.longlong(int64(goVal.Int()))), nil
-		},
-		reflect.Float64: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.double(goVal.Float())), nil
-		},
-		reflect.String: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-			return reflect.ValueOf(C.CString(goVal.String())), nil
-		},
+}
+
+// calcContextError returns the error of the calculation which has not been
+// started for the done context.
+func calcContextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errCalcTimeout
+	}
+	return errCalcCanceled
+}
+
+// streamWriter is the stream writer exchanged with the caller. The rows and
+// the other calls are written straight to the stream writer of the workbook.
+// In append mode, they are written by the functions of the workbook instead,
+// after the existing rows of the worksheet.
+type streamWriter struct {
+	sheet      string
+	stream     *excelize.StreamWriter
+	rows       int
+	lastRow    int
+	appendMode *streamAppend
+}
+
+// streamAppend is the state of the stream writer in append mode, which
+// includes the workbook, the last row of the worksheet before appending, and
+// whether the worksheet contained tables before appending.
+type streamAppend struct {
+	file      *excelize.File
+	lastRow   int
+	hasTables bool
+}
+
+// openStreamWriter returns the stream writer for the worksheet. The existing
+// rows of the worksheet will be kept in append mode.
+func openStreamWriter(f *excelize.File, sheet string, appendMode bool) (*streamWriter, error) {
+	index, err := f.GetSheetIndex(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if index == -1 {
+		return nil, excelize.ErrSheetNotExist{SheetName: sheet}
+	}
+	w := &streamWriter{sheet: sheet}
+	if !appendMode {
+		if w.stream, err = f.NewStreamWriter(sheet); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	if w.appendMode, err = newStreamAppend(f, sheet); err != nil {
+		return nil, err
+	}
+	w.lastRow = w.appendMode.lastRow
+	return w, nil
+}
+
+// newStreamAppend returns the state of the stream writer in append mode by
+// given worksheet name. The last row is the last row read by the rows
+// iterator, which includes the rows without values.
+func newStreamAppend(f *excelize.File, sheet string) (*streamAppend, error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var lastRow int
+	for rows.Next() {
+		lastRow++
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &streamAppend{file: f, lastRow: lastRow, hasTables: len(tables) > 0}, nil
+}
+
+// call runs the call on the stream writer other than setting rows. In append
+// mode, the given function of the workbook applies the call instead.
+func (w *streamWriter) call(stream func(streamWriter *excelize.StreamWriter) error, file func(f *excelize.File, sheet string) error) error {
+	if w.appendMode != nil {
+		return file(w.appendMode.file, w.sheet)
+	}
+	return stream(w.stream)
+}
+
+// setRow writes the row by the stream writer of the workbook. In append mode,
+// the row is written by the functions of the workbook after the existing rows
+// of the worksheet.
+func (w *streamWriter) setRow(cell string, values []interface{}, opts []excelize.RowOpts) error {
+	col, row, err := excelize.CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	if w.appendMode == nil {
+		err = w.stream.SetRow(cell, values, opts...)
+	} else if row <= w.lastRow {
+		err = fmt.Errorf("row %d has already been written", row)
+	} else {
+		err = setSheetRow(w.appendMode.file, w.sheet, col, row, values, opts)
+	}
+	if err != nil {
+		return err
+	}
+	w.rows, w.lastRow = w.rows+1, row
+	return nil
+}
+
+// setSheetRow sets the row by the functions of the workbook as the stream
+// writer does, the nil values are skipped, and the values of the Cell type set
+// the style, the formula and the value or the rich text of the cells.
+func setSheetRow(f *excelize.File, sheet string, col, row int, values []interface{}, opts []excelize.RowOpts) error {
+	for _, opt := range opts {
+		if err := setRowOpts(f, sheet, row, opt); err != nil {
+			return err
+		}
+	}
+	for i, val := range values {
+		if val == nil {
+			continue
+		}
+		cell, err := excelize.CoordinatesToCellName(col+i, row)
+		if err != nil {
+			return err
+		}
+		c, ok := val.(excelize.Cell)
+		if !ok {
+			if err = f.SetCellValue(sheet, cell, val); err != nil {
+				return err
+			}
+			continue
+		}
+		if runs, ok := c.Value.([]excelize.RichTextRun); ok {
+			err = f.SetCellRichText(sheet, cell, runs)
+		} else if c.Value != nil {
+			err = f.SetCellValue(sheet, cell, c.Value)
+		}
+		if err != nil {
+			return err
+		}
+		if c.Formula != "" {
+			if err = f.SetCellFormula(sheet, cell, c.Formula); err != nil {
+				return err
+			}
+		}
+		if c.StyleID != 0 {
+			if err = f.SetCellStyle(sheet, cell, cell, c.StyleID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setRowOpts sets the height, style, visibility and outline level of the row
+// by the functions of the workbook.
+func setRowOpts(f *excelize.File, sheet string, row int, opts excelize.RowOpts) error {
+	if opts.Height > 0 {
+		if err := f.SetRowHeight(sheet, row, opts.Height); err != nil {
+			return err
+		}
+	}
+	if opts.StyleID > 0 {
+		if err := f.SetRowStyle(sheet, row, row, opts.StyleID); err != nil {
+			return err
+		}
+	}
+	if opts.Hidden {
+		if err := f.SetRowVisible(sheet, row, false); err != nil {
+			return err
+		}
+	}
+	if opts.OutlineLevel > 0 {
+		return f.SetRowOutlineLevel(sheet, row, uint8(opts.OutlineLevel))
+	}
+	return nil
+}
+
+// flush ends the streaming writing process. The flushing can be canceled
+// before the workbook was modified. In append mode, it extends the ranges of
+// the tables which end at the last row of the worksheet before appending, and
+// updates the dimension of the worksheet to the used range of it, the flushing
+// can be retried if it failed.
+func (w *streamWriter) flush(f *excelize.File, progress *progressReporter) error {
+	if err := progress.report(C.ProgressStepSerialize, 0, 0, false); err != nil {
+		return err
+	}
+	if w.appendMode != nil {
+		if err := w.appendMode.extendTables(w.sheet, w.lastRow); err != nil {
+			return err
+		}
+		return setUsedRangeDimension(f, w.sheet)
+	}
+	index, err := f.GetSheetIndex(w.sheet)
+	if err != nil {
+		return err
+	}
+	if index == -1 {
+		return excelize.ErrSheetNotExist{SheetName: w.sheet}
+	}
+	return w.stream.Flush()
+}
+
+// discard drops the stream writer without flushing it. The workbook would save
+// the rows written so far without ending them, so the stream writer of the
+// workbook is replaced by an empty one which has been flushed. In append mode,
+// it removes the appended rows, and the tables added on the worksheet which
+// didn't contain tables.
+func (w *streamWriter) discard(f *excelize.File) error {
+	if w.appendMode != nil {
+		return w.appendMode.removeRows(w.sheet, w.lastRow)
+	}
+	stream, err := f.NewStreamWriter(w.sheet)
+	if err != nil {
+		if errorCode(err) == C.ErrCodeSheetNotExist {
+			return nil
+		}
+		return err
+	}
+	return stream.Flush()
+}
+
+// removeRows removes the rows after the last row of the worksheet before
+// appending up to the given last row, from the bottom, so no rows will be
+// shifted. The tables added by the stream writer will be deleted if the
+// worksheet didn't contain tables.
+func (s *streamAppend) removeRows(sheet string, lastRow int) error {
+	for row := lastRow; row > s.lastRow; row-- {
+		if err := s.file.RemoveRow(sheet, row); err != nil {
+			return err
+		}
+	}
+	if s.hasTables {
+		return nil
+	}
+	tables, err := s.file.GetTables(sheet)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err = s.file.DeleteTable(table.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUsedRangeDimension updates the dimension of the worksheet to the used
+// range of it.
+func setUsedRangeDimension(f *excelize.File, sheet string) error {
+	cols, lastRow, err := getUsedRangeSize(f, sheet)
+	if err != nil || lastRow == 0 {
+		return err
+	}
+	bottomRightCell, err := excelize.CoordinatesToCellName(cols, lastRow)
+	if err != nil {
+		return err
+	}
+	return f.SetSheetDimension(sheet, "A1:"+bottomRightCell)
+}
+
+// tablePart maps the attributes and the columns of the table part, which are
+// read by the GetTableRows function but not provided by the excelize library.
+type tablePart struct {
+	XMLName        xml.Name `xml:"table"`
+	Name           string   `xml:"name,attr"`
+	HeaderRowCount *int     `xml:"headerRowCount,attr"`
+	TotalsRowCount int      `xml:"totalsRowCount,attr,omitempty"`
+	TableColumns   *struct {
+		TableColumn []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"tableColumn"`
+	} `xml:"tableColumns"`
+}
+
+// getTableParts returns the paths of the table parts of the worksheet, which
+// are found by the relationships of the worksheet.
+func getTableParts(f *excelize.File, sheet string) ([]string, error) {
+	sheetPath, err := getSheetXMLPath(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	rels, err := getRelationships(f, sheetPath)
+	if err != nil {
+		return nil, err
+	}
+	var tableParts []string
+	for _, rel := range rels {
+		if rel.Type == excelize.SourceRelationshipTable {
+			tableParts = append(tableParts, getPartPath(sheetPath, rel.Target))
+		}
+	}
+	return tableParts, nil
+}
+
+// getTablePart returns the decoded table part of the worksheet by given table
+// name, the table name is case-insensitive.
+func getTablePart(f *excelize.File, sheet, name string) (tablePart, error) {
+	var table tablePart
+	tableParts, err := getTableParts(f, sheet)
+	if err != nil {
+		return table, err
+	}
+	for _, tablePath := range tableParts {
+		content, ok := f.Pkg.Load(tablePath)
+		if !ok {
+			continue
+		}
+		if err = xml.Unmarshal(content.([]byte), &table); err != nil {
+			return table, err
+		}
+		if strings.EqualFold(table.Name, name) {
+			return table, nil
+		}
+		table = tablePart{}
+	}
+	return table, tableNotExistError{name: name}
+}
+
+// extendTables extends the ranges of the tables which end at the last row of
+// the worksheet before appending to the given last row, except the tables
+// with the totals row. Only the range reference of the table and the auto
+// filter in the table part are replaced, so the other content is kept as it
+// is.
+func (s *streamAppend) extendTables(sheet string, lastRow int) error {
+	if lastRow <= s.lastRow || !s.hasTables {
+		return nil
+	}
+	tableParts, err := getTableParts(s.file, sheet)
+	if err != nil {
+		return err
+	}
+	for _, tablePath := range tableParts {
+		content, ok := s.file.Pkg.Load(tablePath)
+		if !ok {
+			continue
+		}
+		output, err := extendTableRef(content.([]byte), s.lastRow, lastRow)
+		if err != nil {
+			return err
+		}
+		if output != nil {
+			s.file.Pkg.Store(tablePath, output)
+		}
+	}
+	return nil
+}
+
+// extendTableRef returns the table part with the range reference ending at the
+// given last row, if the table ends at the given row before extending and
+// doesn't have the totals row, otherwise it returns nil. The range reference
+// of the auto filter will be extended too if it's the same as the table.
+func extendTableRef(content []byte, fromRow, toRow int) ([]byte, error) {
+	var (
+		decoder     = xml.NewDecoder(bytes.NewReader(content))
+		output      []byte
+		ref, newRef string
+		offset      int64
+	)
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		el, ok := token.(xml.StartElement)
+		if !ok || (ref != "" && el.Name.Local != "autoFilter") {
+			continue
+		}
+		attrs := map[string]string{}
+		for _, attr := range el.Attr {
+			if attr.Name.Space == "" {
+				attrs[attr.Name.Local] = attr.Value
+			}
+		}
+		if ref == "" {
+			if el.Name.Local != "table" {
+				return nil, nil
+			}
+			coordinates, err := rangeRefToCoordinates(attrs["ref"])
+			if err != nil {
+				return nil, err
+			}
+			if coordinates[3] != fromRow || (attrs["totalsRowCount"] != "" && attrs["totalsRowCount"] != "0") {
+				return nil, nil
+			}
+			topLeftCell, _ := excelize.CoordinatesToCellName(coordinates[0], coordinates[1])
+			bottomRightCell, err := excelize.CoordinatesToCellName(coordinates[2], toRow)
+			if err != nil {
+				return nil, err
+			}
+			ref, newRef = attrs["ref"], topLeftCell+":"+bottomRightCell
+		} else if attrs["ref"] != ref {
+			continue
+		}
+		end := decoder.InputOffset()
+		loc := refAttrExp.FindSubmatchIndex(content[start:end])
+		if loc == nil {
+			continue
+		}
+		output = append(output, content[offset:start+int64(loc[2])]...)
+		output = append(output, newRef...)
+		offset = start + int64(loc[3])
+	}
+	if ref == "" {
+		return nil, nil
+	}
+	return append(output, content[offset:]...), nil
+}
+
+// version is the version of the library, which should be the same as the
+// __version__ of the Python package, the setup script reads the version of the
+// package from it, and the tests check they are the same.
+const version = "0.1.0"
+
+// abiVersion is the version of the ABI of the library, the Python package
+// checks it's the same as the abi_version of the package when it was imported.
+// It should be increased when the parameters or results of any exported
+// function, or the layout of any structure exchanged by them has been changed.
+const abiVersion = 1
+
+// imageDecoders defines the image formats of the decoders registered by the
+// blank imports of the library, which are used to read the sizes of the
+// pictures inserted from files or bytes. It should be updated together with
+// the blank imports.
+var imageDecoders = []string{"gif", "jpeg", "png", "tiff"}
+
+var (
+	files        = newHandleRegistry("file")
+	rowsIterator = newHandleRegistry("rows iterator")
+	colsIterator = newHandleRegistry("cols iterator")
+	sw           = newHandleRegistry("stream writer")
+	emptyString  string
+	errArgType   = errors.New("invalid argument data type")
+	errCanceled  = errors.New("operation canceled by the progress callback")
+
+	errCalcCanceled  = errors.New("calculation has been canceled")
+	errCalcTimeout   = errors.New("calculation timed out")
+	errCalcAbandoned = errors.New("stopped waiting for the calculation, which keeps running in the background until it returns")
+
+	errStreamAppendTable = errors.New("the worksheet already contains tables, can't add table by the stream writer in append mode")
+
+	// refAttrExp matches the range reference attribute in the start tag of the
+	// table or the auto filter in the table part.
+	refAttrExp = regexp.MustCompile(`\sref\s*=\s*["']([^"']*)["']`)
+
+	// goBaseTypes defines Go's basic data types, indexed by the kinds.
+	goBaseTypes = [reflect.UnsafePointer + 1]bool{
+		reflect.Bool:    true,
+		reflect.Int:     true,
+		reflect.Int8:    true,
+		reflect.Int16:   true,
+		reflect.Int32:   true,
+		reflect.Int64:   true,
+		reflect.Uint:    true,
+		reflect.Uint8:   true,
+		reflect.Uint16:  true,
+		reflect.Uint32:  true,
+		reflect.Uint64:  true,
+		reflect.Uintptr: true,
+		reflect.Float32: true,
+		reflect.Float64: true,
+		reflect.String:  true,
+	}
+	// cToBaseGoTypeFuncs defined functions mapping for G to Go basic data types
+	// convention, the numeric values are checked for overflow.
+	cToBaseGoTypeFuncs = map[reflect.Kind]func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error){
+		reflect.Bool: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return reflect.ValueOf(cVal.Bool()), nil
+		},
+		reflect.Uint: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(uint(0)))
+		},
+		reflect.Uint8: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(uint8(0)))
+		},
+		reflect.Uint16: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(uint16(0)))
+		},
+		reflect.Uint32: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(uint32(0)))
+		},
+		reflect.Uint64: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(uint64(0)))
+		},
+		reflect.Uintptr: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(uintptr(0)))
+		},
+		reflect.Int: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(int(0)))
+		},
+		reflect.Int8: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(int8(0)))
+		},
+		reflect.Int16: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(int16(0)))
+		},
+		reflect.Int32: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(int32(0)))
+		},
+		reflect.Int64: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(int64(0)))
+		},
+		reflect.Float32: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(float32(0)))
+		},
+		reflect.Float64: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(cVal, reflect.TypeOf(float64(0)))
+		},
+		reflect.String: func(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			if cVal.Elem().CanAddr() {
+				return reflect.ValueOf(C.GoString(cVal.Interface().(*C.char))), nil
+			}
+			return reflect.ValueOf(""), nil
+		},
+	}
+	// goBaseValueToCFuncs defined functions mapping for Go basic data types
+	// value to C convention, the numeric values are checked for overflow.
+	goBaseValueToCFuncs = map[reflect.Kind]func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error){
+		reflect.Bool: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return reflect.ValueOf(C._Bool(goVal.Bool())), nil
+		},
+		reflect.Uint: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.uint(0)))
+		},
+		reflect.Uint8: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.uchar(0)))
+		},
+		reflect.Uint16: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.ushort(0)))
+		},
+		reflect.Uint32: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.uint(0)))
+		},
+		reflect.Uint64: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.ulonglong(0)))
+		},
+		reflect.Uintptr: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.size_t(0)))
+		},
+		reflect.Int: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.int(0)))
+		},
+		reflect.Int8: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.schar(0)))
+		},
+		reflect.Int16: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.short(0)))
+		},
+		reflect.Int32: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.int(0)))
+		},
+		reflect.Int64: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.longlong(0)))
+		},
+		reflect.Float32: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.float(0)))
+		},
+		reflect.Float64: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return convertNumber(goVal, reflect.TypeOf(C.double(0)))
+		},
+		reflect.String: func(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+			return reflect.ValueOf(C.CString(goVal.String())), nil
+		},
+	}
+)
+
+// convertNumber converts the numeric value to the given numeric type, it
+// returns an error instead of truncating the value if it overflows the type.
+func convertNumber(val reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	result := reflect.New(typ).Elem()
+	var overflow bool
+	switch {
+	case val.CanInt() && result.CanInt():
+		overflow = result.OverflowInt(val.Int())
+		result.SetInt(val.Int())
+	case val.CanInt() && result.CanUint():
+		overflow = val.Int() < 0 || result.OverflowUint(uint64(val.Int()))
+		result.SetUint(uint64(val.Int()))
+	case val.CanUint() && result.CanInt():
+		overflow = val.Uint() > math.MaxInt64 || result.OverflowInt(int64(val.Uint()))
+		result.SetInt(int64(val.Uint()))
+	case val.CanUint() && result.CanUint():
+		overflow = result.OverflowUint(val.Uint())
+		result.SetUint(val.Uint())
+	case val.CanInt() && result.CanFloat():
+		result.SetFloat(float64(val.Int()))
+	case val.CanUint() && result.CanFloat():
+		result.SetFloat(float64(val.Uint()))
+	case val.CanFloat() && result.CanFloat():
+		overflow = result.OverflowFloat(val.Float())
+		result.SetFloat(val.Float())
+	default:
+		return result, errArgType
+	}
+	if overflow {
+		return result, fmt.Errorf("value %s overflows %s", formatNumber(val), typ.Kind())
+	}
+	return result, nil
+}
+
+// formatNumber returns the string of the given numeric value.
+func formatNumber(val reflect.Value) string {
+	switch {
+	case val.CanInt():
+		return strconv.FormatInt(val.Int(), 10)
+	case val.CanUint():
+		return strconv.FormatUint(val.Uint(), 10)
+	}
+	return strconv.FormatFloat(val.Float(), 'g', -1, 64)
+}
+
+// cToGoBaseType convert C value to Go basic data type variable.
+func cToGoBaseType(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+	fn, ok := cToBaseGoTypeFuncs[kind]
+	if !ok {
+		return reflect.ValueOf(nil), errArgType
+	}
+	return fn(cVal, kind)
+}
+
+// cToGoArray convert C language array to Go slice of the C elements, the
+// given C array is a pointer to the first element, for example: char ** or
+// struct Border *, and the slice shares the memory with the C array.
+func cToGoArray(cArray reflect.Value, cArrayLen int) reflect.Value {
+	return reflect.SliceAt(cArray.Type().Elem(), cArray.UnsafePointer(), cArrayLen)
+}
+
+// structConverter is the cached conversion plan between a Go structure type
+// and a C structure type, it saves the exported fields of the Go structure
+// with the indexes of the fields of the same name in the C structure, to
+// avoid looking up the fields by name in each conversion.
+type structConverter struct {
+	fields []fieldConverter
+}
+
+// fieldConverter is the conversion plan of an exported field of the Go
+// structure, the index of the C field is -1 if the C structure doesn't have
+// the field, and the index of the length field is -1 if it's not an array.
+type fieldConverter struct {
+	reflect.StructField
+	cIdx   int
+	cType  reflect.Type
+	lenIdx int
+}
+
+// converterKey is the key of the cached structure converters.
+type converterKey struct {
+	goType, cType reflect.Type
+}
+
+// structConverters caches the structure converters by the pair of the Go and
+// C structure types.
+var structConverters sync.Map
+
+// getStructConverter returns the cached converter between the given Go and C
+// structure types, it creates the converter on first use.
+func getStructConverter(goType, cType reflect.Type) *structConverter {
+	key := converterKey{goType: goType, cType: cType}
+	if conv, ok := structConverters.Load(key); ok {
+		return conv.(*structConverter)
+	}
+	conv := &structConverter{}
+	for i := 0; i < goType.NumField(); i++ {
+		field := goType.Field(i)
+		if unicode.IsLower(rune(field.Name[0])) {
+			continue
+		}
+		fc := fieldConverter{StructField: field, cIdx: -1, lenIdx: -1}
+		if cField, ok := cType.FieldByName(field.Name); ok {
+			fc.cIdx, fc.cType = cField.Index[0], cField.Type
+		}
+		if cField, ok := cType.FieldByName(field.Name + "Len"); ok {
+			fc.lenIdx = cField.Index[0]
+		}
+		conv.fields = append(conv.fields, fc)
+	}
+	actual, _ := structConverters.LoadOrStore(key, conv)
+	return actual.(*structConverter)
+}
+
+// cField returns the field of the given C structure value, it returns an
+// invalid value if the C structure doesn't have the field.
+func (fc *fieldConverter) cField(cVal reflect.Value) reflect.Value {
+	if fc.cIdx < 0 {
+		return reflect.Value{}
+	}
+	return cVal.Field(fc.cIdx)
+}
+
+// cLenField returns the length field of the given C structure value, it
+// returns an invalid value if the C structure doesn't have the field.
+func (fc *fieldConverter) cLenField(cVal reflect.Value) reflect.Value {
+	if fc.lenIdx < 0 {
+		return reflect.Value{}
+	}
+	return cVal.Field(fc.lenIdx)
+}
+
+// cValueToGo convert C language object to Go variable base on the given Go
+// structure types, this function extract each fields of the structure from
+// object recursively.
+func cValueToGo(cVal reflect.Value, goType reflect.Type) (reflect.Value, error) {
+	result := reflect.New(goType)
+	s := result.Elem()
+	conv := getStructConverter(goType, cVal.Type())
+	for fieldIdx := range conv.fields {
+		field := &conv.fields[fieldIdx]
+		resultFieldIdx := field.Index[0]
+		if goBaseTypes[field.Type.Kind()] {
+			cBaseVal := field.cField(cVal)
+			goBaseVal, err := cToGoBaseType(cBaseVal, field.Type.Kind())
+			if err != nil {
+				return result, err
+			}
+			s.Field(resultFieldIdx).Set(goBaseVal.Convert(s.Field(resultFieldIdx).Type()))
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Ptr:
+			// Pointer of the Go data type, for example: *excelize.Options or *string
+			ptrType := field.Type.Elem()
+			if !goBaseTypes[ptrType.Kind()] {
+				// Pointer of the Go struct, for example: *excelize.Options
+				cObjVal := field.cField(cVal)
+				if cObjVal.Kind() == reflect.Ptr && !cObjVal.IsNil() {
+					v, err := cValueToGo(cObjVal.Elem(), ptrType)
+					if err != nil {
+						return result, err
+					}
+					s.Field(resultFieldIdx).Set(v)
+				}
+			}
+			if goBaseTypes[ptrType.Kind()] {
+				// Pointer of the Go basic data type, for example: *string
+				cBaseVal := field.cField(cVal)
+				if !cBaseVal.IsNil() {
+					v, err := cToGoBaseType(cBaseVal.Elem(), ptrType.Kind())
+					if err != nil {
+						return result, err
+					}
+					x := reflect.New(ptrType)
+					x.Elem().Set(v)
+					s.Field(resultFieldIdx).Set(x.Elem().Addr())
+				}
+			}
+		case reflect.Struct:
+			// The Go struct, for example: excelize.Options, convert sub fields recursively
+			structType := field.Type
+			cObjVal := field.cField(cVal)
+			v, err := cValueToGo(cObjVal, structType)
+			if err != nil {
+				return result, err
+			}
+			s.Field(resultFieldIdx).Set(v.Elem())
+		case reflect.Slice:
+			// The Go data type array, for example:
+			// []*excelize.Options, []excelize.Options, []string, []*string
+			ele := field.Type.Elem()
+			cArray := field.cField(cVal)
+			if cArray.IsZero() {
+				continue
+			}
+			if ele.Kind() == reflect.Ptr {
+				// Pointer array of the Go data type, for example: []*excelize.Options or []*string
+				subEle := ele.Elem()
+				cArrayLen := int(field.cLenField(cVal).Int())
+				cArray = cToGoArray(cArray, cArrayLen)
+				for i := 0; i < cArray.Len(); i++ {
+					if goBaseTypes[subEle.Kind()] {
+						// Pointer array of the Go basic data type, for example: []*string
+						v, err := cToGoBaseType(cArray.Index(i), subEle.Kind())
+						if err != nil {
+							return result, err
+						}
+						x := reflect.New(subEle)
+						x.Elem().Set(v)
+						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), x.Elem().Addr()))
+					} else {
+						// Pointer array of the Go struct, for example: []*excelize.Options
+						v, err := cValueToGo(cArray.Index(i).Elem(), subEle)
+						if err != nil {
+							return result, err
+						}
+						x := reflect.New(subEle)
+						x.Elem().Set(v.Elem())
+						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), x.Elem().Addr()))
+					}
+				}
+			} else {
+				// The Go data type array, for example: []excelize.Options or []string
+				subEle := ele
+				cArrayLen := int(field.cLenField(cVal).Int())
+				if subEle.Kind() == reflect.Uint8 { // []byte
+					buf := C.GoBytes(unsafe.Pointer(cArray.Interface().(*C.uchar)), C.int(cArrayLen))
+					s.Field(resultFieldIdx).Set(reflect.ValueOf(buf))
+					continue
+				}
+				cArray = cToGoArray(cArray, cArrayLen)
+				for i := 0; i < cArray.Len(); i++ {
+					if goBaseTypes[subEle.Kind()] {
+						// The Go basic data type array, for example: []string
+						v, err := cToGoBaseType(cArray.Index(i), subEle.Kind())
+						if err != nil {
+							return result, err
+						}
+
+						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), v))
+					} else {
+						// The Go struct array, for example: []excelize.Options
+						v, err := cValueToGo(cArray.Index(i), subEle)
+						if err != nil {
+							return result, err
+						}
+						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), v.Elem()))
+					}
+				}
+			}
+		case reflect.Map:
+			// The Go map, for example: map[string]string, the C array of the
+			// structures with the "Key" and "Value" fields
+			cArray := field.cField(cVal)
+			if cArray.IsZero() {
+				continue
+			}
+			cArray = cToGoArray(cArray, int(field.cLenField(cVal).Int()))
+			goMap := reflect.MakeMapWithSize(field.Type, cArray.Len())
+			for i := 0; i < cArray.Len(); i++ {
+				k, err := cToGoValue(cArray.Index(i).FieldByName("Key"), field.Type.Key())
+				if err != nil {
+					return result, err
+				}
+				v, err := cToGoValue(cArray.Index(i).FieldByName("Value"), field.Type.Elem())
+				if err != nil {
+					return result, err
+				}
+				goMap.SetMapIndex(k, v)
+			}
+			s.Field(resultFieldIdx).Set(goMap)
+		}
+	}
+	return result, nil
+}
+
+// cToGoValue convert C language value to Go variable of the given Go type,
+// the Go type could be a basic data type, a structure or the pointer of them.
+func cToGoValue(cVal reflect.Value, goType reflect.Type) (reflect.Value, error) {
+	switch {
+	case goBaseTypes[goType.Kind()]:
+		v, err := cToGoBaseType(cVal, goType.Kind())
+		if err != nil {
+			return v, err
+		}
+		return v.Convert(goType), nil
+	case goType.Kind() == reflect.Struct:
+		v, err := cValueToGo(cVal, goType)
+		return v.Elem(), err
+	case goType.Kind() == reflect.Ptr && cVal.Kind() == reflect.Ptr:
+		if cVal.IsNil() {
+			return reflect.Zero(goType), nil
+		}
+		v, err := cToGoValue(cVal.Elem(), goType.Elem())
+		if err != nil {
+			return v, err
+		}
+		ptr := reflect.New(goType.Elem())
+		ptr.Elem().Set(v)
+		return ptr, nil
+	}
+	return reflect.Value{}, errArgType
+}
+
+// optionTypes defines the pairs of the Go and C structure types of the options
+// exchanged with the caller, which are converted by cValueToGo and goValueToC.
+var optionTypes = [][2]reflect.Type{
+	{reflect.TypeOf(excelize.AppProperties{}), reflect.TypeOf(C.struct_AppProperties{})},
+	{reflect.TypeOf(excelize.AutoFilterOptions{}), reflect.TypeOf(C.struct_AutoFilterOptions{})},
+	{reflect.TypeOf(excelize.CalcPropsOptions{}), reflect.TypeOf(C.struct_CalcPropsOptions{})},
+	{reflect.TypeOf(excelize.Chart{}), reflect.TypeOf(C.struct_Chart{})},
+	{reflect.TypeOf(excelize.Comment{}), reflect.TypeOf(C.struct_Comment{})},
+	{reflect.TypeOf(excelize.ConditionalFormatOptions{}), reflect.TypeOf(C.struct_ConditionalFormatOptions{})},
+	{reflect.TypeOf(excelize.DataValidation{}), reflect.TypeOf(C.struct_DataValidation{})},
+	{reflect.TypeOf(excelize.DefinedName{}), reflect.TypeOf(C.struct_DefinedName{})},
+	{reflect.TypeOf(excelize.DocProperties{}), reflect.TypeOf(C.struct_DocProperties{})},
+	{reflect.TypeOf(excelize.FormControl{}), reflect.TypeOf(C.struct_FormControl{})},
+	{reflect.TypeOf(excelize.FormulaOpts{}), reflect.TypeOf(C.struct_FormulaOpts{})},
+	{reflect.TypeOf(excelize.GraphicOptions{}), reflect.TypeOf(C.struct_GraphicOptions{})},
+	{reflect.TypeOf(excelize.HeaderFooterImageOptions{}), reflect.TypeOf(C.struct_HeaderFooterImageOptions{})},
+	{reflect.TypeOf(excelize.HeaderFooterOptions{}), reflect.TypeOf(C.struct_HeaderFooterOptions{})},
+	{reflect.TypeOf(excelize.HyperlinkOpts{}), reflect.TypeOf(C.struct_HyperlinkOpts{})},
+	{reflect.TypeOf(excelize.Options{}), reflect.TypeOf(C.struct_Options{})},
+	{reflect.TypeOf(excelize.PageLayoutMarginsOptions{}), reflect.TypeOf(C.struct_PageLayoutMarginsOptions{})},
+	{reflect.TypeOf(excelize.PageLayoutOptions{}), reflect.TypeOf(C.struct_PageLayoutOptions{})},
+	{reflect.TypeOf(excelize.Panes{}), reflect.TypeOf(C.struct_Panes{})},
+	{reflect.TypeOf(excelize.Picture{}), reflect.TypeOf(C.struct_Picture{})},
+	{reflect.TypeOf(excelize.PivotTableOptions{}), reflect.TypeOf(C.struct_PivotTableOptions{})},
+	{reflect.TypeOf(excelize.RichTextRun{}), reflect.TypeOf(C.struct_RichTextRun{})},
+	{reflect.TypeOf(excelize.RowOpts{}), reflect.TypeOf(C.struct_RowOpts{})},
+	{reflect.TypeOf(excelize.Shape{}), reflect.TypeOf(C.struct_Shape{})},
+	{reflect.TypeOf(excelize.SheetPropsOptions{}), reflect.TypeOf(C.struct_SheetPropsOptions{})},
+	{reflect.TypeOf(excelize.SheetProtectionOptions{}), reflect.TypeOf(C.struct_SheetProtectionOptions{})},
+	{reflect.TypeOf(excelize.SlicerOptions{}), reflect.TypeOf(C.struct_SlicerOptions{})},
+	{reflect.TypeOf(excelize.SparklineOptions{}), reflect.TypeOf(C.struct_SparklineOptions{})},
+	{reflect.TypeOf(excelize.Style{}), reflect.TypeOf(C.struct_Style{})},
+	{reflect.TypeOf(excelize.Table{}), reflect.TypeOf(C.struct_Table{})},
+	{reflect.TypeOf(excelize.ViewOptions{}), reflect.TypeOf(C.struct_ViewOptions{})},
+	{reflect.TypeOf(excelize.WorkbookPropsOptions{}), reflect.TypeOf(C.struct_WorkbookPropsOptions{})},
+	{reflect.TypeOf(excelize.WorkbookProtectionOptions{}), reflect.TypeOf(C.struct_WorkbookProtectionOptions{})},
+}
+
+// checkConverter walks the fields of the given Go structure type recursively
+// and returns the fields which can not be converted to or from the fields of
+// the given C structure type, the fields are reported with their paths.
+func checkConverter(goType, cType reflect.Type, path string, checked map[converterKey]bool) []string {
+	key := converterKey{goType: goType, cType: cType}
+	if checked[key] {
+		return nil
+	}
+	checked[key] = true
+	var problems []string
+	checkBaseType := func(path string, kind reflect.Kind) {
+		_, okC := cToBaseGoTypeFuncs[kind]
+		_, okGo := goBaseValueToCFuncs[kind]
+		if !okC || !okGo {
+			problems = append(problems, fmt.Sprintf("%s: unsupported data type %s", path, kind))
+		}
+	}
+	for _, field := range getStructConverter(goType, cType).fields {
+		fieldPath := path + "." + field.Name
+		if field.cIdx < 0 {
+			problems = append(problems, fieldPath+": missing field in the C structure")
+			continue
+		}
+		switch kind := field.Type.Kind(); {
+		case goBaseTypes[kind]:
+			checkBaseType(fieldPath, kind)
+		case kind == reflect.Ptr && goBaseTypes[field.Type.Elem().Kind()]:
+			checkBaseType(fieldPath, field.Type.Elem().Kind())
+		case kind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct && field.cType.Kind() == reflect.Ptr:
+			problems = append(problems, checkConverter(field.Type.Elem(), field.cType.Elem(), fieldPath, checked)...)
+		case kind == reflect.Struct && field.cType.Kind() == reflect.Struct:
+			problems = append(problems, checkConverter(field.Type, field.cType, fieldPath, checked)...)
+		case kind == reflect.Slice && field.lenIdx < 0:
+			problems = append(problems, fieldPath+": missing length field in the C structure")
+		case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.Uint8:
+		case kind == reflect.Slice && goBaseTypes[field.Type.Elem().Kind()]:
+			checkBaseType(fieldPath, field.Type.Elem().Kind())
+		case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.Ptr && goBaseTypes[field.Type.Elem().Elem().Kind()]:
+			checkBaseType(fieldPath, field.Type.Elem().Elem().Kind())
+		case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.Ptr && field.Type.Elem().Elem().Kind() == reflect.Struct &&
+			field.cType.Kind() == reflect.Ptr && field.cType.Elem().Kind() == reflect.Ptr:
+			problems = append(problems, checkConverter(field.Type.Elem().Elem(), field.cType.Elem().Elem(), fieldPath, checked)...)
+		case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct && field.cType.Kind() == reflect.Ptr:
+			problems = append(problems, checkConverter(field.Type.Elem(), field.cType.Elem(), fieldPath, checked)...)
+		case kind == reflect.Map && field.lenIdx < 0:
+			problems = append(problems, fieldPath+": missing length field in the C structure")
+		case kind == reflect.Map && field.cType.Kind() == reflect.Ptr && field.cType.Elem().Kind() == reflect.Struct:
+			for _, entry := range []struct {
+				name   string
+				goType reflect.Type
+			}{{"Key", field.Type.Key()}, {"Value", field.Type.Elem()}} {
+				cEntry, ok := field.cType.Elem().FieldByName(entry.name)
+				switch {
+				case !ok:
+					problems = append(problems, fmt.Sprintf("%s: missing field %s in the C structure", fieldPath, entry.name))
+				case goBaseTypes[entry.goType.Kind()]:
+					checkBaseType(fieldPath+"."+entry.name, entry.goType.Kind())
+				case entry.goType.Kind() == reflect.Struct && cEntry.Type.Kind() == reflect.Struct:
+					problems = append(problems, checkConverter(entry.goType, cEntry.Type, fieldPath+"."+entry.name, checked)...)
+				default:
+					problems = append(problems, fmt.Sprintf("%s.%s: can not convert %s to %s", fieldPath, entry.name, entry.goType, cEntry.Type))
+				}
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: can not convert %s to %s", fieldPath, field.Type, field.cType))
+		}
+	}
+	return problems
+}
+
+// goBaseTypeToC convert Go basic data type value to C variable.
+func goBaseTypeToC(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
+	fn, ok := goBaseValueToCFuncs[kind]
+	if !ok {
+		return reflect.ValueOf(nil), errors.New("invalid argument data type" + kind.String())
+	}
+	return fn(goVal, kind)
+}
+
+// goBaseTypeToCType convert Go basic data type value to C variable of the
+// given C type, it returns an error if the numeric value overflows the type.
+func goBaseTypeToCType(goVal reflect.Value, cType reflect.Type) (reflect.Value, error) {
+	cVal, err := goBaseTypeToC(goVal, goVal.Kind())
+	if err != nil {
+		return cVal, err
+	}
+	if cVal.CanInt() || cVal.CanUint() || cVal.CanFloat() {
+		return convertNumber(cVal, cType)
+	}
+	return cVal.Convert(cType), nil
+}
+
+// goValueToC convert Go variable to C object base on the given Go structure
+// types, this function extract each fields of the structure from structure
+// variable recursively.
+func goValueToC(goVal, cVal reflect.Value) (reflect.Value, error) {
+	result := cVal
+	c := result.Elem()
+	conv := getStructConverter(goVal.Type(), c.Type())
+	for fieldIdx := range conv.fields {
+		field := &conv.fields[fieldIdx]
+		i := field.Index[0]
+		if goBaseTypes[field.Type.Kind()] {
+			cBaseVal, err := goBaseTypeToCType(goVal.Field(i), field.cType)
+			if err != nil {
+				return result, err
+			}
+			field.cField(c).Set(cBaseVal)
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Ptr:
+			// Pointer of the Go data type, for example: *excelize.Options or *string
+			ptrType := field.Type.Elem()
+			if !goBaseTypes[ptrType.Kind()] {
+				// Pointer of the Go struct, for example: *excelize.Options
+				goStructVal := goVal.Field(i)
+				if !goStructVal.IsNil() {
+					cPtr := C.malloc(C.size_t(field.cType.Elem().Size()))
+					cStructPtr := reflect.NewAt(field.cType.Elem(), cPtr)
+					v, err := goValueToC(goStructVal.Elem(), cStructPtr)
+					if err != nil {
+						return result, err
+					}
+					field.cField(c).Set(v)
+				} else {
+					// The C structures of the array elements were allocated
+					// by malloc without initialization, clear the pointer
+					field.cField(c).Set(reflect.Zero(field.cType))
+				}
+			}
+			if goBaseTypes[ptrType.Kind()] {
+				// Pointer of the Go basic data type, for example: *string
+				goBaseVal := goVal.Field(i)
+				if !goBaseVal.IsNil() {
+					v, err := goBaseTypeToCType(goBaseVal.Elem(), field.cType.Elem())
+					if err != nil {
+						return result, err
+					}
+					// Allocate the size of the C value, instead of the size of
+					// the pointer
+					cValPtr := C.malloc(C.size_t(field.cType.Elem().Size()))
+					ptrVal := reflect.NewAt(v.Type(), cValPtr).Elem()
+					ptrVal.Set(v)
+					field.cField(c).Set(ptrVal.Addr())
+				} else {
+					field.cField(c).Set(reflect.Zero(field.cType))
+				}
+			}
+		case reflect.Struct:
+			// The Go struct, for example: excelize.Options, convert sub fields recursively
+			goStructVal := goVal.Field(i)
+			v, err := goValueToC(goStructVal, reflect.New(field.cType))
+			if err != nil {
+				return result, err
+			}
+			field.cField(c).Set(v.Elem())
+		case reflect.Slice:
+			// The Go data type array, for example:
+			// []*excelize.Options, []excelize.Options, []string, []*string
+			goSlice := goVal.Field(i)
+			ele := goSlice.Type().Elem()
+			l, err := goBaseTypeToCType(reflect.ValueOf(goSlice.Len()), field.cLenField(c).Type())
+			if err != nil {
+				return result, err
+			}
+			field.cLenField(c).Set(l)
+			if ele.Kind() == reflect.Uint8 { // []byte
+				byteSlice := goSlice.Bytes()
+				cArray := C.malloc(C.size_t(len(byteSlice)))
+				cSlice := unsafe.Slice((*byte)(cArray), len(byteSlice))
+				copy(cSlice, byteSlice)
+				field.cField(c).Set(reflect.ValueOf((*C.uchar)(cArray)))
+				continue
+			}
+			cArray := C.malloc(C.size_t(goSlice.Len()) * C.size_t(field.cType.Elem().Size()))
+			for j := 0; j < goSlice.Len(); j++ {
+				if goBaseTypes[ele.Kind()] {
+					// The Go basic data type array, for example: []string
+					cBaseVal, err := goBaseTypeToCType(goSlice.Index(j), field.cType.Elem())
+					if err != nil {
+						return result, err
+					}
+					elePtr := unsafe.Pointer(uintptr(cArray) + uintptr(j)*cBaseVal.Type().Size())
+					ele := reflect.NewAt(cBaseVal.Type(), elePtr).Elem()
+					ele.Set(cBaseVal)
+				} else {
+					// The Go struct array, for example: []excelize.Options
+					cPtr := C.malloc(C.size_t(field.cType.Elem().Size()))
+					cStructPtr := reflect.NewAt(field.cType.Elem(), cPtr)
+					v, err := goValueToC(goSlice.Index(j), cStructPtr)
+					if err != nil {
+						return result, err
+					}
+					elePtr := unsafe.Pointer(uintptr(cArray) + uintptr(j)*field.cType.Elem().Size())
+					ele := reflect.NewAt(field.cType.Elem(), elePtr).Elem()
+					ele.Set(reflect.NewAt(field.cType.Elem(), unsafe.Pointer(v.Pointer())).Elem())
+					C.free(cPtr)
+				}
+			}
+			field.cField(c).Set(reflect.NewAt(field.cType.Elem(), cArray))
+		case reflect.Map:
+			// The Go map, for example: map[string]string, the C array of the
+			// structures with the "Key" and "Value" fields sorted by the keys
+			goMap := goVal.Field(i)
+			l, err := goBaseTypeToCType(reflect.ValueOf(goMap.Len()), field.cLenField(c).Type())
+			if err != nil {
+				return result, err
+			}
+			field.cLenField(c).Set(l)
+			keys := goMap.MapKeys()
+			sort.Slice(keys, func(a, b int) bool { return lessValue(keys[a], keys[b]) })
+			entryType := field.cType.Elem()
+			cArray := C.calloc(C.size_t(len(keys)), C.size_t(entryType.Size()))
+			field.cField(c).Set(reflect.NewAt(entryType, cArray))
+			for j, key := range keys {
+				entry := reflect.NewAt(entryType, unsafe.Pointer(uintptr(cArray)+uintptr(j)*entryType.Size())).Elem()
+				k, err := goToCValue(key, entry.FieldByName("Key").Type())
+				if err != nil {
+					return result, err
+				}
+				entry.FieldByName("Key").Set(k)
+				v, err := goToCValue(goMap.MapIndex(key), entry.FieldByName("Value").Type())
+				if err != nil {
+					return result, err
+				}
+				entry.FieldByName("Value").Set(v)
+			}
+		}
+	}
+	return result, nil
+}
+
+// goToCValue convert Go variable to C value of the given C type, the Go
+// variable could be a basic data type, a structure or the pointer of them.
+func goToCValue(goVal reflect.Value, cType reflect.Type) (reflect.Value, error) {
+	switch {
+	case goBaseTypes[goVal.Kind()]:
+		return goBaseTypeToCType(goVal, cType)
+	case goVal.Kind() == reflect.Struct:
+		v, err := goValueToC(goVal, reflect.New(cType))
+		return v.Elem(), err
+	case goVal.Kind() == reflect.Ptr && cType.Kind() == reflect.Ptr:
+		if goVal.IsNil() {
+			return reflect.Zero(cType), nil
+		}
+		v, err := goToCValue(goVal.Elem(), cType.Elem())
+		if err != nil {
+			return reflect.Zero(cType), err
+		}
+		cPtr := reflect.NewAt(cType.Elem(), C.calloc(1, C.size_t(cType.Elem().Size())))
+		cPtr.Elem().Set(v)
+		return cPtr, nil
+	}
+	return reflect.Zero(cType), errArgType
+}
+
+// lessValue reports whether the Go basic data type value a is less than b,
+// it's used to sort the keys of the maps.
+func lessValue(a, b reflect.Value) bool {
+	switch {
+	case a.CanInt():
+		return a.Int() < b.Int()
+	case a.CanUint():
+		return a.Uint() < b.Uint()
+	case a.CanFloat():
+		return a.Float() < b.Float()
+	case a.Kind() == reflect.String:
+		return a.String() < b.String()
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// cArrayLenIndexes caches the indexes of the length fields of the C structure
+// fields by the C structure types.
+var cArrayLenIndexes sync.Map
+
+// getCArrayLenIndexes returns the index of the length field with the "Len"
+// suffix for each field of the given C structure type, the index is -1 if the
+// field doesn't have a length field.
+func getCArrayLenIndexes(cType reflect.Type) []int {
+	if indexes, ok := cArrayLenIndexes.Load(cType); ok {
+		return indexes.([]int)
+	}
+	indexes := make([]int, cType.NumField())
+	for i := range indexes {
+		indexes[i] = -1
+		if field, ok := cType.FieldByName(cType.Field(i).Name + "Len"); ok {
+			indexes[i] = field.Index[0]
+		}
+	}
+	actual, _ := cArrayLenIndexes.LoadOrStore(cType, indexes)
+	return actual.([]int)
+}
+
+// freeCPointer releases the C memory of the given C pointer and the memory
+// referenced by the value it points to.
+func freeCPointer(cPtr reflect.Value) {
+	if cPtr.IsNil() {
+		return
+	}
+	switch cPtr.Elem().Kind() {
+	case reflect.Ptr:
+		// Pointer of the C pointer, for example: char **
+		freeCPointer(cPtr.Elem())
+	case reflect.Struct:
+		// Pointer of the C struct, for example: struct Options *
+		freeCValue(cPtr.Elem())
+	}
+	C.free(cPtr.UnsafePointer())
+}
+
+// freeCValue releases the C memory allocated for the fields of the given C
+// structure, this function walks each field of the structure recursively in
+// the same way as goValueToC, the array fields are recognized by the length
+// fields with the "Len" suffix.
+func freeCValue(cVal reflect.Value) {
+	lenIndexes := getCArrayLenIndexes(cVal.Type())
+	for i := 0; i < cVal.NumField(); i++ {
+		field := cVal.Type().Field(i)
+		switch field.Type.Kind() {
+		case reflect.Ptr:
+			cPtr := cVal.Field(i)
+			if cPtr.IsNil() {
+				continue
+			}
+			if lenIndexes[i] < 0 {
+				freeCPointer(cPtr)
+				continue
+			}
+			// The C array, for example: char ** or struct Options *
+			cArrayLen := cVal.Field(lenIndexes[i])
+			cArray := reflect.NewAt(reflect.ArrayOf(int(cArrayLen.Int()), field.Type.Elem()), cPtr.UnsafePointer()).Elem()
+			for j := 0; j < cArray.Len(); j++ {
+				switch ele := cArray.Index(j); ele.Kind() {
+				case reflect.Ptr:
+					freeCPointer(ele)
+				case reflect.Struct:
+					freeCValue(ele)
+				}
+			}
+			C.free(cPtr.UnsafePointer())
+		case reflect.Struct:
+			// The C struct, for example: struct Options
+			freeCValue(cVal.Field(i))
+		}
+	}
+}
+
+// freeResult releases the C memory referenced by the given pointer of the
+// result structure, and reset the result to the zero value to avoid a double
+// free.
+func freeResult(result interface{}) {
+	val := reflect.ValueOf(result)
+	if val.IsNil() {
+		return
+	}
+	freeCValue(val.Elem())
+	val.Elem().Set(reflect.Zero(val.Elem().Type()))
+}
+
+// jsonUnsupportedMethods defines the methods of the workbook which can not be
+// called by CallJSON, the workbook and the iterators returned by them are
+// managed by the handles.
+var jsonUnsupportedMethods = map[string]bool{
+	"Close":           true,
+	"Cols":            true,
+	"NewStreamWriter": true,
+	"Rows":            true,
+}
+
+// jsonReadOnlyMethods defines the methods of the workbook which only read the
+// workbook, CallJSON takes the read lock of the workbook handle for them, and
+// the write lock for the other methods.
+var jsonReadOnlyMethods = map[string]bool{
+	"GetActiveSheetIndex":   true,
+	"GetAppProps":           true,
+	"GetBaseColor":          true,
+	"GetCalcProps":          true,
+	"GetCellFormula":        true,
+	"GetCellHyperLink":      true,
+	"GetCellRichText":       true,
+	"GetCellStyle":          true,
+	"GetCellType":           true,
+	"GetCellValue":          true,
+	"GetColOutlineLevel":    true,
+	"GetColStyle":           true,
+	"GetColVisible":         true,
+	"GetColWidth":           true,
+	"GetCols":               true,
+	"GetComments":           true,
+	"GetConditionalFormats": true,
+	"GetConditionalStyle":   true,
+	"GetCustomProps":        true,
+	"GetDataValidations":    true,
+	"GetDefaultFont":        true,
+	"GetDefinedName":        true,
+	"GetDocProps":           true,
+	"GetFormControls":       true,
+	"GetHeaderFooter":       true,
+	"GetHyperLinkCells":     true,
+	"GetMergeCells":         true,
+	"GetPageLayout":         true,
+	"GetPageMargins":        true,
+	"GetPanes":              true,
+	"GetPictureCells":       true,
+	"GetPictures":           true,
+	"GetPivotTables":        true,
+	"GetRowHeight":          true,
+	"GetRowOutlineLevel":    true,
+	"GetRowVisible":         true,
+	"GetRows":               true,
+	"GetSheetDimension":     true,
+	"GetSheetIndex":         true,
+	"GetSheetList":          true,
+	"GetSheetMap":           true,
+	"GetSheetName":          true,
+	"GetSheetProps":         true,
+	"GetSheetProtection":    true,
+	"GetSheetView":          true,
+	"GetSheetVisible":       true,
+	"GetSlicers":            true,
+	"GetStyle":              true,
+	"GetTables":             true,
+	"GetWorkbookProps":      true,
+	"SearchSheet":           true,
+}
+
+// callJSON calls the method of the workbook by given method name with the
+// arguments encoded as a JSON array, and returns the results of the method
+// except the error encoded as JSON.
+func callJSON(f *excelize.File, name, args string) (string, error) {
+	fn := reflect.ValueOf(f).MethodByName(name)
+	if !fn.IsValid() || jsonUnsupportedMethods[name] {
+		return emptyString, fmt.Errorf("unsupported method %q", name)
+	}
+	return callJSONFunc(fn, name, args)
+}
+
+// callJSONFunc calls the function with the arguments encoded as a JSON array,
+// and returns the results of the function except the error encoded as JSON.
+func callJSONFunc(fn reflect.Value, name, args string) (string, error) {
+	var rawArgs []json.RawMessage
+	if err := json.Unmarshal([]byte(args), &rawArgs); err != nil {
+		return emptyString, err
+	}
+	fnType := fn.Type()
+	numIn, minIn := fnType.NumIn(), fnType.NumIn()
+	if fnType.IsVariadic() {
+		minIn--
+	}
+	if len(rawArgs) < minIn || (!fnType.IsVariadic() && len(rawArgs) > numIn) {
+		return emptyString, fmt.Errorf("method %s expects %d arguments, but got %d", name, minIn, len(rawArgs))
+	}
+	in := make([]reflect.Value, len(rawArgs))
+	for i, raw := range rawArgs {
+		argType := fnType.In(min(i, numIn-1))
+		if fnType.IsVariadic() && i >= numIn-1 {
+			argType = argType.Elem()
+		}
+		arg := reflect.New(argType)
+		if err := json.Unmarshal(raw, arg.Interface()); err != nil {
+			return emptyString, fmt.Errorf("invalid argument %d of method %s: %v", i+1, name, err)
+		}
+		in[i] = arg.Elem()
+	}
+	out := fn.Call(in)
+	if n := len(out); n > 0 && fnType.Out(n-1) == reflect.TypeOf((*error)(nil)).Elem() {
+		if err, _ := out[n-1].Interface().(error); err != nil {
+			return emptyString, err
+		}
+		out = out[:n-1]
+	}
+	var result interface{}
+	switch len(out) {
+	case 0:
+	case 1:
+		result = out[0].Interface()
+	default:
+		results := make([]interface{}, len(out))
+		for i, v := range out {
+			results[i] = v.Interface()
+		}
+		result = results
+	}
+	val, err := json.Marshal(result)
+	return string(val), err
+}
+
+// errorCodes defines the error codes of the sentinel errors of the excelize
+// library and this library.
+var errorCodes = map[error]C.int{
+	excelize.ErrAddVBAProject:               C.ErrCodeUnsupported,
+	excelize.ErrCellCharsLength:             C.ErrCodeExceedsLimit,
+	excelize.ErrCellStyles:                  C.ErrCodeExceedsLimit,
+	excelize.ErrColumnNumber:                C.ErrCodeCoordinates,
+	excelize.ErrColumnWidth:                 C.ErrCodeExceedsLimit,
+	excelize.ErrCoordinates:                 C.ErrCodeCoordinates,
+	excelize.ErrDataValidationFormulaLength: C.ErrCodeExceedsLimit,
+	excelize.ErrDataValidationRange:         C.ErrCodeExceedsLimit,
+	excelize.ErrDefinedNameScope:            C.ErrCodeNotExist,
+	excelize.ErrExistsSheet:                 C.ErrCodeSheetName,
+	excelize.ErrFontLength:                  C.ErrCodeExceedsLimit,
+	excelize.ErrImgExt:                      C.ErrCodeUnsupported,
+	excelize.ErrMaxFilePathLength:           C.ErrCodeExceedsLimit,
+	excelize.ErrMaxRowHeight:                C.ErrCodeExceedsLimit,
+	excelize.ErrMaxRows:                     C.ErrCodeCoordinates,
+	excelize.ErrNameLength:                  C.ErrCodeExceedsLimit,
+	excelize.ErrParameterInvalid:            C.ErrCodeParameterInvalid,
+	excelize.ErrParameterRequired:           C.ErrCodeParameterRequired,
+	excelize.ErrPasswordLengthInvalid:       C.ErrCodeProtection,
+	excelize.ErrSheetIdx:                    C.ErrCodeSheetName,
+	excelize.ErrSheetNameBlank:              C.ErrCodeSheetName,
+	excelize.ErrSheetNameInvalid:            C.ErrCodeSheetName,
+	excelize.ErrSheetNameLength:             C.ErrCodeSheetName,
+	excelize.ErrSheetNameSingleQuote:        C.ErrCodeSheetName,
+	excelize.ErrTotalSheetHyperlinks:        C.ErrCodeExceedsLimit,
+	excelize.ErrUnknownEncryptMechanism:     C.ErrCodeUnsupported,
+	excelize.ErrUnprotectSheet:              C.ErrCodeProtection,
+	excelize.ErrUnprotectSheetPassword:      C.ErrCodeProtection,
+	excelize.ErrUnprotectWorkbook:           C.ErrCodeProtection,
+	excelize.ErrUnprotectWorkbookPassword:   C.ErrCodeProtection,
+	excelize.ErrUnsupportedEncryptMechanism: C.ErrCodeUnsupported,
+	excelize.ErrUnsupportedHashAlgorithm:    C.ErrCodeUnsupported,
+	excelize.ErrUnsupportedNumberFormat:     C.ErrCodeUnsupported,
+	excelize.ErrWorkbookFileFormat:          C.ErrCodeUnsupported,
+	excelize.ErrWorkbookPassword:            C.ErrCodeWorkbookPassword,
+	errCanceled:                             C.ErrCodeCanceled,
+	errCalcAbandoned:                        C.ErrCodeCanceled,
+	errCalcCanceled:                         C.ErrCodeCanceled,
+	errCalcTimeout:                          C.ErrCodeTimeout,
+	errStreamAppendTable:                    C.ErrCodeUnsupported,
+}
+
+// errorCode returns the error code of the given error. The sentinel errors are
+// matched by errors.Is, the worksheet and handle errors are matched by
+// errors.As. The coordinates errors, the non-worksheet errors and the not exist
+// errors of the tables and slicers are built by the excelize library with
+// fmt.Errorf without a type, so they are matched by their formats.
+func errorCode(err error) C.int {
+	if err == nil {
+		return C.ErrCodeNone
+	}
+	for sentinel, code := range errorCodes {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+	var handleErr handleError
+	switch {
+	case errors.As(err, &handleErr) && handleErr.released:
+		return C.ErrCodeHandleReleased
+	case errors.As(err, &handleErr):
+		return C.ErrCodeHandleNotFound
+	case errors.Is(err, fs.ErrNotExist):
+		return C.ErrCodeFileNotExist
+	case errors.As(err, new(excelize.ErrSheetNotExist)):
+		return C.ErrCodeSheetNotExist
+	case errors.As(err, new(tableNotExistError)):
+		return C.ErrCodeNotExist
+	}
+	// The following errors of the excelize have no sentinel or type, so they
+	// were matched by the messages, TestErrorMessageCode keeps them in sync
+	// with the excelize.
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "sheet ") && strings.HasSuffix(msg, " is not a worksheet"):
+		return C.ErrCodeSheetNotExist
+	case (strings.HasPrefix(msg, "table ") || strings.HasPrefix(msg, "slicer ")) && strings.HasSuffix(msg, " does not exist"):
+		return C.ErrCodeNotExist
+	}
+	for _, prefix := range []string{"cannot convert cell ", "invalid cell reference ", "invalid cell name ", "invalid column name ", "invalid row number "} {
+		if strings.HasPrefix(msg, prefix) {
+			return C.ErrCodeCoordinates
+		}
+	}
+	return C.ErrCodeUnknown
+}
+
+// cStructField returns the settable field of the C structure by given field
+// names, the unexported fields such as "err" are also settable. It returns an
+// invalid value if none of the fields was found.
+func cStructField(val reflect.Value, names ...string) reflect.Value {
+	for _, name := range names {
+		if field := val.FieldByName(name); field.IsValid() {
+			return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+	}
+	return reflect.Value{}
+}
+
+// finalizeResult was deferred in the exported functions. It recovers the panic
+// to avoid the host process crash, and returns the panic message and stack
+// through the error field of the given result with the panic error code. The
+// exported functions set the error codes of the returned errors by themselves,
+// so the result is left untouched without a panic. The result could be the
+// pointer of the result structure or nil.
+func finalizeResult(result interface{}) {
+	r := recover()
+	if r == nil || result == nil {
+		return
+	}
+	val := reflect.ValueOf(result).Elem()
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	msg := C.CString(fmt.Sprintf("%v\n%s", r, debug.Stack()))
+	freeCValue(val)
+	val.Set(reflect.Zero(val.Type()))
+	cStructField(val, "err", "Err").Set(reflect.ValueOf(msg))
+	cStructField(val, "ErrCode").Set(reflect.ValueOf(C.int(C.ErrCodePanic)))
+}
+
+// cCellToGo converts the C cell of the stream writer to excelize.Cell, the value
+// of the cell will be the rich text runs if the RichText is not empty.
+func cCellToGo(val C.struct_Cell) (excelize.Cell, error) {
+	cell := excelize.Cell{StyleID: int(val.StyleID), Formula: C.GoString(val.Formula), Value: cInterfaceToGo(val.Value)}
+	if val.RichTextLen == 0 {
+		return cell, nil
+	}
+	runs := make([]excelize.RichTextRun, val.RichTextLen)
+	for i, run := range unsafe.Slice(val.RichText, val.RichTextLen) {
+		goVal, err := cValueToGo(reflect.ValueOf(run), reflect.TypeOf(excelize.RichTextRun{}))
+		if err != nil {
+			return cell, err
+		}
+		runs[i] = goVal.Elem().Interface().(excelize.RichTextRun)
+	}
+	cell.Value = runs
+	return cell, nil
+}
+
+// cInterfaceToGo convert C interface to Go interface data type value.
+func cInterfaceToGo(val C.struct_Interface) interface{} {
+	switch val.Type {
+	case Int:
+		return int(val.Integer)
+	case Int32:
+		return int32(val.Integer32)
+	case String, Error, Formula:
+		return C.GoString(val.String)
+	case Float:
+		return float64(val.Float64)
+	case Boolean:
+		return bool(val.Boolean)
+	case Time:
+		return cTimeToGo(val)
+	case Int64:
+		return int64(val.Integer)
+	case Uint64:
+		return uint64(val.UInteger64)
+	case Duration:
+		return time.Duration(val.Integer)
+	default:
+		return nil
+	}
+}
+
+// cTimeToGo convert C interface to Go time.Time data type value. The time
+// without location and offset is in UTC, the time in the "Local" location is
+// in the local time zone, and the time with an unknown location is in a fixed
+// zone with the given offset seconds east of UTC.
+func cTimeToGo(val C.struct_Interface) time.Time {
+	t := time.Unix(int64(val.Integer), int64(val.Nanosecond)).UTC()
+	name := C.GoString(val.Location)
+	if (name == "" && val.Offset == 0) || name == "UTC" {
+		return t
+	}
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return t.In(loc)
+	}
+	return t.In(time.FixedZone(name, int(val.Offset)))
+}
+
+// wallTimeToC convert the time without time zone in UTC, such as the date and
+// time values of the cells, to C interface data type value without location and
+// offset, so the wall clock of the time will be kept by the caller.
+func wallTimeToC(t time.Time) C.struct_Interface {
+	return C.struct_Interface{Type: Time, Integer: C.longlong(t.Unix()), Nanosecond: C.int(t.Nanosecond())}
+}
+
+// cellValueToC convert the raw value of a cell to C interface data type value
+// by given cell type. The value of the number cell which can not be parsed as
+// a float number and the value of the date cell which is not in ISO 8601 format
+// will be kept as string.
+func cellValueToC(cellType excelize.CellType, raw string) C.struct_Interface {
+	switch cellType {
+	case excelize.CellTypeBool:
+		return C.struct_Interface{Type: Boolean, Boolean: C._Bool(raw == "1" || strings.EqualFold(raw, "true"))}
+	case excelize.CellTypeDate:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+			t, err := time.Parse(layout, raw)
+			if err != nil {
+				continue
+			}
+			if t.Location() == time.UTC {
+				return wallTimeToC(t)
+			}
+			return goInterfaceToC(t)
+		}
+	case excelize.CellTypeError:
+		return C.struct_Interface{Type: Error, String: C.CString(raw)}
+	case excelize.CellTypeFormula:
+		return C.struct_Interface{Type: Formula, String: C.CString(raw)}
+	case excelize.CellTypeNumber, excelize.CellTypeUnset:
+		if raw == "" {
+			return C.struct_Interface{Type: Nil}
+		}
+		if val, err := strconv.ParseFloat(raw, 64); err == nil {
+			return C.struct_Interface{Type: Float, Float64: C.double(val)}
+		}
+	}
+	return C.struct_Interface{Type: String, String: C.CString(raw)}
+}
+
+// typedValueReader converts the raw value of the cells in a workbook to the
+// typed values. The number cells with a date or time number format are
+// converted to time, the date system of the workbook and the number format of
+// the styles are read once and cached.
+type typedValueReader struct {
+	file       *excelize.File
+	date1904   bool
+	dateStyles map[int]bool
+}
+
+// newTypedValueReader returns a typed value reader by given workbook.
+func newTypedValueReader(f *excelize.File) (*typedValueReader, error) {
+	props, err := f.GetWorkbookProps()
+	if err != nil {
+		return nil, err
+	}
+	return &typedValueReader{
+		file:       f,
+		date1904:   props.Date1904 != nil && *props.Date1904,
+		dateStyles: map[int]bool{},
+	}, nil
+}
+
+// value returns the typed value of a cell by given cell type, style ID and
+// raw value of the cell.
+func (r *typedValueReader) value(cellType excelize.CellType, styleID int, raw string) (C.struct_Interface, error) {
+	if cellType == excelize.CellTypeNumber || cellType == excelize.CellTypeUnset {
+		if val, err := strconv.ParseFloat(raw, 64); err == nil {
+			isDate, err := r.isDateStyle(styleID)
+			if err != nil {
+				return C.struct_Interface{Type: Nil}, err
+			}
+			if isDate {
+				if t, err := excelize.ExcelDateToTime(val, r.date1904); err == nil {
+					return wallTimeToC(t), nil
+				}
+			}
+		}
+	}
+	return cellValueToC(cellType, raw), nil
+}
+
+// isDateStyle returns if the number format of the given style is a date or
+// time number format.
+func (r *typedValueReader) isDateStyle(styleID int) (bool, error) {
+	if isDate, ok := r.dateStyles[styleID]; ok {
+		return isDate, nil
+	}
+	style, err := r.file.GetStyle(styleID)
+	if err != nil {
+		return false, err
+	}
+	isDate := isDateNumFmtID(style.NumFmt)
+	if style.CustomNumFmt != nil {
+		isDate = isDateNumFmtCode(*style.CustomNumFmt)
+	}
+	r.dateStyles[styleID] = isDate
+	return isDate, nil
+}
+
+// isDateNumFmtID returns if the given built-in number format ID, including the
+// language glyphs number formats, is a date or time number format.
+func isDateNumFmtID(ID int) bool {
+	return (14 <= ID && ID <= 22) || (27 <= ID && ID <= 36) || (45 <= ID && ID <= 47) ||
+		(50 <= ID && ID <= 58) || (71 <= ID && ID <= 81)
+}
+
+// isDateNumFmtCode returns if the given number format code is a date or time
+// number format. Only the first section of the code is checked, the literal
+// text, the escaped and padding characters, and the colors, conditions and
+// locales in brackets are skipped, the elapsed time such as "[h]" is a time.
+func isDateNumFmtCode(code string) bool {
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '"':
+			end := strings.IndexByte(code[i+1:], '"')
+			if end == -1 {
+				return false
+			}
+			i += end + 1
+		case '\\', '_', '*':
+			i++
+		case '[':
+			end := strings.IndexByte(code[i:], ']')
+			if end == -1 {
+				return false
+			}
+			if token := strings.ToLower(code[i+1 : i+end]); token != "" && strings.Trim(token, token[:1]) == "" &&
+				strings.Contains("hms", token[:1]) {
+				return true
+			}
+			i += end
+		case ';':
+			return false
+		case 'd', 'D', 'h', 'H', 'm', 'M', 's', 'S', 'y', 'Y':
+			return true
+		}
+	}
+	return false
+}
+
+// rangeRefToCoordinates convert the range reference such as "C10:H5000" or a
+// single cell reference to the coordinates of the top left and bottom right
+// cells.
+func rangeRefToCoordinates(ref string) ([]int, error) {
+	cells := strings.Split(ref, ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	if len(cells) != 2 {
+		return nil, excelize.ErrParameterInvalid
+	}
+	coordinates := make([]int, 4)
+	for i, cell := range cells {
+		col, row, err := excelize.CellNameToCoordinates(cell)
+		if err != nil {
+			return nil, err
+		}
+		coordinates[i*2], coordinates[i*2+1] = col, row
+	}
+	if coordinates[0] > coordinates[2] {
+		coordinates[0], coordinates[2] = coordinates[2], coordinates[0]
+	}
+	if coordinates[1] > coordinates[3] {
+		coordinates[1], coordinates[3] = coordinates[3], coordinates[1]
+	}
+	return coordinates, nil
+}
+
+// resolveRangeRef resolve the range reference or the defined name which refers
+// to a range, and returns the worksheet name and the coordinates of the range.
+// The defined name scoped to the given worksheet takes precedence over the
+// workbook scoped one. The whole column reference such as "A:C" and the whole
+// row reference such as "1:3" are clamped to the used range of the worksheet.
+func resolveRangeRef(f *excelize.File, sheet, ref string) (string, []int, error) {
+	var refersTo string
+	for _, dn := range f.GetDefinedName() {
+		if strings.EqualFold(dn.Name, ref) && (dn.Scope == sheet || (dn.Scope == "Workbook" && refersTo == "")) {
+			refersTo = dn.RefersTo
+		}
+	}
+	if refersTo != "" {
+		refersTo = strings.TrimPrefix(refersTo, "=")
+		i := strings.LastIndex(refersTo, "!")
+		if i == -1 || strings.Contains(refersTo, ",") {
+			return sheet, nil, excelize.ErrParameterInvalid
+		}
+		sheet = strings.ReplaceAll(strings.Trim(refersTo[:i], "'"), "''", "'")
+		ref = refersTo[i+1:]
+	}
+	ref = strings.ReplaceAll(ref, "$", "")
+	coordinates, err := rangeRefToCoordinates(ref)
+	if cells := strings.Split(ref, ":"); err != nil && isWholeRangeRef(cells) {
+		coordinates, err = wholeRangeRefToCoordinates(f, sheet, cells)
+	}
+	return sheet, coordinates, err
+}
+
+// isWholeRangeRef returns if the given cells of a range reference is a whole
+// column reference such as "A:C" or a whole row reference such as "1:3".
+func isWholeRangeRef(cells []string) bool {
+	if len(cells) != 2 || cells[0] == "" || cells[1] == "" {
+		return false
+	}
+	return strings.Trim(strings.ToUpper(cells[0]+cells[1]), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == "" ||
+		strings.Trim(cells[0]+cells[1], "0123456789") == ""
+}
+
+// wholeRangeRefToCoordinates convert the whole column or row reference to the
+// coordinates of the range, which are clamped to the used range of the
+// worksheet.
+func wholeRangeRefToCoordinates(f *excelize.File, sheet string, cells []string) ([]int, error) {
+	var (
+		nums  = make([]int, 2)
+		isCol = strings.Trim(cells[0], "0123456789") != ""
+		err   error
+	)
+	for i, cell := range cells {
+		if isCol {
+			nums[i], err = excelize.ColumnNameToNumber(cell)
+		} else if nums[i], err = strconv.Atoi(cell); err == nil {
+			_, err = excelize.CoordinatesToCellName(1, nums[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	cols, rows, err := getUsedRangeSize(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	if isCol {
+		return []int{min(nums[0], nums[1]), 1, max(nums[0], nums[1]), rows}, nil
+	}
+	return []int{1, min(nums[0], nums[1]), cols, max(nums[0], nums[1])}, nil
+}
+
+// getUsedRangeSize returns the number of the columns and rows in the used range
+// of the worksheet, which is the range of the cells with a value or formula
+// read by the rows iterator.
+func getUsedRangeSize(f *excelize.File, sheet string) (int, int, error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return 0, 0, err
+	}
+	var cols, lastRow int
+	for row := 1; rows.Next(); row++ {
+		cells, err := rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			_ = rows.Close()
+			return cols, lastRow, err
+		}
+		if len(cells) > 0 {
+			cols, lastRow = max(cols, len(cells)), row
+		}
+	}
+	return cols, lastRow, rows.Close()
+}
+
+// getRangeValues provides a function to get the values of the cells in the
+// range by given worksheet name and the coordinates of the range. The rows of
+// the range are read once by the rows iterator and clipped to the range, and
+// the values of the merged cells are resolved as the GetCellValue function
+// does.
+func getRangeValues(f *excelize.File, sheet string, coordinates []int, opts excelize.Options) ([][]string, error) {
+	sheetRows, err := readSheetRows(f, sheet, coordinates[1], coordinates[3], opts)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, coordinates[3]-coordinates[1]+1)
+	for r := range rows {
+		rows[r] = make([]string, coordinates[2]-coordinates[0]+1)
+		if r < len(sheetRows) && coordinates[0] <= len(sheetRows[r].cells) {
+			copy(rows[r], sheetRows[r].cells[coordinates[0]-1:])
+		}
+	}
+	mergeCells, err := f.GetMergeCells(sheet, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, mergeCell := range mergeCells {
+		cells, err := rangeRefToCoordinates(mergeCell.GetStartAxis() + ":" + mergeCell.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		if cells[0] > coordinates[2] || cells[2] < coordinates[0] || cells[1] > coordinates[3] || cells[3] < coordinates[1] {
+			continue
+		}
+		val, err := f.GetCellValue(sheet, mergeCell.GetStartAxis(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for r := max(cells[1], coordinates[1]); r <= min(cells[3], coordinates[3]); r++ {
+			for c := max(cells[0], coordinates[0]); c <= min(cells[2], coordinates[2]); c++ {
+				rows[r-coordinates[1]][c-coordinates[0]] = val
+			}
+		}
+	}
+	return rows, nil
+}
+
+// sheetRow is a row of the worksheet read by the rows iterator, which includes
+// the values of the cells up to the last cell with a value or formula, and the
+// style ID of the row.
+type sheetRow struct {
+	cells []string
+	style int
+}
+
+// readSheetRows reads the rows of the worksheet by the rows iterator from the
+// given first row to the last row, or to the end of the worksheet if the last
+// row is 0. The worksheet is loaded by the GetCellType function before
+// iterating, so the rows are read from the loaded worksheet with all the rows
+// and the cells before the last cell of each row.
+func readSheetRows(f *excelize.File, sheet string, firstRow, lastRow int, opts excelize.Options) ([]sheetRow, error) {
+	if _, err := f.GetCellType(sheet, "A1"); err != nil {
+		return nil, err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var result []sheetRow
+	for row := 1; (lastRow == 0 || row <= lastRow) && rows.Next(); row++ {
+		if row < firstRow {
+			continue
+		}
+		cells, err := rows.Columns(opts)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		result = append(result, sheetRow{cells: cells, style: rows.GetRowOpts().StyleID})
+	}
+	return result, rows.Close()
+}
+
+// sheetStyles reads the style ID of the cells in the rows of the worksheet. The
+// style of the cell before the last cell with a value or formula in the row is
+// read by the GetCellStyle function, these cells exist in the loaded worksheet,
+// so no empty rows and cells will be added to the worksheet. The style of the
+// other cells is the style of the row or the column, as the GetCellStyle
+// function does for the cell without style.
+type sheetStyles struct {
+	file  *excelize.File
+	sheet string
+	row   int
+	rows  []sheetRow
+	cols  map[int]int
+}
+
+// newSheetStyles returns the style reader of the cells from the given first row
+// to the last row in the worksheet.
+func newSheetStyles(f *excelize.File, sheet string, firstRow, lastRow int) (*sheetStyles, error) {
+	rows, err := readSheetRows(f, sheet, firstRow, lastRow, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+	return &sheetStyles{file: f, sheet: sheet, row: firstRow, rows: rows, cols: map[int]int{}}, nil
+}
+
+// style returns the style ID of the cell by given column and row number.
+func (s *sheetStyles) style(col, row int) (int, error) {
+	if i := row - s.row; 0 <= i && i < len(s.rows) {
+		if col <= len(s.rows[i].cells) {
+			cell, err := excelize.CoordinatesToCellName(col, row)
+			if err != nil {
+				return 0, err
+			}
+			return s.file.GetCellStyle(s.sheet, cell)
+		}
+		if s.rows[i].style != 0 {
+			return s.rows[i].style, nil
+		}
+	}
+	if styleID, ok := s.cols[col]; ok {
+		return styleID, nil
+	}
+	name, err := excelize.ColumnNumberToName(col)
+	if err != nil {
+		return 0, err
+	}
+	styleID, err := s.file.GetColStyle(s.sheet, name)
+	s.cols[col] = styleID
+	return styleID, err
+}
+
+// relationship directly maps the relationship element in the relationships
+// part of the package.
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Target string `xml:"Target,attr"`
+	Type   string `xml:"Type,attr"`
+}
+
+// getRelationships returns the relationships of the given part in the package,
+// the relationships loaded by the workbook take precedence over the ones
+// stored in the package.
+func getRelationships(f *excelize.File, part string) ([]relationship, error) {
+	relsPath := "_rels/.rels"
+	if part != "" {
+		relsPath = path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+	}
+	content, ok := f.Pkg.Load(relsPath)
+	if rels, loaded := f.Relationships.Load(relsPath); loaded {
+		output, err := xml.Marshal(rels)
+		if err != nil {
+			return nil, err
+		}
+		content, ok = output, len(output) > 0
+	}
+	var rels struct {
+		Relationships []relationship `xml:"Relationship"`
+	}
+	if !ok {
+		return rels.Relationships, nil
+	}
+	err := xml.Unmarshal(content.([]byte), &rels)
+	return rels.Relationships, err
+}
+
+// getPartPath returns the path of the part in the package by given the path of
+// the source part and the target of the relationship.
+func getPartPath(source, target string) string {
+	target = strings.ReplaceAll(target, "\\", "/")
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return path.Join(path.Dir(source), target)
+}
+
+// getSheetXMLPath returns the path of the worksheet XML part by given
+// worksheet name.
+func getSheetXMLPath(f *excelize.File, sheet string) (string, error) {
+	rels, err := getRelationships(f, emptyString)
+	if err != nil {
+		return emptyString, err
+	}
+	var wbPath string
+	for _, rel := range rels {
+		if strings.HasSuffix(rel.Type, "/officeDocument") {
+			wbPath = getPartPath(emptyString, rel.Target)
+		}
+	}
+	if rels, err = getRelationships(f, wbPath); err != nil || f.WorkBook == nil {
+		return emptyString, err
+	}
+	for _, ws := range f.WorkBook.Sheets.Sheet {
+		if !strings.EqualFold(ws.Name, sheet) {
+			continue
+		}
+		for _, rel := range rels {
+			if rel.ID == ws.ID {
+				return getPartPath(wbPath, rel.Target), nil
+			}
+		}
+	}
+	return emptyString, excelize.ErrSheetNotExist{SheetName: sheet}
+}
+
+// sheetStyleReader reads the style ID of the cells from the row and cell data
+// in the worksheet XML part without preparing the worksheet, so no empty rows
+// and cells will be added to the worksheet. The cells should be read in row
+// order.
+type sheetStyleReader struct {
+	decoder  *xml.Decoder
+	cols     [][3]int
+	row      int
+	rowStyle int
+	cells    map[int]int
+	done     bool
+}
+
+// readSheetXML returns the worksheet XML part by given worksheet name. The
+// worksheet is loaded by the GetCellType function and flushed into the package
+// by the Rows function, so the worksheet XML part has the latest data.
+func readSheetXML(f *excelize.File, sheet string) ([]byte, error) {
+	if _, err := f.GetCellType(sheet, "A1"); err != nil {
+		return nil, err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	name, err := getSheetXMLPath(f, sheet)
+	if err != nil {
+		return nil, err
 	}
-)
-
-// cToGoBaseType convert JavaScript value to Go basic data type variable.
-func cToGoBaseType(cVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-	fn, ok := cToBaseGoTypeFuncs[kind]
+	content, ok := f.Pkg.Load(name)
 	if !ok {
-		return reflect.ValueOf(nil), errArgType
+		return nil, excelize.ErrSheetNotExist{SheetName: sheet}
 	}
-	return fn(cVal, kind)
+	return content.([]byte), nil
 }
 
-// cToGoArray convert C language array to Go array base on the given Go	structure
-// types.
-func cToGoArray(cArray reflect.Value, cArrayLen int) reflect.Value {
-	switch cArray.Elem().Type().String() {
-	case "*main._Ctype_char":
-		return reflect.ValueOf(append([]*C.char{}, unsafe.Slice(cArray.Interface().(**C.char), cArrayLen)...))
-	case "*main._Ctype_struct_Options": // []*excelize.Options
-		val := cArray.Interface().(**C.struct_Options)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	case "main._Ctype_struct_Border":
-		val := cArray.Interface().(*C.struct_Border)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	case "main._Ctype_struct_ChartSeries":
-		val := cArray.Interface().(*C.struct_ChartSeries)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	case "main._Ctype_struct_ConditionalFormatOptions":
-		val := cArray.Interface().(*C.struct_ConditionalFormatOptions)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	case "main._Ctype_struct_PivotTableField":
-		val := cArray.Interface().(*C.struct_PivotTableField)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	case "main._Ctype_struct_RichTextRun":
-		val := cArray.Interface().(*C.struct_RichTextRun)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	case "main._Ctype_struct_Selection":
-		val := cArray.Interface().(*C.struct_Selection)
-		arr := unsafe.Slice(val, cArrayLen)
-		return reflect.ValueOf(arr)
-	}
-	return cArray
+// newSheetStyleReader returns a style reader by given worksheet name.
+func newSheetStyleReader(f *excelize.File, sheet string) (*sheetStyleReader, error) {
+	content, err := readSheetXML(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &sheetStyleReader{decoder: xml.NewDecoder(bytes.NewReader(content)), cells: map[int]int{}}, nil
 }
 
-// cValueToGo convert C language object to Go variable base on the given Go
-// structure types, this function extract each fields of the structure from
-// object recursively.
-func cValueToGo(cVal reflect.Value, goType reflect.Type) (reflect.Value, error) {
-	result := reflect.New(goType)
-	s := result.Elem()
-	for resultFieldIdx := 0; resultFieldIdx < s.NumField(); resultFieldIdx++ {
-		field := goType.Field(resultFieldIdx)
-		if unicode.IsLower(rune(field.Name[0])) {
-			continue
+// style returns the style ID of the cell by given column and row number. The
+// style of the row and the column will be used for the cell without style, as
+// the GetCellStyle function does.
+func (r *sheetStyleReader) style(col, row int) (int, error) {
+	for !r.done && r.row < row {
+		if err := r.next(); err != nil {
+			return 0, err
 		}
-		if goBaseTypes[field.Type.Kind()] {
-			cBaseVal := cVal.FieldByName(field.Name)
-			goBaseVal, err := cToGoBaseType(cBaseVal, field.Type.Kind())
-			if err != nil {
-				return result, err
-			}
-			s.Field(resultFieldIdx).Set(goBaseVal.Convert(s.Field(resultFieldIdx).Type()))
-			continue
+	}
+	if r.row == row {
+		if styleID := r.cells[col]; styleID != 0 {
+			return styleID, nil
 		}
-		switch field.Type.Kind() {
-		case reflect.Ptr:
-			// Pointer of the Go data type, for example: *excelize.Options or *string
-			ptrType := field.Type.Elem()
-			if !goBaseTypes[ptrType.Kind()] {
-				// Pointer of the Go struct, for example: *excelize.Options
-				cObjVal := cVal.FieldByName(field.Name)
-				if cObjVal.Kind() == reflect.Ptr && !cObjVal.IsNil() {
-					v, err := cValueToGo(cObjVal.Elem(), ptrType)
-					if err != nil {
-						return result, err
-					}
-					s.Field(resultFieldIdx).Set(v)
-				}
-			}
-			if goBaseTypes[ptrType.Kind()] {
-				// Pointer of the Go basic data type, for example: *string
-				cBaseVal := cVal.FieldByName(field.Name)
-				if !cBaseVal.IsNil() {
-					v, err := cToGoBaseType(cBaseVal.Elem(), ptrType.Kind())
-					if err != nil {
-						return result, err
-					}
-					x := reflect.New(ptrType)
-					x.Elem().Set(v)
-					s.Field(resultFieldIdx).Set(x.Elem().Addr())
-				}
-			}
-		case reflect.Struct:
-			// The Go struct, for example: excelize.Options, convert sub fields recursively
-			structType := field.Type
-			cObjVal := cVal.FieldByName(field.Name)
-			v, err := cValueToGo(cObjVal, structType)
-			if err != nil {
-				return result, err
-			}
-			s.Field(resultFieldIdx).Set(v.Elem())
-		case reflect.Slice:
-			// The Go data type array, for example:
-			// []*excelize.Options, []excelize.Options, []string, []*string
-			ele := field.Type.Elem()
-			cArray := cVal.FieldByName(field.Name)
-			if cArray.IsZero() {
-				continue
-			}
-			if ele.Kind() == reflect.Ptr {
-				// Pointer array of the Go data type, for example: []*excelize.Options or []*string
-				subEle := ele.Elem()
-				cArrayLen := int(cVal.FieldByName(field.Name + "Len").Int())
-				cArray = cToGoArray(cArray, cArrayLen)
-				for i := 0; i < cArray.Len(); i++ {
-					if goBaseTypes[subEle.Kind()] {
-						// Pointer array of the Go basic data type, for example: []*string
-						v, err := cToGoBaseType(cArray.Index(i), subEle.Kind())
-						if err != nil {
-							return result, err
-						}
-						x := reflect.New(subEle)
-						x.Elem().Set(v)
-						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), x.Elem().Addr()))
-					} else {
-						// Pointer array of the Go struct, for example: []*excelize.Options
-						v, err := cValueToGo(cArray.Index(i).Elem(), subEle)
-						if err != nil {
-							return result, err
-						}
-						x := reflect.New(subEle)
-						x.Elem().Set(v.Elem())
-						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), x.Elem().Addr()))
-					}
-				}
-			} else {
-				// The Go data type array, for example: []excelize.Options or []string
-				subEle := ele
-				cArrayLen := int(cVal.FieldByName(field.Name + "Len").Int())
-				if subEle.Kind() == reflect.Uint8 { // []byte
-					buf := C.GoBytes(unsafe.Pointer(cArray.Interface().(*C.uchar)), C.int(cArrayLen))
-					s.Field(resultFieldIdx).Set(reflect.ValueOf(buf))
-					continue
-				}
-				cArray = cToGoArray(cArray, cArrayLen)
-				for i := 0; i < cArray.Len(); i++ {
-					if goBaseTypes[subEle.Kind()] {
-						// The Go basic data type array, for example: []string
-						v, err := cToGoBaseType(cArray.Index(i), subEle.Kind())
-						if err != nil {
-							return result, err
-						}
-
-						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), v))
-					} else {
-						// The Go struct array, for example: []excelize.Options
-						v, err := cValueToGo(cArray.Index(i), subEle)
-						if err != nil {
-							return result, err
-						}
-						s.Field(resultFieldIdx).Set(reflect.Append(s.Field(resultFieldIdx), v.Elem()))
-					}
-				}
-			}
+		if r.rowStyle != 0 {
+			return r.rowStyle, nil
 		}
 	}
-	return result, nil
-}
-
-// goBaseTypeToC convert Go basic data type value to C variable.
-func goBaseTypeToC(goVal reflect.Value, kind reflect.Kind) (reflect.Value, error) {
-	fn, ok := goBaseValueToCFuncs[kind]
-	if !ok {
-		return reflect.ValueOf(nil), errors.New("invalid argument data type" + kind.String())
+	for _, c := range r.cols {
+		if c[0] <= col && col <= c[1] && c[2] != 0 {
+			return c[2], nil
+		}
 	}
-	return fn(goVal, kind)
+	return 0, nil
 }
 
-// goValueToC convert Go variable to C object base on the given Go structure
-// types, this function extract each fields of the structure from structure
-// variable recursively.
-func goValueToC(goVal, cVal reflect.Value) (reflect.Value, error) {
-	result := cVal
-	c := result.Elem()
-	for i := 0; i < goVal.Type().NumField(); i++ {
-		cField, _ := c.Type().FieldByName(goVal.Type().Field(i).Name)
-		field := goVal.Type().Field(i)
-		if unicode.IsLower(rune(field.Name[0])) {
-			continue
-		}
-		if goBaseTypes[field.Type.Kind()] {
-			goBaseVal := goVal.FieldByName(field.Name)
-			cBaseVal, err := goBaseTypeToC(goBaseVal, goBaseVal.Type().Kind())
-			if err != nil {
-				return result, err
+// next decodes the next row in the worksheet, the columns before the sheet data
+// will be decoded on the way.
+func (r *sheetStyleReader) next() error {
+	var col int
+	clear(r.cells)
+	attr := func(el xml.StartElement, name string) int {
+		for _, a := range el.Attr {
+			if a.Name.Local == name {
+				val, _ := strconv.Atoi(a.Value)
+				return val
 			}
-			c.FieldByName(field.Name).Set(cBaseVal.Convert(cField.Type))
-			continue
 		}
-		switch goVal.Type().Field(i).Type.Kind() {
-		case reflect.Ptr:
-			// Pointer of the Go data type, for example: *excelize.Options or *string
-			ptrType := field.Type.Elem()
-			if !goBaseTypes[ptrType.Kind()] {
-				// Pointer of the Go struct, for example: *excelize.Options
-				goStructVal := goVal.Field(i)
-				if !goStructVal.IsNil() {
-					cPtr := C.malloc(C.size_t(cField.Type.Elem().Size()))
-					cStructPtr := reflect.NewAt(cField.Type.Elem(), cPtr)
-					v, err := goValueToC(goStructVal.Elem(), cStructPtr)
-					if err != nil {
-						return result, err
-					}
-					c.FieldByName(field.Name).Set(v)
+		return 0
+	}
+	for {
+		token, err := r.decoder.RawToken()
+		if err == io.EOF {
+			r.done = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "col":
+				r.cols = append(r.cols, [3]int{attr(el, "min"), attr(el, "max"), attr(el, "style")})
+			case "row":
+				r.row++
+				if row := attr(el, "r"); row != 0 {
+					r.row = row
 				}
-			}
-			if goBaseTypes[ptrType.Kind()] {
-				// Pointer of the Go basic data type, for example: *string
-				goBaseVal := goVal.Field(i)
-				if !goBaseVal.IsNil() {
-					v, err := goBaseTypeToC(goBaseVal.Elem(), ptrType.Kind())
-					if err != nil {
-						return result, err
+				r.rowStyle, col = attr(el, "s"), 0
+			case "c":
+				col++
+				for _, a := range el.Attr {
+					if a.Name.Local == "r" {
+						if c, _, err := excelize.CellNameToCoordinates(a.Value); err == nil {
+							col = c
+						}
 					}
-					cValPtr := C.malloc(C.size_t(unsafe.Sizeof(cField.Type.Elem().Size())))
-					ptrVal := reflect.NewAt(v.Type(), cValPtr).Elem()
-					ptrVal.Set(v)
-					c.FieldByName(field.Name).Set(ptrVal.Addr())
-				} else {
-					c.FieldByName(field.Name).Set(reflect.Zero(cField.Type))
 				}
+				r.cells[col] = attr(el, "s")
 			}
-		case reflect.Struct:
-			// The Go struct, for example: excelize.Options, convert sub fields recursively
-			goStructVal := goVal.Field(i)
-			v, err := goValueToC(goStructVal, reflect.New(cField.Type))
-			if err != nil {
-				return result, err
-			}
-			c.FieldByName(field.Name).Set(v.Elem())
-		case reflect.Slice:
-			// The Go data type array, for example:
-			// []*excelize.Options, []excelize.Options, []string, []*string
-			goSlice := goVal.Field(i)
-			ele := goSlice.Type().Elem()
-			l, err := goBaseTypeToC(reflect.ValueOf(goSlice.Len()), reflect.Int)
-			if err != nil {
-				return result, err
-			}
-			c.FieldByName(field.Name + "Len").Set(l)
-			if ele.Kind() == reflect.Uint8 { // []byte
-				byteSlice := goSlice.Bytes()
-				cArray := C.malloc(C.size_t(len(byteSlice)))
-				cSlice := unsafe.Slice((*byte)(cArray), len(byteSlice))
-				copy(cSlice, byteSlice)
-				c.FieldByName(field.Name).Set(reflect.ValueOf((*C.uchar)(cArray)))
-				continue
-			}
-			cArray := C.malloc(C.size_t(goSlice.Len()) * C.size_t(cField.Type.Elem().Size()))
-			for j := 0; j < goSlice.Len(); j++ {
-				if goBaseTypes[ele.Kind()] {
-					// The Go basic data type array, for example: []string
-					cBaseVal, err := goBaseTypeToC(goSlice.Index(j), goSlice.Index(j).Type().Kind())
-					if err != nil {
-						return result, err
-					}
-					elePtr := unsafe.Pointer(uintptr(cArray) + uintptr(j)*cBaseVal.Type().Size())
-					ele := reflect.NewAt(cBaseVal.Type(), elePtr).Elem()
-					ele.Set(cBaseVal)
-				} else {
-					// The Go struct array, for example: []excelize.Options
-					cPtr := C.malloc(C.size_t(cField.Type.Elem().Size()))
-					cStructPtr := reflect.NewAt(cField.Type.Elem(), cPtr)
-					v, err := goValueToC(goSlice.Index(j), cStructPtr)
-					if err != nil {
-						return result, err
-					}
-					elePtr := unsafe.Pointer(uintptr(cArray) + uintptr(j)*cField.Type.Elem().Size())
-					ele := reflect.NewAt(cField.Type.Elem(), elePtr).Elem()
-					ele.Set(reflect.NewAt(cField.Type.Elem(), unsafe.Pointer(v.Pointer())).Elem())
-				}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "row":
+				return nil
+			case "sheetData":
+				r.done = true
+				return nil
 			}
-			c.FieldByName(field.Name).Set(reflect.NewAt(cField.Type.Elem(), cArray))
 		}
 	}
-	return result, nil
-}
-
-// cInterfaceToGo convert C interface to Go interface data type value.
-func cInterfaceToGo(val C.struct_Interface) interface{} {
-	switch val.Type {
-	case Int:
-		return int(val.Integer)
-	case Int32:
-		return int32(val.Integer32)
-	case String:
-		return C.GoString(val.String)
-	case Float:
-		return float64(val.Float64)
-	case Boolean:
-		return bool(val.Boolean)
-	case Time:
-		return time.Unix(int64(val.Integer), 0)
-	default:
-		return nil
-	}
 }
 
 // goInterfaceToC convert Go interface to C interface data type value.
 func goInterfaceToC(val interface{}) C.struct_Interface {
 	switch v := val.(type) {
 	case int:
-		return C.struct_Interface{Type: Int, Integer: C.int(v)}
+		return C.struct_Interface{Type: Int, Integer: C.longlong(v)}
 	case int32:
 		return C.struct_Interface{Type: Int32, Integer32: C.int(v)}
+	case int64:
+		return C.struct_Interface{Type: Int64, Integer: C.longlong(v)}
+	case uint:
+		return C.struct_Interface{Type: Uint64, UInteger64: C.ulonglong(v)}
+	case uint64:
+		return C.struct_Interface{Type: Uint64, UInteger64: C.ulonglong(v)}
 	case string:
 		return C.struct_Interface{Type: String, String: C.CString(v)}
 	case float64:
 		return C.struct_Interface{Type: Float, Float64: C.double(v)}
 	case bool:
 		return C.struct_Interface{Type: Boolean, Boolean: C._Bool(v)}
+	case time.Duration:
+		return C.struct_Interface{Type: Duration, Integer: C.longlong(v)}
 	case time.Time:
-		return C.struct_Interface{Type: Time, Integer: C.int(v.Unix())}
+		_, offset := v.Zone()
+		// The local time zone is named by the TZ environment variable if set
+		location := v.Location().String()
+		if v.Location() == time.Local {
+			location = "Local"
+		}
+		return C.struct_Interface{
+			Type:       Time,
+			Integer:    C.longlong(v.Unix()),
+			Nanosecond: C.int(v.Nanosecond()),
+			Offset:     C.int(offset),
+			Location:   C.CString(location),
+		}
 	default:
 		return C.struct_Interface{Type: Nil}
 	}
@@ -482,30 +2840,32 @@ func goInterfaceToC(val interface{}) C.struct_Interface {
 // properties set.
 //
 //export AddChart
-func AddChart(idx int, sheet, cell *C.char, chart *C.struct_Chart, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddChart(idx int, sheet, cell *C.char, chart *C.struct_Chart, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	charts := make([]*excelize.Chart, length)
 	for i, c := range unsafe.Slice(chart, length) {
 		goVal, err := cValueToGo(reflect.ValueOf(c), reflect.TypeOf(excelize.Chart{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		c := goVal.Elem().Interface().(excelize.Chart)
 		charts[i] = &c
 	}
 	if len(charts) > 1 {
 		if err := f.(*excelize.File).AddChart(C.GoString(sheet), C.GoString(cell), charts[0], charts[1:]...); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).AddChart(C.GoString(sheet), C.GoString(cell), charts[0]); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddChartSheet provides the method to create a chartsheet by given chart
@@ -514,30 +2874,32 @@ func AddChart(idx int, sheet, cell *C.char, chart *C.struct_Chart, length int) *
 // a chart.
 //
 //export AddChartSheet
-func AddChartSheet(idx int, sheet *C.char, chart *C.struct_Chart, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddChartSheet(idx int, sheet *C.char, chart *C.struct_Chart, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	charts := make([]*excelize.Chart, length)
 	for i, c := range unsafe.Slice(chart, length) {
 		goVal, err := cValueToGo(reflect.ValueOf(c), reflect.TypeOf(excelize.Chart{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		c := goVal.Elem().Interface().(excelize.Chart)
 		charts[i] = &c
 	}
 	if len(charts) > 1 {
 		if err := f.(*excelize.File).AddChartSheet(C.GoString(sheet), charts[0], charts[1:]...); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).AddChartSheet(C.GoString(sheet), charts[0]); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddComment provides the method to add comments in a sheet by giving the
@@ -546,21 +2908,23 @@ func AddChartSheet(idx int, sheet *C.char, chart *C.struct_Chart, length int) *C
 // 32512.
 //
 //export AddComment
-func AddComment(idx int, sheet *C.char, opts *C.struct_Comment) *C.char {
+func AddComment(idx int, sheet *C.char, opts *C.struct_Comment) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var comment excelize.Comment
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Comment{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	comment = goVal.Elem().Interface().(excelize.Comment)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddComment(C.GoString(sheet), comment); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddDataValidation provides set data validation on a range of the worksheet
@@ -569,21 +2933,23 @@ func AddComment(idx int, sheet *C.char, opts *C.struct_Comment) *C.char {
 // NewDataValidation function.
 //
 //export AddDataValidation
-func AddDataValidation(idx int, sheet *C.char, dv *C.struct_DataValidation) *C.char {
+func AddDataValidation(idx int, sheet *C.char, dv *C.struct_DataValidation) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var dataValidation excelize.DataValidation
 	goVal, err := cValueToGo(reflect.ValueOf(*dv), reflect.TypeOf(excelize.DataValidation{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	dataValidation = goVal.Elem().Interface().(excelize.DataValidation)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddDataValidation(C.GoString(sheet), &dataValidation); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddFormControl provides the method to add form control object in a worksheet
@@ -593,21 +2959,23 @@ func AddDataValidation(idx int, sheet *C.char, dv *C.struct_DataValidation) *C.c
 // XLSM or XLTM. Scroll value must be between 0 and 30000.
 //
 //export AddFormControl
-func AddFormControl(idx int, sheet *C.char, opts *C.struct_FormControl) *C.char {
+func AddFormControl(idx int, sheet *C.char, opts *C.struct_FormControl) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.FormControl
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.FormControl{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.FormControl)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddFormControl(C.GoString(sheet), options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddHeaderFooterImage provides a mechanism to set the graphics that can be
@@ -618,35 +2986,39 @@ func AddFormControl(idx int, sheet *C.char, opts *C.struct_FormControl) *C.char
 // The width and height should have units in them, e.g. "100pt".
 //
 //export AddHeaderFooterImage
-func AddHeaderFooterImage(idx int, sheet *C.char, opts *C.struct_HeaderFooterImageOptions) *C.char {
+func AddHeaderFooterImage(idx int, sheet *C.char, opts *C.struct_HeaderFooterImageOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.HeaderFooterImageOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.HeaderFooterImageOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.HeaderFooterImageOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddHeaderFooterImage(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddIgnoredErrors provides the method to ignored error for a range of cells.
 //
 //export AddIgnoredErrors
-func AddIgnoredErrors(idx int, sheet, rangeRef *C.char, ignoredErrorsType C.int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddIgnoredErrors(idx int, sheet, rangeRef *C.char, ignoredErrorsType C.int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddIgnoredErrors(C.GoString(sheet), C.GoString(rangeRef), excelize.IgnoredErrorsType(ignoredErrorsType)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddPicture add picture in a sheet by given picture format set (such as
@@ -655,26 +3027,28 @@ func AddIgnoredErrors(idx int, sheet, rangeRef *C.char, ignoredErrorsType C.int)
 // TIFF, WMF and WMZ.
 //
 //export AddPicture
-func AddPicture(idx int, sheet, cell, name *C.char, opts *C.struct_GraphicOptions) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddPicture(idx int, sheet, cell, name *C.char, opts *C.struct_GraphicOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.GraphicOptions{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options := goVal.Elem().Interface().(excelize.GraphicOptions)
 		if err := f.(*excelize.File).AddPicture(C.GoString(sheet), C.GoString(cell), C.GoString(name), &options); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).AddPicture(C.GoString(sheet), C.GoString(cell), C.GoString(name), nil); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddPictureFromBytes provides the method to add picture in a sheet by given
@@ -686,20 +3060,22 @@ func AddPicture(idx int, sheet, cell, name *C.char, opts *C.struct_GraphicOption
 // WPS Office embedded image cells
 //
 //export AddPictureFromBytes
-func AddPictureFromBytes(idx int, sheet, cell *C.char, pic *C.struct_Picture) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddPictureFromBytes(idx int, sheet, cell *C.char, pic *C.struct_Picture) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*pic), reflect.TypeOf(excelize.Picture{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := goVal.Elem().Interface().(excelize.Picture)
 	if err := f.(*excelize.File).AddPictureFromBytes(C.GoString(sheet), C.GoString(cell), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddPivotTable provides the method to add pivot table by given pivot table
@@ -707,20 +3083,22 @@ func AddPictureFromBytes(idx int, sheet, cell *C.char, pic *C.struct_Picture) *C
 // fields at the same time.
 //
 //export AddPivotTable
-func AddPivotTable(idx int, opts *C.struct_PivotTableOptions) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddPivotTable(idx int, opts *C.struct_PivotTableOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.PivotTableOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := goVal.Elem().Interface().(excelize.PivotTableOptions)
 	if err := f.(*excelize.File).AddPivotTable(&options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddShape provides the method to add shape in a sheet by given worksheet
@@ -728,42 +3106,46 @@ func AddPivotTable(idx int, opts *C.struct_PivotTableOptions) *C.char {
 // print settings).
 //
 //export AddShape
-func AddShape(idx int, sheet *C.char, opts *C.struct_Shape) *C.char {
+func AddShape(idx int, sheet *C.char, opts *C.struct_Shape) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.Shape
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Shape{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.Shape)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddShape(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddSlicer function inserts a slicer by giving the worksheet name and slicer
 // settings.
 //
 //export AddSlicer
-func AddSlicer(idx int, sheet *C.char, opts *C.struct_SlicerOptions) *C.char {
+func AddSlicer(idx int, sheet *C.char, opts *C.struct_SlicerOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.SlicerOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.SlicerOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.SlicerOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddSlicer(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddSparkline provides a function to add sparklines to the worksheet by
@@ -773,58 +3155,64 @@ func AddSlicer(idx int, sheet *C.char, opts *C.struct_SlicerOptions) *C.char {
 // 2007, but they won't be displayed.
 //
 //export AddSparkline
-func AddSparkline(idx int, sheet *C.char, opts *C.struct_SparklineOptions) *C.char {
+func AddSparkline(idx int, sheet *C.char, opts *C.struct_SparklineOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.SparklineOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.SparklineOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.SparklineOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddSparkline(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddTable provides the method to add table in a worksheet by given worksheet
 // name, range reference and format set.
 //
 //export AddTable
-func AddTable(idx int, sheet *C.char, table *C.struct_Table) *C.char {
+func AddTable(idx int, sheet *C.char, table *C.struct_Table) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var tbl excelize.Table
 	goVal, err := cValueToGo(reflect.ValueOf(*table), reflect.TypeOf(excelize.Table{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	tbl = goVal.Elem().Interface().(excelize.Table)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AddTable(C.GoString(sheet), &tbl); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AddVBAProject provides the method to add vbaProject.bin file which contains
 // functions and/or macros. The file extension should be XLSM or XLTM.
 //
 //export AddVBAProject
-func AddVBAProject(idx int, file *C.uchar, fileLen C.int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AddVBAProject(idx int, file *C.uchar, fileLen C.int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	buf := C.GoBytes(unsafe.Pointer(file), fileLen)
 	if err := f.(*excelize.File).AddVBAProject(buf); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AutoFilter provides the method to add auto filter in a worksheet by given
@@ -832,23 +3220,25 @@ func AddVBAProject(idx int, file *C.uchar, fileLen C.int) *C.char {
 // way of filtering a 2D range of data based on some simple criteria.
 //
 //export AutoFilter
-func AutoFilter(idx int, sheet, rangeRef *C.char, opts *C.struct_AutoFilterOptions, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AutoFilter(idx int, sheet, rangeRef *C.char, opts *C.struct_AutoFilterOptions, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := make([]excelize.AutoFilterOptions, length)
 	for i, val := range unsafe.Slice(opts, length) {
 		goVal, err := cValueToGo(reflect.ValueOf(val), reflect.TypeOf(excelize.AutoFilterOptions{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options[i] = goVal.Elem().Interface().(excelize.AutoFilterOptions)
 	}
 	if err := f.(*excelize.File).AutoFilter(C.GoString(sheet), C.GoString(rangeRef), options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // AutoFitColWidth provides a function to auto fit columns width according to
@@ -862,15 +3252,17 @@ func AutoFilter(idx int, sheet, rangeRef *C.char, opts *C.struct_AutoFilterOptio
 // processing.
 //
 //export AutoFitColWidth
-func AutoFitColWidth(idx int, sheet, columns *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func AutoFitColWidth(idx int, sheet, columns *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).AutoFitColWidth(C.GoString(sheet), C.GoString(columns)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // CalcCellValue provides a function to get calculated cell value. This feature
@@ -879,47 +3271,210 @@ func AutoFitColWidth(idx int, sheet, columns *C.char) *C.char {
 // other formulas are not supported currently.
 //
 //export CalcCellValue
-func CalcCellValue(idx int, sheet, cell *C.char, opts *C.struct_Options) C.struct_StringErrorResult {
-	var options excelize.Options
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(errFilePtr)}
+func CalcCellValue(idx int, sheet, cell *C.char, opts *C.struct_Options) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	var options []excelize.Options
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		options = append(options, goVal.Elem().Interface().(excelize.Options))
+	}
+	val, err := calcCellValue(idx, C.GoString(sheet), C.GoString(cell), options)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(emptyString)}
+}
+
+// CalcCellValueWithTimeout provides a function to get calculated cell value
+// with a timeout in milliseconds, 0 means no timeout. The calculation returns
+// the timeout error once the timeout has elapsed, or the cancellation error
+// once the "CancelCalc" function was called for the workbook. Note that the
+// interrupted calculation can not be stopped, it keeps running in the
+// background until it returns, and the functions modifying the workbook wait
+// for it. The number of the calculations running in the background is limited
+// by the number of CPUs, the calculations beyond it wait for a free slot.
+//
+//export CalcCellValueWithTimeout
+func CalcCellValueWithTimeout(idx int, sheet, cell *C.char, opts *C.struct_Options, timeout C.int) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	var options []excelize.Options
+	if timeout < 0 {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(excelize.ErrParameterInvalid), err: C.CString(excelize.ErrParameterInvalid.Error())}
 	}
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(err.Error())}
+			return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		options = goVal.Elem().Interface().(excelize.Options)
+		options = append(options, goVal.Elem().Interface().(excelize.Options))
+	}
+	val, err := calcCellValueWithTimeout(idx, C.GoString(sheet), C.GoString(cell), options, time.Duration(timeout)*time.Millisecond)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(emptyString)}
+}
+
+// calcCellValue calculates the cell value in the workbook under the read lock
+// of the workbook, and reports the progress of the calculation.
+func calcCellValue(idx int, sheet, cell string, opts []excelize.Options) (string, error) {
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return emptyString, err
+	}
+	progress := files.progress(idx, C.ProgressCalc, sheet)
+	if err := progress.report(0, 0, 0, false); err != nil {
+		return emptyString, err
+	}
+	val, err := f.(*excelize.File).CalcCellValue(sheet, cell, opts...)
+	if err != nil {
+		return val, err
+	}
+	return val, progress.report(0, 0, 0, true)
+}
+
+// calcCellValueWithTimeout calculates the cell value in the workbook with the
+// timeout, 0 means no timeout. The calculation can be canceled by the
+// "CancelCalc" function and the progress callback of the workbook. It waits
+// for a slot of the calculations running in the background before starting.
+func calcCellValueWithTimeout(idx int, sheet, cell string, opts []excelize.Options, timeout time.Duration) (string, error) {
+	// Join the calculations in flight before waiting for the slot and the
+	// lock, so the cancellation during waiting will not be lost
+	ctx := files.context(idx)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	select {
+	case calcSlots <- struct{}{}:
+	case <-ctx.Done():
+		return emptyString, calcContextError(ctx)
+	}
+	unlock := files.rlock(idx)
+	release := func() {
+		unlock()
+		<-calcSlots
+	}
+	f, err := files.load(idx)
+	if err != nil {
+		release()
+		return emptyString, err
+	}
+	progress := files.progress(idx, C.ProgressCalc, sheet)
+	if err := progress.report(0, 0, 0, false); err != nil {
+		release()
+		return emptyString, err
+	}
+	if ctx.Err() != nil {
+		release()
+		return emptyString, calcContextError(ctx)
 	}
-	val, err := f.(*excelize.File).CalcCellValue(C.GoString(sheet), C.GoString(cell), options)
+	return runCalc(ctx, progress, release, func() (string, error) {
+		return f.(*excelize.File).CalcCellValue(sheet, cell, opts...)
+	})
+}
+
+// CallJSON provides a function to call the method of the workbook by given
+// method name, with the arguments encoded as a JSON array. The arguments are
+// decoded into the parameter types of the method, such as excelize.Chart,
+// excelize.Style and excelize.PivotTableOptions, so the option fields don't
+// need to be declared in the C structures. It returns the results of the
+// method except the error encoded as JSON: null if the method has no results,
+// the value if it has one result, or an array of the results otherwise.
+//
+//export CallJSON
+func CallJSON(idx int, method, args *C.char) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	name := C.GoString(method)
+	if name == "CalcCellValue" {
+		// The calculation reports the progress as the CalcCellValue function
+		calc := func(sheet, cell string, opts ...excelize.Options) (string, error) {
+			return calcCellValue(idx, sheet, cell, opts)
+		}
+		val, err := callJSONFunc(reflect.ValueOf(calc), name, C.GoString(args))
+		if err != nil {
+			return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(emptyString)}
+	}
+	if jsonReadOnlyMethods[name] {
+		defer files.rlock(idx)()
+	} else {
+		defer files.lock(idx)()
+	}
+	f, err := files.load(idx)
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	val, err := callJSON(f.(*excelize.File), name, C.GoString(args))
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(emptyString)}
 }
 
+// CancelCalc provides a function to cancel the calculations in flight on the
+// workbook by the "CalcCellValueWithTimeout" function, the canceled
+// calculations return the cancellation error, and the calculations started
+// after it will not be affected.
+//
+//export CancelCalc
+func CancelCalc(idx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	if err := files.cancel(idx); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
 // CellNameToCoordinates converts alphanumeric cell name to [X, Y] coordinates
 // or returns an error.
 //
 //export CellNameToCoordinates
-func CellNameToCoordinates(cell *C.char) C.struct_CellNameToCoordinatesResult {
+func CellNameToCoordinates(cell *C.char) (res C.struct_CellNameToCoordinatesResult) {
+	defer finalizeResult(&res)
 	col, row, err := excelize.CellNameToCoordinates(C.GoString(cell))
 	if err != nil {
-		return C.struct_CellNameToCoordinatesResult{col: C.int(col), row: C.int(row), err: C.CString(err.Error())}
+		return C.struct_CellNameToCoordinatesResult{col: C.int(col), row: C.int(row), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_CellNameToCoordinatesResult{col: C.int(col), row: C.int(row), err: C.CString(emptyString)}
 }
 
+// CheckConverters provides a function to check the converters of the option
+// types exchanged with the caller, it walks the fields of all option types
+// and returns the fields which can not be converted between the Go and C
+// structures. It returns an empty list if all fields can be converted.
+//
+//export CheckConverters
+func CheckConverters() (res C.struct_StringArrayErrorResult) {
+	defer finalizeResult(&res)
+	var problems []string
+	checked := map[converterKey]bool{}
+	for _, types := range optionTypes {
+		problems = append(problems, checkConverter(types[0], types[1], types[0].Name(), checked)...)
+	}
+	cArray := C.malloc(C.size_t(len(problems)) * C.size_t(unsafe.Sizeof(uintptr(0))))
+	for i, v := range problems {
+		*(*unsafe.Pointer)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(uintptr(0)))) = unsafe.Pointer(C.CString(v))
+	}
+	return C.struct_StringArrayErrorResult{ArrLen: C.int(len(problems)), Arr: (**C.char)(cArray), Err: C.CString(emptyString)}
+}
+
 // ColumnNameToNumber provides a function to convert Excel sheet column name
 // (case-insensitive) to int. The function returns an error if column name
 // incorrect.
 //
 //export ColumnNameToNumber
-func ColumnNameToNumber(name *C.char) C.struct_IntErrorResult {
+func ColumnNameToNumber(name *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
 	col, err := excelize.ColumnNameToNumber(C.GoString(name))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(col), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(col), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(col), err: C.CString(emptyString)}
 }
@@ -928,10 +3483,11 @@ func ColumnNameToNumber(name *C.char) C.struct_IntErrorResult {
 // sheet column title.
 //
 //export ColumnNumberToName
-func ColumnNumberToName(num int) C.struct_StringErrorResult {
+func ColumnNumberToName(num int) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
 	col, err := excelize.ColumnNumberToName(num)
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(col), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(col), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(col), err: C.CString(emptyString)}
 }
@@ -940,10 +3496,11 @@ func ColumnNumberToName(num int) C.struct_StringErrorResult {
 // or returns an error.
 //
 //export CoordinatesToCellName
-func CoordinatesToCellName(col, row int, abs bool) C.struct_StringErrorResult {
+func CoordinatesToCellName(col, row int, abs bool) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
 	cell, err := excelize.CoordinatesToCellName(col, row, abs)
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(cell), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(cell), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(cell), err: C.CString(emptyString)}
 }
@@ -951,16 +3508,112 @@ func CoordinatesToCellName(col, row int, abs bool) C.struct_StringErrorResult {
 // Close closes and cleanup the open temporary file for the spreadsheet.
 //
 //export Close
-func Close(idx int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func Close(idx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.release(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	defer files.Delete(idx)
 	if err := f.(*excelize.File).Close(); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// Cols returns a columns iterator, used for streaming reading data for a
+// worksheet with a large data. This function is concurrency safe.
+//
+//export Cols
+func Cols(idx int, sheet *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	cols, err := f.(*excelize.File).Cols(C.GoString(sheet))
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_IntErrorResult{val: C.int(colsIterator.storeChild(cols, idx)), err: C.CString(emptyString)}
+}
+
+// ColsClose releases the columns iterator, the handle of the iterator can not
+// be used after it was closed.
+//
+//export ColsClose
+func ColsClose(cIdx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(colsIterator.parent(cIdx))()
+	defer colsIterator.lock(cIdx)()
+	if _, err := colsIterator.release(cIdx); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// ColsError will return the error when the error occurs.
+//
+//export ColsError
+func ColsError(cIdx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(colsIterator.parent(cIdx))()
+	defer colsIterator.lock(cIdx)()
+	col, err := colsIterator.load(cIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	if err = col.(*excelize.Cols).Error(); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// ColsNext will return true if the next column is found.
+//
+//export ColsNext
+func ColsNext(cIdx int) (res C.struct_BoolErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(colsIterator.parent(cIdx))()
+	defer colsIterator.lock(cIdx)()
+	col, err := colsIterator.load(cIdx)
+	if err != nil {
+		return C.struct_BoolErrorResult{val: C._Bool(false), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_BoolErrorResult{val: C._Bool(col.(*excelize.Cols).Next()), err: C.CString(emptyString)}
+}
+
+// ColsRows return the current column's row values. This fetches the worksheet
+// data as a stream, returns each cell in a column as is, and will not skip
+// empty columns in the tail of the worksheet.
+//
+//export ColsRows
+func ColsRows(cIdx int, opts *C.struct_Options) (res C.struct_StringArrayErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(colsIterator.parent(cIdx))()
+	defer colsIterator.lock(cIdx)()
+	var options excelize.Options
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+		options = goVal.Elem().Interface().(excelize.Options)
+	}
+	col, err := colsIterator.load(cIdx)
+	if err != nil {
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	result, err := col.(*excelize.Cols).Rows(options)
+	if err != nil {
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	cArray := C.malloc(C.size_t(len(result)) * C.size_t(unsafe.Sizeof(uintptr(0))))
+	for i, v := range result {
+		*(*unsafe.Pointer)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(uintptr(0)))) = unsafe.Pointer(C.CString(v))
+	}
+	return C.struct_StringArrayErrorResult{ArrLen: C.int(len(result)), Arr: (**C.char)(cArray), Err: C.CString(emptyString)}
 }
 
 // CopySheet provides a function to duplicate a worksheet by gave source and
@@ -968,45 +3621,51 @@ func Close(idx int) *C.char {
 // workbooks that contain tables, charts or pictures.
 //
 //export CopySheet
-func CopySheet(idx, from, to int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func CopySheet(idx, from, to int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).CopySheet(from, to); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeleteChart provides a function to delete chart in spreadsheet by given
 // worksheet name and cell reference.
 //
 //export DeleteChart
-func DeleteChart(idx int, sheet, cell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DeleteChart(idx int, sheet, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeleteChart(C.GoString(sheet), C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeleteComment provides the method to delete comment in a sheet by given
 // worksheet name.
 //
 //export DeleteComment
-func DeleteComment(idx int, sheet, cell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DeleteComment(idx int, sheet, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeleteComment(C.GoString(sheet), C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeleteDefinedName provides a function to delete the defined names of the
@@ -1014,36 +3673,40 @@ func DeleteComment(idx int, sheet, cell *C.char) *C.char {
 // workbook.
 //
 //export DeleteDefinedName
-func DeleteDefinedName(idx int, definedName *C.struct_DefinedName) *C.char {
+func DeleteDefinedName(idx int, definedName *C.struct_DefinedName) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var df excelize.DefinedName
 	goVal, err := cValueToGo(reflect.ValueOf(*definedName), reflect.TypeOf(excelize.DefinedName{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	df = goVal.Elem().Interface().(excelize.DefinedName)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeleteDefinedName(&df); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeleteFormControl provides the method to delete form control in a worksheet
 // by given worksheet name and cell reference.
 //
 //export DeleteFormControl
-func DeleteFormControl(idx int, sheet, cell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DeleteFormControl(idx int, sheet, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeleteFormControl(C.GoString(sheet), C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeletePicture provides a function to delete charts in spreadsheet by given
@@ -1051,15 +3714,17 @@ func DeleteFormControl(idx int, sheet, cell *C.char) *C.char {
 // deleted from the document currently.
 //
 //export DeletePicture
-func DeletePicture(idx int, sheet, cell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DeletePicture(idx int, sheet, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeletePicture(C.GoString(sheet), C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeleteSheet provides a function to delete worksheet in a workbook by given
@@ -1069,29 +3734,33 @@ func DeletePicture(idx int, sheet, cell *C.char) *C.char {
 // it. This function will be invalid when only one worksheet is left.
 //
 //export DeleteSheet
-func DeleteSheet(idx int, sheet *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DeleteSheet(idx int, sheet *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeleteSheet(C.GoString(sheet)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DeleteSlicer provides the method to delete a slicer by a given slicer name.
 //
 //export DeleteSlicer
-func DeleteSlicer(idx int, name *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DeleteSlicer(idx int, name *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DeleteSlicer(C.GoString(name)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DuplicateRow inserts a copy of specified row (by its Excel row number)
@@ -1101,15 +3770,17 @@ func DeleteSlicer(idx int, name *C.char) *C.char {
 // excelize only partially updates these references currently.
 //
 //export DuplicateRow
-func DuplicateRow(idx int, sheet *C.char, row int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DuplicateRow(idx int, sheet *C.char, row int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DuplicateRow(C.GoString(sheet), row); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // DuplicateRowTo inserts a copy of specified row by it Excel number to
@@ -1120,24 +3791,410 @@ func DuplicateRow(idx int, sheet *C.char, row int) *C.char {
 // partially updates these references currently.
 //
 //export DuplicateRowTo
-func DuplicateRowTo(idx int, sheet *C.char, row, row2 int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func DuplicateRowTo(idx int, sheet *C.char, row, row2 int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).DuplicateRowTo(C.GoString(sheet), row, row2); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// FreeBoolErrorResult releases the error message of the BoolErrorResult.
+//
+//export FreeBoolErrorResult
+func FreeBoolErrorResult(result *C.struct_BoolErrorResult) {
+	if result == nil {
+		return
+	}
+	C.free(unsafe.Pointer(result.err))
+	*result = C.struct_BoolErrorResult{}
+}
+
+// FreeBuildInfoResult releases the memory allocated for the BuildInfoResult,
+// including the strings, arrays and structures referenced by it.
+//
+//export FreeBuildInfoResult
+func FreeBuildInfoResult(result *C.struct_BuildInfoResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeBytesErrorResult releases the memory allocated for the BytesErrorResult,
+// including the strings, arrays and structures referenced by it.
+//
+//export FreeBytesErrorResult
+func FreeBytesErrorResult(result *C.struct_BytesErrorResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeCellNameToCoordinatesResult releases the memory allocated for the
+// CellNameToCoordinatesResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeCellNameToCoordinatesResult
+func FreeCellNameToCoordinatesResult(result *C.struct_CellNameToCoordinatesResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeErrorResult releases the error message of the ErrorResult.
+//
+//export FreeErrorResult
+func FreeErrorResult(result *C.struct_ErrorResult) {
+	if result == nil {
+		return
+	}
+	C.free(unsafe.Pointer(result.err))
+	*result = C.struct_ErrorResult{}
+}
+
+// FreeFloat64ErrorResult releases the error message of the
+// Float64ErrorResult.
+//
+//export FreeFloat64ErrorResult
+func FreeFloat64ErrorResult(result *C.struct_Float64ErrorResult) {
+	if result == nil {
+		return
+	}
+	C.free(unsafe.Pointer(result.err))
+	*result = C.struct_Float64ErrorResult{}
+}
+
+// FreeGetAppPropsResult releases the memory allocated for the
+// GetAppPropsResult, including the strings, arrays and structures referenced by
+// it.
+//
+//export FreeGetAppPropsResult
+func FreeGetAppPropsResult(result *C.struct_GetAppPropsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetCalcPropsResult releases the memory allocated for the
+// GetCalcPropsResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetCalcPropsResult
+func FreeGetCalcPropsResult(result *C.struct_GetCalcPropsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetCellHyperLinkResult releases the memory allocated for the
+// GetCellHyperLinkResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeGetCellHyperLinkResult
+func FreeGetCellHyperLinkResult(result *C.struct_GetCellHyperLinkResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetCellRichTextResult releases the memory allocated for the
+// GetCellRichTextResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeGetCellRichTextResult
+func FreeGetCellRichTextResult(result *C.struct_GetCellRichTextResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetCommentsResult releases the memory allocated for the
+// GetCommentsResult, including the strings, arrays and structures referenced by
+// it.
+//
+//export FreeGetCommentsResult
+func FreeGetCommentsResult(result *C.struct_GetCommentsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetCustomPropsResult releases the memory allocated for the
+// GetCustomPropsResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetCustomPropsResult
+func FreeGetCustomPropsResult(result *C.struct_GetCustomPropsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetDataValidationsResult releases the memory allocated for the
+// GetDataValidationsResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeGetDataValidationsResult
+func FreeGetDataValidationsResult(result *C.struct_GetDataValidationsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetDefinedNameResult releases the memory allocated for the
+// GetDefinedNameResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetDefinedNameResult
+func FreeGetDefinedNameResult(result *C.struct_GetDefinedNameResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetDocPropsResult releases the memory allocated for the
+// GetDocPropsResult, including the strings, arrays and structures referenced by
+// it.
+//
+//export FreeGetDocPropsResult
+func FreeGetDocPropsResult(result *C.struct_GetDocPropsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetFormControlsResult releases the memory allocated for the
+// GetFormControlsResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeGetFormControlsResult
+func FreeGetFormControlsResult(result *C.struct_GetFormControlsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetPageLayoutResult releases the memory allocated for the
+// GetPageLayoutResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetPageLayoutResult
+func FreeGetPageLayoutResult(result *C.struct_GetPageLayoutResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetPageMarginsResult releases the memory allocated for the
+// GetPageMarginsResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetPageMarginsResult
+func FreeGetPageMarginsResult(result *C.struct_GetPageMarginsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetPicturesResult releases the memory allocated for the
+// GetPicturesResult, including the strings, arrays and structures referenced by
+// it.
+//
+//export FreeGetPicturesResult
+func FreeGetPicturesResult(result *C.struct_GetPicturesResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetPivotTablesResult releases the memory allocated for the
+// GetPivotTablesResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetPivotTablesResult
+func FreeGetPivotTablesResult(result *C.struct_GetPivotTablesResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetRowOptsResult releases the memory allocated for the GetRowOptsResult,
+// including the strings, arrays and structures referenced by it.
+//
+//export FreeGetRowOptsResult
+func FreeGetRowOptsResult(result *C.struct_GetRowOptsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetSheetMapResult releases the memory allocated for the
+// GetSheetMapResult, including the strings, arrays and structures referenced by
+// it.
+//
+//export FreeGetSheetMapResult
+func FreeGetSheetMapResult(result *C.struct_GetSheetMapResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetSheetPropsResult releases the memory allocated for the
+// GetSheetPropsResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetSheetPropsResult
+func FreeGetSheetPropsResult(result *C.struct_GetSheetPropsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetSheetProtectionResult releases the memory allocated for the
+// GetSheetProtectionResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeGetSheetProtectionResult
+func FreeGetSheetProtectionResult(result *C.struct_GetSheetProtectionResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetSheetViewResult releases the memory allocated for the
+// GetSheetViewResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetSheetViewResult
+func FreeGetSheetViewResult(result *C.struct_GetSheetViewResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetSlicersResult releases the memory allocated for the GetSlicersResult,
+// including the strings, arrays and structures referenced by it.
+//
+//export FreeGetSlicersResult
+func FreeGetSlicersResult(result *C.struct_GetSlicersResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetStyleResult releases the memory allocated for the GetStyleResult,
+// including the strings, arrays and structures referenced by it.
+//
+//export FreeGetStyleResult
+func FreeGetStyleResult(result *C.struct_GetStyleResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetTableRowsResult releases the memory allocated for the
+// GetTableRowsResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeGetTableRowsResult
+func FreeGetTableRowsResult(result *C.struct_GetTableRowsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetTablesResult releases the memory allocated for the GetTablesResult,
+// including the strings, arrays and structures referenced by it.
+//
+//export FreeGetTablesResult
+func FreeGetTablesResult(result *C.struct_GetTablesResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeGetWorkbookPropsResult releases the memory allocated for the
+// GetWorkbookPropsResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeGetWorkbookPropsResult
+func FreeGetWorkbookPropsResult(result *C.struct_GetWorkbookPropsResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeIntErrorResult releases the error message of the IntErrorResult.
+//
+//export FreeIntErrorResult
+func FreeIntErrorResult(result *C.struct_IntErrorResult) {
+	if result == nil {
+		return
+	}
+	C.free(unsafe.Pointer(result.err))
+	*result = C.struct_IntErrorResult{}
+}
+
+// FreeInterfaceErrorResult releases the memory allocated for the
+// InterfaceErrorResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeInterfaceErrorResult
+func FreeInterfaceErrorResult(result *C.struct_InterfaceErrorResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeListHandlesResult releases the memory allocated for the
+// ListHandlesResult, including the strings, arrays and structures referenced by
+// it.
+//
+//export FreeListHandlesResult
+func FreeListHandlesResult(result *C.struct_ListHandlesResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeStringArrayErrorResult releases the memory allocated for the
+// StringArrayErrorResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeStringArrayErrorResult
+func FreeStringArrayErrorResult(result *C.struct_StringArrayErrorResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeStringErrorResult releases the string value and the error message of
+// the StringErrorResult.
+//
+//export FreeStringErrorResult
+func FreeStringErrorResult(result *C.struct_StringErrorResult) {
+	if result == nil {
+		return
 	}
-	return C.CString(emptyString)
+	C.free(unsafe.Pointer(result.val))
+	C.free(unsafe.Pointer(result.err))
+	*result = C.struct_StringErrorResult{}
+}
+
+// FreeStringIntErrorResult releases the memory allocated for the
+// StringIntErrorResult, including the strings, arrays and structures referenced
+// by it.
+//
+//export FreeStringIntErrorResult
+func FreeStringIntErrorResult(result *C.struct_StringIntErrorResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeStringMatrixErrorResult releases the memory allocated for the
+// StringMatrixErrorResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeStringMatrixErrorResult
+func FreeStringMatrixErrorResult(result *C.struct_StringMatrixErrorResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
+}
+
+// FreeTypedCellMatrixErrorResult releases the memory allocated for the
+// TypedCellMatrixErrorResult, including the strings, arrays and structures
+// referenced by it.
+//
+//export FreeTypedCellMatrixErrorResult
+func FreeTypedCellMatrixErrorResult(result *C.struct_TypedCellMatrixErrorResult) {
+	defer finalizeResult(nil)
+	freeResult(result)
 }
 
 // GetActiveSheetIndex provides a function to get active sheet index of the
 // spreadsheet. If not found the active sheet will be return integer 0.
 //
 //export GetActiveSheetIndex
-func GetActiveSheetIndex(idx int) int {
-	f, ok := files.Load(idx)
-	if !ok {
+func GetActiveSheetIndex(idx int) (res int) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
 		return 0
 	}
 	return f.(*excelize.File).GetActiveSheetIndex()
@@ -1146,37 +4203,76 @@ func GetActiveSheetIndex(idx int) int {
 // GetAppProps provides a function to get document application properties.
 //
 //export GetAppProps
-func GetAppProps(idx int) C.struct_GetAppPropsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetAppPropsResult{err: C.CString(errFilePtr)}
+func GetAppProps(idx int) (res C.struct_GetAppPropsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetAppPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetAppProps()
 	if err != nil {
-		return C.struct_GetAppPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetAppPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(*opts), reflect.ValueOf(&C.struct_AppProperties{}))
 	if err != nil {
-		return C.struct_GetAppPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetAppPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetAppPropsResult{opts: cVal.Elem().Interface().(C.struct_AppProperties), err: C.CString(emptyString)}
 }
 
+// GetBuildInfo provides a function to get the build information of the
+// library, including the version and the ABI version of the library, the
+// linked excelize library and Go, and the registered image decoders. This is
+// useful to verify the Python package and the library are matched.
+//
+//export GetBuildInfo
+func GetBuildInfo() (res C.struct_BuildInfoResult) {
+	defer finalizeResult(&res)
+	type BuildInfoResult struct {
+		Version         string
+		ABIVersion      int
+		ExcelizeVersion string
+		GoVersion       string
+		ImageDecoders   []string
+	}
+	result := BuildInfoResult{Version: version, ABIVersion: abiVersion, GoVersion: runtime.Version(), ImageDecoders: imageDecoders}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/xuri/excelize/v2" {
+				result.ExcelizeVersion = dep.Version
+				if dep.Replace != nil && dep.Replace.Version != "" {
+					result.ExcelizeVersion = dep.Replace.Version
+				}
+			}
+		}
+	}
+	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_BuildInfoResult{}))
+	if err != nil {
+		return C.struct_BuildInfoResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	ret := cVal.Elem().Interface().(C.struct_BuildInfoResult)
+	ret.Err = C.CString(emptyString)
+	return ret
+}
+
 // GetCalcProps provides a function to gets calculation properties.
 //
 //export GetCalcProps
-func GetCalcProps(idx int) C.struct_GetCalcPropsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetCalcPropsResult{err: C.CString(errFilePtr)}
+func GetCalcProps(idx int) (res C.struct_GetCalcPropsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetCalcPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetCalcProps()
 	if err != nil {
-		return C.struct_GetCalcPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetCalcPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_CalcPropsOptions{}))
 	if err != nil {
-		return C.struct_GetCalcPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetCalcPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetCalcPropsResult{opts: cVal.Elem().Interface().(C.struct_CalcPropsOptions), err: C.CString(emptyString)}
 }
@@ -1185,14 +4281,16 @@ func GetCalcProps(idx int) C.struct_GetCalcPropsResult {
 // worksheet name and cell reference in spreadsheet.
 //
 //export GetCellFormula
-func GetCellFormula(idx int, sheet, cell *C.char) C.struct_StringErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(errFilePtr)}
+func GetCellFormula(idx int, sheet, cell *C.char) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	formula, err := f.(*excelize.File).GetCellFormula(C.GoString(sheet), C.GoString(cell))
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(formula), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(formula), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(formula), err: C.CString(emptyString)}
 }
@@ -1203,14 +4301,16 @@ func GetCellFormula(idx int, sheet, cell *C.char) C.struct_StringErrorResult {
 // address.
 //
 //export GetCellHyperLink
-func GetCellHyperLink(idx int, sheet, cell *C.char) C.struct_GetCellHyperLinkResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetCellHyperLinkResult{link: false, target: C.CString(emptyString), err: C.CString(errFilePtr)}
+func GetCellHyperLink(idx int, sheet, cell *C.char) (res C.struct_GetCellHyperLinkResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetCellHyperLinkResult{link: false, target: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	link, target, err := f.(*excelize.File).GetCellHyperLink(C.GoString(sheet), C.GoString(cell))
 	if err != nil {
-		return C.struct_GetCellHyperLinkResult{link: C._Bool(link), target: C.CString(target), err: C.CString(err.Error())}
+		return C.struct_GetCellHyperLinkResult{link: C._Bool(link), target: C.CString(target), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetCellHyperLinkResult{link: C._Bool(link), target: C.CString(target), err: C.CString(emptyString)}
 }
@@ -1219,20 +4319,22 @@ func GetCellHyperLink(idx int, sheet, cell *C.char) C.struct_GetCellHyperLinkRes
 // worksheet and cell reference.
 //
 //export GetCellRichText
-func GetCellRichText(idx int, sheet, cell *C.char) C.struct_GetCellRichTextResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetCellRichTextResult{Err: C.CString(errFilePtr)}
+func GetCellRichText(idx int, sheet, cell *C.char) (res C.struct_GetCellRichTextResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetCellRichTextResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	runs, err := f.(*excelize.File).GetCellRichText(C.GoString(sheet), C.GoString(cell))
 	if err != nil {
-		return C.struct_GetCellRichTextResult{Err: C.CString(err.Error())}
+		return C.struct_GetCellRichTextResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(runs)) * C.size_t(unsafe.Sizeof(C.struct_RichTextRun{})))
 	for i, r := range runs {
 		cVal, err := goValueToC(reflect.ValueOf(r), reflect.ValueOf(&C.struct_RichTextRun{}))
 		if err != nil {
-			return C.struct_GetCellRichTextResult{Err: C.CString(err.Error())}
+			return C.struct_GetCellRichTextResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_RichTextRun)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_RichTextRun{}))) = cVal.Elem().Interface().(C.struct_RichTextRun)
 	}
@@ -1243,18 +4345,85 @@ func GetCellRichText(idx int, sheet, cell *C.char) C.struct_GetCellRichTextResul
 // name and cell reference. This function is concurrency safe.
 //
 //export GetCellStyle
-func GetCellStyle(idx int, sheet, cell *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+func GetCellStyle(idx int, sheet, cell *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	idx, err := f.(*excelize.File).GetCellStyle(C.GoString(sheet), C.GoString(cell))
+	idx, err = f.(*excelize.File).GetCellStyle(C.GoString(sheet), C.GoString(cell))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(idx), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
 }
 
+// GetCellType provides a function to get the cell's data type by given
+// worksheet name and cell reference in spreadsheet file.
+//
+//export GetCellType
+func GetCellType(idx int, sheet, cell *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	cellType, err := f.(*excelize.File).GetCellType(C.GoString(sheet), C.GoString(cell))
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(cellType), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_IntErrorResult{val: C.int(cellType), err: C.CString(emptyString)}
+}
+
+// GetCellTypedValue provides a function to get the typed value from cell by
+// given worksheet name and cell reference in spreadsheet. The value is built
+// from the cell type and the raw value of the cell, the number cell returns a
+// float number, or a time if the number format of the cell is a date or time
+// number format, the boolean cell returns a boolean, the date cell returns a
+// time, the error and formula string cells return the error code and the
+// formula result with the corresponding types, other cells return the string.
+//
+//export GetCellTypedValue
+func GetCellTypedValue(idx int, sheet, cell *C.char) (res C.struct_InterfaceErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	cellType, err := f.(*excelize.File).GetCellType(C.GoString(sheet), C.GoString(cell))
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	raw, err := f.(*excelize.File).GetCellValue(C.GoString(sheet), C.GoString(cell), excelize.Options{RawCellValue: true})
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	col, row, err := excelize.CellNameToCoordinates(C.GoString(cell))
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	styles, err := newSheetStyles(f.(*excelize.File), C.GoString(sheet), row, row)
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	styleID, err := styles.style(col, row)
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	reader, err := newTypedValueReader(f.(*excelize.File))
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	val, err := reader.value(cellType, styleID, raw)
+	if err != nil {
+		return C.struct_InterfaceErrorResult{val: C.struct_Interface{Type: Nil}, ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_InterfaceErrorResult{val: val, err: C.CString(emptyString)}
+}
+
 // GetCellValue provides a function to get formatted value from cell by given
 // worksheet name and cell reference in spreadsheet. The return value is
 // converted to the `string` data type. If the cell format can be applied to
@@ -1263,22 +4432,24 @@ func GetCellStyle(idx int, sheet, cell *C.char) C.struct_IntErrorResult {
 // merged range.
 //
 //export GetCellValue
-func GetCellValue(idx int, sheet, cell *C.char, opts *C.struct_Options) C.struct_StringErrorResult {
+func GetCellValue(idx int, sheet, cell *C.char, opts *C.struct_Options) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.Options
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(errFilePtr)}
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(err.Error())}
+			return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
 	}
 	val, err := f.(*excelize.File).GetCellValue(C.GoString(sheet), C.GoString(cell), options)
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(emptyString)}
 }
@@ -1287,14 +4458,16 @@ func GetCellValue(idx int, sheet, cell *C.char, opts *C.struct_Options) C.struct
 // column by given worksheet name and column name.
 //
 //export GetColOutlineLevel
-func GetColOutlineLevel(idx int, sheet, col *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+func GetColOutlineLevel(idx int, sheet, col *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetColOutlineLevel(C.GoString(sheet), C.GoString(col))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(int32(val)), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(int32(val)), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(int32(val)), err: C.CString(emptyString)}
 }
@@ -1303,14 +4476,16 @@ func GetColOutlineLevel(idx int, sheet, col *C.char) C.struct_IntErrorResult {
 // name and column name. This function is concurrency safe.
 //
 //export GetColStyle
-func GetColStyle(idx int, sheet, col *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+func GetColStyle(idx int, sheet, col *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetColStyle(C.GoString(sheet), C.GoString(col))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(val), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(val), err: C.CString(emptyString)}
 }
@@ -1319,14 +4494,16 @@ func GetColStyle(idx int, sheet, col *C.char) C.struct_IntErrorResult {
 // worksheet name and column name. This function is concurrency safe.
 //
 //export GetColVisible
-func GetColVisible(idx int, sheet, col *C.char) C.struct_BoolErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_BoolErrorResult{val: C._Bool(false), err: C.CString(errFilePtr)}
+func GetColVisible(idx int, sheet, col *C.char) (res C.struct_BoolErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_BoolErrorResult{val: C._Bool(false), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetColVisible(C.GoString(sheet), C.GoString(col))
 	if err != nil {
-		return C.struct_BoolErrorResult{val: C._Bool(val), err: C.CString(err.Error())}
+		return C.struct_BoolErrorResult{val: C._Bool(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_BoolErrorResult{val: C._Bool(val), err: C.CString(emptyString)}
 }
@@ -1335,14 +4512,16 @@ func GetColVisible(idx int, sheet, col *C.char) C.struct_BoolErrorResult {
 // and column name. This function is concurrency safe.
 //
 //export GetColWidth
-func GetColWidth(idx int, sheet, col *C.char) C.struct_Float64ErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_Float64ErrorResult{val: C.double(0), err: C.CString(errFilePtr)}
+func GetColWidth(idx int, sheet, col *C.char) (res C.struct_Float64ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_Float64ErrorResult{val: C.double(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetColWidth(C.GoString(sheet), C.GoString(col))
 	if err != nil {
-		return C.struct_Float64ErrorResult{val: C.double(val), err: C.CString(err.Error())}
+		return C.struct_Float64ErrorResult{val: C.double(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_Float64ErrorResult{val: C.double(val), err: C.CString(emptyString)}
 }
@@ -1354,25 +4533,27 @@ func GetColWidth(idx int, sheet, col *C.char) C.struct_Float64ErrorResult {
 // the original value will be used.
 //
 //export GetCols
-func GetCols(idx int, sheet *C.char, opts *C.struct_Options) C.struct_StringMatrixErrorResult {
+func GetCols(idx int, sheet *C.char, opts *C.struct_Options) (res C.struct_StringMatrixErrorResult) {
+	defer finalizeResult(&res)
 	var (
 		options excelize.Options
 		result  StringMatrixErrorResult
 	)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringMatrixErrorResult{err: C.CString(errFilePtr)}
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+			return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
 	}
 	cols, err := f.(*excelize.File).GetCols(C.GoString(sheet), options)
 	if err != nil {
-		return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	for _, col := range cols {
 		var c Cells
@@ -1381,7 +4562,7 @@ func GetCols(idx int, sheet *C.char, opts *C.struct_Options) C.struct_StringMatr
 	}
 	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_StringMatrixErrorResult{}))
 	if err != nil {
-		return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	ret := cVal.Elem().Interface().(C.struct_StringMatrixErrorResult)
 	ret.err = C.CString(emptyString)
@@ -1391,20 +4572,22 @@ func GetCols(idx int, sheet *C.char, opts *C.struct_Options) C.struct_StringMatr
 // GetComments retrieves all comments in a worksheet by given worksheet name.
 //
 //export GetComments
-func GetComments(idx int, sheet *C.char) C.struct_GetCommentsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetCommentsResult{Err: C.CString(errFilePtr)}
+func GetComments(idx int, sheet *C.char) (res C.struct_GetCommentsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetCommentsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	comments, err := f.(*excelize.File).GetComments(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetCommentsResult{Err: C.CString(err.Error())}
+		return C.struct_GetCommentsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(comments)) * C.size_t(unsafe.Sizeof(C.struct_Comment{})))
 	for i, r := range comments {
 		cVal, err := goValueToC(reflect.ValueOf(r), reflect.ValueOf(&C.struct_Comment{}))
 		if err != nil {
-			return C.struct_GetCommentsResult{Err: C.CString(err.Error())}
+			return C.struct_GetCommentsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_Comment)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_Comment{}))) = cVal.Elem().Interface().(C.struct_Comment)
 	}
@@ -1414,14 +4597,16 @@ func GetComments(idx int, sheet *C.char) C.struct_GetCommentsResult {
 // GetCustomProps provides a function to get custom file properties.
 //
 //export GetCustomProps
-func GetCustomProps(idx int) C.struct_GetCustomPropsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetCustomPropsResult{Err: C.CString(errFilePtr)}
+func GetCustomProps(idx int) (res C.struct_GetCustomPropsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetCustomPropsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	props, err := f.(*excelize.File).GetCustomProps()
 	if err != nil {
-		return C.struct_GetCustomPropsResult{Err: C.CString(err.Error())}
+		return C.struct_GetCustomPropsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(props)) * C.size_t(unsafe.Sizeof(C.struct_CustomProperty{})))
 	for i, prop := range props {
@@ -1434,20 +4619,22 @@ func GetCustomProps(idx int) C.struct_GetCustomPropsResult {
 // GetDataValidations returns data validations list by given worksheet name.
 //
 //export GetDataValidations
-func GetDataValidations(idx int, sheet *C.char) C.struct_GetDataValidationsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetDataValidationsResult{Err: C.CString(errFilePtr)}
+func GetDataValidations(idx int, sheet *C.char) (res C.struct_GetDataValidationsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetDataValidationsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	dvs, err := f.(*excelize.File).GetDataValidations(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetDataValidationsResult{Err: C.CString(err.Error())}
+		return C.struct_GetDataValidationsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(dvs)) * C.size_t(unsafe.Sizeof(C.struct_DataValidation{})))
 	for i, v := range dvs {
 		cVal, err := goValueToC(reflect.ValueOf(*v), reflect.ValueOf(&C.struct_DataValidation{}))
 		if err != nil {
-			return C.struct_GetDataValidationsResult{Err: C.CString(err.Error())}
+			return C.struct_GetDataValidationsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_DataValidation)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_DataValidation{}))) = cVal.Elem().Interface().(C.struct_DataValidation)
 	}
@@ -1458,14 +4645,16 @@ func GetDataValidations(idx int, sheet *C.char) C.struct_GetDataValidationsResul
 // workbook. The spreadsheet generated by excelize default font is Calibri.
 //
 //export GetDefaultFont
-func GetDefaultFont(idx int) C.struct_StringErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(errFilePtr)}
+func GetDefaultFont(idx int) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetDefaultFont()
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(val), err: C.CString(emptyString)}
 }
@@ -1474,17 +4663,19 @@ func GetDefaultFont(idx int) C.struct_StringErrorResult {
 // or worksheet.
 //
 //export GetDefinedName
-func GetDefinedName(idx int) C.struct_GetDefinedNameResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetDefinedNameResult{Err: C.CString(errFilePtr)}
+func GetDefinedName(idx int) (res C.struct_GetDefinedNameResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetDefinedNameResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	definedNames := f.(*excelize.File).GetDefinedName()
 	cArray := C.malloc(C.size_t(len(definedNames)) * C.size_t(unsafe.Sizeof(C.struct_DefinedName{})))
 	for i, dn := range definedNames {
 		cVal, err := goValueToC(reflect.ValueOf(dn), reflect.ValueOf(&C.struct_DefinedName{}))
 		if err != nil {
-			return C.struct_GetDefinedNameResult{Err: C.CString(err.Error())}
+			return C.struct_GetDefinedNameResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_DefinedName)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_DefinedName{}))) = cVal.Elem().Interface().(C.struct_DefinedName)
 	}
@@ -1494,18 +4685,20 @@ func GetDefinedName(idx int) C.struct_GetDefinedNameResult {
 // GetDocProps provides a function to get document core properties.
 //
 //export GetDocProps
-func GetDocProps(idx int) C.struct_GetDocPropsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetDocPropsResult{err: C.CString(errFilePtr)}
+func GetDocProps(idx int) (res C.struct_GetDocPropsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetDocPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetDocProps()
 	if err != nil {
-		return C.struct_GetDocPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetDocPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(*opts), reflect.ValueOf(&C.struct_DocProperties{}))
 	if err != nil {
-		return C.struct_GetDocPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetDocPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetDocPropsResult{opts: cVal.Elem().Interface().(C.struct_DocProperties), err: C.CString(emptyString)}
 }
@@ -1515,20 +4708,22 @@ func GetDocProps(idx int) C.struct_GetDocPropsResult {
 // and height of the form controls currently.
 //
 //export GetFormControls
-func GetFormControls(idx int, sheet *C.char) C.struct_GetFormControlsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetFormControlsResult{Err: C.CString(errFilePtr)}
+func GetFormControls(idx int, sheet *C.char) (res C.struct_GetFormControlsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetFormControlsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetFormControls(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetFormControlsResult{Err: C.CString(err.Error())}
+		return C.struct_GetFormControlsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(opts)) * C.size_t(unsafe.Sizeof(C.struct_FormControl{})))
 	for i, opt := range opts {
 		cVal, err := goValueToC(reflect.ValueOf(opt), reflect.ValueOf(&C.struct_FormControl{}))
 		if err != nil {
-			return C.struct_GetFormControlsResult{Err: C.CString(err.Error())}
+			return C.struct_GetFormControlsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_FormControl)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_FormControl{}))) = cVal.Elem().Interface().(C.struct_FormControl)
 	}
@@ -1542,14 +4737,16 @@ func GetFormControls(idx int, sheet *C.char) C.struct_GetFormControlsResult {
 // If linkType is empty, it will return all hyperlinks in the worksheet.
 //
 //export GetHyperLinkCells
-func GetHyperLinkCells(idx int, sheet, linkType *C.char) C.struct_StringArrayErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringArrayErrorResult{Err: C.CString(errFilePtr)}
+func GetHyperLinkCells(idx int, sheet, linkType *C.char) (res C.struct_StringArrayErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	result, err := f.(*excelize.File).GetHyperLinkCells(C.GoString(sheet), C.GoString(linkType))
 	if err != nil {
-		return C.struct_StringArrayErrorResult{Err: C.CString(err.Error())}
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(result)) * C.size_t(unsafe.Sizeof(uintptr(0))))
 	for i, v := range result {
@@ -1564,15 +4761,17 @@ func GetHyperLinkCells(idx int, sheet, linkType *C.char) C.struct_StringArrayErr
 // returned.
 //
 //export GetMergeCells
-func GetMergeCells(idx int, sheet *C.char, withoutValues bool) C.struct_StringMatrixErrorResult {
+func GetMergeCells(idx int, sheet *C.char, withoutValues bool) (res C.struct_StringMatrixErrorResult) {
+	defer finalizeResult(&res)
 	var result StringMatrixErrorResult
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringMatrixErrorResult{err: C.CString(errFilePtr)}
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	rows, err := f.(*excelize.File).GetMergeCells(C.GoString(sheet), withoutValues)
 	if err != nil {
-		return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	for _, row := range rows {
 		var r Cells
@@ -1581,7 +4780,7 @@ func GetMergeCells(idx int, sheet *C.char, withoutValues bool) C.struct_StringMa
 	}
 	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_StringMatrixErrorResult{}))
 	if err != nil {
-		return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	ret := cVal.Elem().Interface().(C.struct_StringMatrixErrorResult)
 	ret.err = C.CString(emptyString)
@@ -1591,18 +4790,20 @@ func GetMergeCells(idx int, sheet *C.char, withoutValues bool) C.struct_StringMa
 // GetPageLayout provides a function to gets worksheet page layout.
 //
 //export GetPageLayout
-func GetPageLayout(idx int, sheet *C.char) C.struct_GetPageLayoutResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetPageLayoutResult{err: C.CString(errFilePtr)}
+func GetPageLayout(idx int, sheet *C.char) (res C.struct_GetPageLayoutResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetPageLayoutResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetPageLayout(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetPageLayoutResult{err: C.CString(err.Error())}
+		return C.struct_GetPageLayoutResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_PageLayoutOptions{}))
 	if err != nil {
-		return C.struct_GetPageLayoutResult{err: C.CString(err.Error())}
+		return C.struct_GetPageLayoutResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetPageLayoutResult{opts: cVal.Elem().Interface().(C.struct_PageLayoutOptions), err: C.CString(emptyString)}
 }
@@ -1610,18 +4811,20 @@ func GetPageLayout(idx int, sheet *C.char) C.struct_GetPageLayoutResult {
 // GetPageMargins provides a function to get worksheet page margins.
 //
 //export GetPageMargins
-func GetPageMargins(idx int, sheet *C.char) C.struct_GetPageMarginsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetPageMarginsResult{err: C.CString(errFilePtr)}
+func GetPageMargins(idx int, sheet *C.char) (res C.struct_GetPageMarginsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetPageMarginsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetPageMargins(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetPageMarginsResult{err: C.CString(err.Error())}
+		return C.struct_GetPageMarginsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_PageLayoutMarginsOptions{}))
 	if err != nil {
-		return C.struct_GetPageMarginsResult{err: C.CString(err.Error())}
+		return C.struct_GetPageMarginsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetPageMarginsResult{opts: cVal.Elem().Interface().(C.struct_PageLayoutMarginsOptions), err: C.CString(emptyString)}
 }
@@ -1635,20 +4838,22 @@ func GetPageMargins(idx int, sheet *C.char) C.struct_GetPageMarginsResult {
 // a precision of two decimal places.
 //
 //export GetPictures
-func GetPictures(idx int, sheet, cell *C.char) C.struct_GetPicturesResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetPicturesResult{Err: C.CString(errFilePtr)}
+func GetPictures(idx int, sheet, cell *C.char) (res C.struct_GetPicturesResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetPicturesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	pics, err := f.(*excelize.File).GetPictures(C.GoString(sheet), C.GoString(cell))
 	if err != nil {
-		return C.struct_GetPicturesResult{Err: C.CString(err.Error())}
+		return C.struct_GetPicturesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(pics)) * C.size_t(unsafe.Sizeof(C.struct_Picture{})))
 	for i, pic := range pics {
 		cVal, err := goValueToC(reflect.ValueOf(pic), reflect.ValueOf(&C.struct_Picture{}))
 		if err != nil {
-			return C.struct_GetPicturesResult{Err: C.CString(err.Error())}
+			return C.struct_GetPicturesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_Picture)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_Picture{}))) = cVal.Elem().Interface().(C.struct_Picture)
 	}
@@ -1661,38 +4866,89 @@ func GetPictures(idx int, sheet, cell *C.char) C.struct_GetPicturesResult {
 // and scenario.
 //
 //export GetPivotTables
-func GetPivotTables(idx int, sheet *C.char) C.struct_GetPivotTablesResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetPivotTablesResult{Err: C.CString(errFilePtr)}
+func GetPivotTables(idx int, sheet *C.char) (res C.struct_GetPivotTablesResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetPivotTablesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	pivotTables, err := f.(*excelize.File).GetPivotTables(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetPivotTablesResult{Err: C.CString(err.Error())}
+		return C.struct_GetPivotTablesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(pivotTables)) * C.size_t(unsafe.Sizeof(C.struct_PivotTableOptions{})))
 	for i, tbl := range pivotTables {
 		cVal, err := goValueToC(reflect.ValueOf(tbl), reflect.ValueOf(&C.struct_PivotTableOptions{}))
 		if err != nil {
-			return C.struct_GetPivotTablesResult{Err: C.CString(err.Error())}
+			return C.struct_GetPivotTablesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_PivotTableOptions)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_PivotTableOptions{}))) = cVal.Elem().Interface().(C.struct_PivotTableOptions)
 	}
 	return C.struct_GetPivotTablesResult{PivotTablesLen: C.int(len(pivotTables)), PivotTables: (*C.struct_PivotTableOptions)(cArray), Err: C.CString(emptyString)}
 }
 
+// GetRange provides a function to get the values of the cells in a range by
+// given worksheet name and range reference, for example "C10:H5000", or a
+// defined name which refers to a range. The whole column reference such as
+// "A:C" and the whole row reference such as "1:3" are clamped to the used range
+// of the worksheet. The result is a rectangle matrix of the range, and the
+// value of each cell is resolved as the GetCellValue function does, including
+// the merged cells and the RawCellValue option.
+//
+//export GetRange
+func GetRange(idx int, sheet, rangeRef *C.char, opts *C.struct_Options) (res C.struct_StringMatrixErrorResult) {
+	defer finalizeResult(&res)
+	var (
+		options excelize.Options
+		result  StringMatrixErrorResult
+	)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		options = goVal.Elem().Interface().(excelize.Options)
+	}
+	name, coordinates, err := resolveRangeRef(f.(*excelize.File), C.GoString(sheet), C.GoString(rangeRef))
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	rows, err := getRangeValues(f.(*excelize.File), name, coordinates, options)
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	for _, row := range rows {
+		result.Row = append(result.Row, Cells{Cell: row})
+	}
+	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_StringMatrixErrorResult{}))
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	ret := cVal.Elem().Interface().(C.struct_StringMatrixErrorResult)
+	ret.err = C.CString(emptyString)
+	return ret
+}
+
 // GetRowHeight provides a function to get row height by given worksheet name
 // and row number.
 //
 //export GetRowHeight
-func GetRowHeight(idx int, sheet *C.char, row C.int) C.struct_Float64ErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_Float64ErrorResult{val: C.double(0), err: C.CString(errFilePtr)}
+func GetRowHeight(idx int, sheet *C.char, row C.int) (res C.struct_Float64ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_Float64ErrorResult{val: C.double(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetRowHeight(C.GoString(sheet), int(row))
 	if err != nil {
-		return C.struct_Float64ErrorResult{val: C.double(val), err: C.CString(err.Error())}
+		return C.struct_Float64ErrorResult{val: C.double(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_Float64ErrorResult{val: C.double(val), err: C.CString(emptyString)}
 }
@@ -1701,14 +4957,16 @@ func GetRowHeight(idx int, sheet *C.char, row C.int) C.struct_Float64ErrorResult
 // by given worksheet name and row number.
 //
 //export GetRowOutlineLevel
-func GetRowOutlineLevel(idx int, sheet *C.char, row C.int) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+func GetRowOutlineLevel(idx int, sheet *C.char, row C.int) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetRowOutlineLevel(C.GoString(sheet), int(row))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(int32(val)), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(int32(val)), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(int32(val)), err: C.CString(emptyString)}
 }
@@ -1717,14 +4975,16 @@ func GetRowOutlineLevel(idx int, sheet *C.char, row C.int) C.struct_IntErrorResu
 // worksheet name and Excel row number.
 //
 //export GetRowVisible
-func GetRowVisible(idx int, sheet *C.char, row int) C.struct_BoolErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_BoolErrorResult{val: C._Bool(false), err: C.CString(errFilePtr)}
+func GetRowVisible(idx int, sheet *C.char, row int) (res C.struct_BoolErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_BoolErrorResult{val: C._Bool(false), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	val, err := f.(*excelize.File).GetRowVisible(C.GoString(sheet), row)
 	if err != nil {
-		return C.struct_BoolErrorResult{val: C._Bool(val), err: C.CString(err.Error())}
+		return C.struct_BoolErrorResult{val: C._Bool(val), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_BoolErrorResult{val: C._Bool(val), err: C.CString(emptyString)}
 }
@@ -1738,25 +4998,27 @@ func GetRowVisible(idx int, sheet *C.char, row int) C.struct_BoolErrorResult {
 // may be inconsistent.
 //
 //export GetRows
-func GetRows(idx int, sheet *C.char, opts *C.struct_Options) C.struct_StringMatrixErrorResult {
+func GetRows(idx int, sheet *C.char, opts *C.struct_Options) (res C.struct_StringMatrixErrorResult) {
+	defer finalizeResult(&res)
 	var (
 		options excelize.Options
 		result  StringMatrixErrorResult
 	)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringMatrixErrorResult{err: C.CString(errFilePtr)}
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+			return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
 	}
 	rows, err := f.(*excelize.File).GetRows(C.GoString(sheet), options)
 	if err != nil {
-		return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	for _, row := range rows {
 		var r Cells
@@ -1765,7 +5027,7 @@ func GetRows(idx int, sheet *C.char, opts *C.struct_Options) C.struct_StringMatr
 	}
 	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_StringMatrixErrorResult{}))
 	if err != nil {
-		return C.struct_StringMatrixErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	ret := cVal.Elem().Interface().(C.struct_StringMatrixErrorResult)
 	ret.err = C.CString(emptyString)
@@ -1775,14 +5037,16 @@ func GetRows(idx int, sheet *C.char, opts *C.struct_Options) C.struct_StringMatr
 // GetSheetDimension provides the method to get the used range of the worksheet.
 //
 //export GetSheetDimension
-func GetSheetDimension(idx int, sheet *C.char) C.struct_StringErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(errFilePtr)}
+func GetSheetDimension(idx int, sheet *C.char) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	dimension, err := f.(*excelize.File).GetSheetDimension(C.GoString(sheet))
 	if err != nil {
-		return C.struct_StringErrorResult{val: C.CString(dimension), err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{val: C.CString(dimension), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(dimension), err: C.CString(emptyString)}
 }
@@ -1792,14 +5056,16 @@ func GetSheetDimension(idx int, sheet *C.char) C.struct_StringErrorResult {
 // exist, it will return an integer type value -1.
 //
 //export GetSheetIndex
-func GetSheetIndex(idx int, sheet *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(-1), err: C.CString(errFilePtr)}
+func GetSheetIndex(idx int, sheet *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	idx, err := f.(*excelize.File).GetSheetIndex(C.GoString(sheet))
+	idx, err = f.(*excelize.File).GetSheetIndex(C.GoString(sheet))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(idx), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
 }
@@ -1808,10 +5074,12 @@ func GetSheetIndex(idx int, sheet *C.char) C.struct_IntErrorResult {
 // dialog sheets name list of the workbook.
 //
 //export GetSheetList
-func GetSheetList(idx int) C.struct_StringArrayErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringArrayErrorResult{Err: C.CString(errFilePtr)}
+func GetSheetList(idx int) (res C.struct_StringArrayErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	result := f.(*excelize.File).GetSheetList()
 	cArray := C.malloc(C.size_t(len(result)) * C.size_t(unsafe.Sizeof(uintptr(0))))
@@ -1825,7 +5093,8 @@ func GetSheetList(idx int) C.struct_StringArrayErrorResult {
 // sheets ID and name map of the workbook.
 //
 //export GetSheetMap
-func GetSheetMap(idx int) C.struct_GetSheetMapResult {
+func GetSheetMap(idx int) (res C.struct_GetSheetMapResult) {
+	defer finalizeResult(&res)
 	type IntStringResult struct {
 		K int
 		V string
@@ -1835,10 +5104,11 @@ func GetSheetMap(idx int) C.struct_GetSheetMapResult {
 		Err string
 	}
 	var result GetSheetMapResult
-	f, ok := files.Load(idx)
-	if !ok {
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
 		return C.struct_GetSheetMapResult{
-			Err: C.CString(errFilePtr),
+			ErrCode: errorCode(err), Err: C.CString(err.Error()),
 		}
 	}
 	for k, v := range f.(*excelize.File).GetSheetMap() {
@@ -1847,7 +5117,7 @@ func GetSheetMap(idx int) C.struct_GetSheetMapResult {
 
 	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_GetSheetMapResult{}))
 	if err != nil {
-		return C.struct_GetSheetMapResult{Err: C.CString(err.Error())}
+		return C.struct_GetSheetMapResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	ret := cVal.Elem().Interface().(C.struct_GetSheetMapResult)
 	ret.Err = C.CString(emptyString)
@@ -1858,10 +5128,12 @@ func GetSheetMap(idx int) C.struct_GetSheetMapResult {
 // If the given worksheet index is invalid, it will return an error.
 //
 //export GetSheetName
-func GetSheetName(idx int, sheetIndex int) C.struct_StringErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringErrorResult{val: C.CString(emptyString), err: C.CString(errFilePtr)}
+func GetSheetName(idx int, sheetIndex int) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringErrorResult{val: C.CString(emptyString), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(f.(*excelize.File).GetSheetName(sheetIndex)), err: C.CString(emptyString)}
 }
@@ -1869,18 +5141,20 @@ func GetSheetName(idx int, sheetIndex int) C.struct_StringErrorResult {
 // GetSheetProps provides a function to get worksheet properties.
 //
 //export GetSheetProps
-func GetSheetProps(idx int, sheet *C.char) C.struct_GetSheetPropsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetSheetPropsResult{err: C.CString(errFilePtr)}
+func GetSheetProps(idx int, sheet *C.char) (res C.struct_GetSheetPropsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetSheetPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetSheetProps(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetSheetPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetSheetPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_SheetPropsOptions{}))
 	if err != nil {
-		return C.struct_GetSheetPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetSheetPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetSheetPropsResult{opts: cVal.Elem().Interface().(C.struct_SheetPropsOptions), err: C.CString(emptyString)}
 }
@@ -1890,18 +5164,20 @@ func GetSheetProps(idx int, sheet *C.char) C.struct_GetSheetPropsResult {
 // be empty.
 //
 //export GetSheetProtection
-func GetSheetProtection(idx int, sheet *C.char) C.struct_GetSheetProtectionResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetSheetProtectionResult{err: C.CString(errFilePtr)}
+func GetSheetProtection(idx int, sheet *C.char) (res C.struct_GetSheetProtectionResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetSheetProtectionResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetSheetProtection(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetSheetProtectionResult{err: C.CString(err.Error())}
+		return C.struct_GetSheetProtectionResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_SheetProtectionOptions{}))
 	if err != nil {
-		return C.struct_GetSheetProtectionResult{err: C.CString(err.Error())}
+		return C.struct_GetSheetProtectionResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetSheetProtectionResult{opts: cVal.Elem().Interface().(C.struct_SheetProtectionOptions), err: C.CString(emptyString)}
 }
@@ -1910,18 +5186,20 @@ func GetSheetProtection(idx int, sheet *C.char) C.struct_GetSheetProtectionResul
 // negative and if so is counted backward (-1 is the last view).
 //
 //export GetSheetView
-func GetSheetView(idx int, sheet *C.char, viewIndex int) C.struct_GetSheetViewResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetSheetViewResult{err: C.CString(errFilePtr)}
+func GetSheetView(idx int, sheet *C.char, viewIndex int) (res C.struct_GetSheetViewResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetSheetViewResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetSheetView(C.GoString(sheet), viewIndex)
 	if err != nil {
-		return C.struct_GetSheetViewResult{err: C.CString(err.Error())}
+		return C.struct_GetSheetViewResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_ViewOptions{}))
 	if err != nil {
-		return C.struct_GetSheetViewResult{err: C.CString(err.Error())}
+		return C.struct_GetSheetViewResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetSheetViewResult{opts: cVal.Elem().Interface().(C.struct_ViewOptions), err: C.CString(emptyString)}
 }
@@ -1930,14 +5208,16 @@ func GetSheetView(idx int, sheet *C.char, viewIndex int) C.struct_GetSheetViewRe
 // worksheet name.
 //
 //export GetSheetVisible
-func GetSheetVisible(idx int, sheet *C.char) C.struct_BoolErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_BoolErrorResult{val: C._Bool(false), err: C.CString(errFilePtr)}
+func GetSheetVisible(idx int, sheet *C.char) (res C.struct_BoolErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_BoolErrorResult{val: C._Bool(false), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	visible, err := f.(*excelize.File).GetSheetVisible(C.GoString(sheet))
 	if err != nil {
-		return C.struct_BoolErrorResult{val: C._Bool(visible), err: C.CString(err.Error())}
+		return C.struct_BoolErrorResult{val: C._Bool(visible), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_BoolErrorResult{val: C._Bool(visible), err: C.CString(emptyString)}
 }
@@ -1947,20 +5227,22 @@ func GetSheetVisible(idx int, sheet *C.char) C.struct_BoolErrorResult {
 // width, and graphic options of the slicer shape currently.
 //
 //export GetSlicers
-func GetSlicers(idx int, sheet *C.char) C.struct_GetSlicersResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetSlicersResult{Err: C.CString(errFilePtr)}
+func GetSlicers(idx int, sheet *C.char) (res C.struct_GetSlicersResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetSlicersResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	tables, err := f.(*excelize.File).GetSlicers(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetSlicersResult{Err: C.CString(err.Error())}
+		return C.struct_GetSlicersResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(tables)) * C.size_t(unsafe.Sizeof(C.struct_SlicerOptions{})))
 	for i, t := range tables {
 		cVal, err := goValueToC(reflect.ValueOf(t), reflect.ValueOf(&C.struct_SlicerOptions{}))
 		if err != nil {
-			return C.struct_GetSlicersResult{Err: C.CString(err.Error())}
+			return C.struct_GetSlicersResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_SlicerOptions)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_SlicerOptions{}))) = cVal.Elem().Interface().(C.struct_SlicerOptions)
 	}
@@ -1970,18 +5252,20 @@ func GetSlicers(idx int, sheet *C.char) C.struct_GetSlicersResult {
 // GetStyle provides a function to get style definition by given style index.
 //
 //export GetStyle
-func GetStyle(idx, styleID int) C.struct_GetStyleResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetStyleResult{err: C.CString(errFilePtr)}
+func GetStyle(idx, styleID int) (res C.struct_GetStyleResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetStyleResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	style, err := f.(*excelize.File).GetStyle(styleID)
 	if err != nil {
-		return C.struct_GetStyleResult{err: C.CString(err.Error())}
+		return C.struct_GetStyleResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(*style), reflect.ValueOf(&C.struct_Style{}))
 	if err != nil {
-		return C.struct_GetStyleResult{err: C.CString(err.Error())}
+		return C.struct_GetStyleResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetStyleResult{style: cVal.Elem().Interface().(C.struct_Style), err: C.CString(emptyString)}
 }
@@ -1990,41 +5274,247 @@ func GetStyle(idx, styleID int) C.struct_GetStyleResult {
 // worksheet name.
 //
 //export GetTables
-func GetTables(idx int, sheet *C.char) C.struct_GetTablesResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetTablesResult{Err: C.CString(errFilePtr)}
+func GetTables(idx int, sheet *C.char) (res C.struct_GetTablesResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetTablesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	tables, err := f.(*excelize.File).GetTables(C.GoString(sheet))
 	if err != nil {
-		return C.struct_GetTablesResult{Err: C.CString(err.Error())}
+		return C.struct_GetTablesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(tables)) * C.size_t(unsafe.Sizeof(C.struct_Table{})))
 	for i, t := range tables {
 		cVal, err := goValueToC(reflect.ValueOf(t), reflect.ValueOf(&C.struct_Table{}))
 		if err != nil {
-			return C.struct_GetTablesResult{Err: C.CString(err.Error())}
+			return C.struct_GetTablesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		*(*C.struct_Table)(unsafe.Pointer(uintptr(unsafe.Pointer(cArray)) + uintptr(i)*unsafe.Sizeof(C.struct_Table{}))) = cVal.Elem().Interface().(C.struct_Table)
 	}
 	return C.struct_GetTablesResult{TablesLen: C.int(len(tables)), Tables: (*C.struct_Table)(cArray), Err: C.CString(emptyString)}
 }
 
+// GetTableRows provides a function to get the data of a table by given table
+// name, the table will be looked up on all worksheets in the workbook. It
+// returns the name of the worksheet and the range reference of the table, the
+// header names of the table columns, and the typed cells of the data rows
+// without the header row and the totals row of the table.
+//
+//export GetTableRows
+func GetTableRows(idx int, name *C.char) (res C.struct_GetTableRowsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	file := f.(*excelize.File)
+	sheet, table, err := findTable(file, C.GoString(name))
+	if err != nil {
+		return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	t, err := getTablePart(file, sheet, table.Name)
+	if err != nil {
+		return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	var columns []string
+	if t.TableColumns != nil {
+		for _, column := range t.TableColumns.TableColumn {
+			columns = append(columns, column.Name)
+		}
+	}
+	coordinates, err := rangeRefToCoordinates(table.Range)
+	if err != nil {
+		return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	coordinates[1]++
+	if t.HeaderRowCount != nil {
+		coordinates[1] += *t.HeaderRowCount - 1
+	}
+	coordinates[3] -= t.TotalsRowCount
+	var formatted, raw [][]string
+	if coordinates[1] <= coordinates[3] {
+		if formatted, err = getRangeValues(file, sheet, coordinates, excelize.Options{}); err != nil {
+			return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+		if raw, err = getRangeValues(file, sheet, coordinates, excelize.Options{RawCellValue: true}); err != nil {
+			return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+	}
+	rows, err := typedCells(file, sheet, coordinates[0], coordinates[1], formatted, raw)
+	if err != nil {
+		return C.struct_GetTableRowsResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	header := unsafe.Slice((**C.char)(C.calloc(C.size_t(max(len(columns), 1)), C.size_t(unsafe.Sizeof((*C.char)(nil))))), len(columns))
+	for i, column := range columns {
+		header[i] = C.CString(column)
+	}
+	return C.struct_GetTableRowsResult{
+		Sheet:     C.CString(sheet),
+		Range:     C.CString(table.Range),
+		HeaderLen: C.int(len(header)),
+		Header:    unsafe.SliceData(header),
+		RowLen:    rows.RowLen,
+		Row:       rows.Row,
+		Err:       C.CString(emptyString),
+	}
+}
+
+// findTable returns the name of the worksheet and the table by given table
+// name, the table names are case-insensitive.
+func findTable(f *excelize.File, name string) (string, excelize.Table, error) {
+	for _, sheet := range f.GetSheetList() {
+		tables, err := f.GetTables(sheet)
+		if err != nil {
+			if errorCode(err) == C.ErrCodeSheetNotExist {
+				// The chart sheets, dialog sheets and macro sheets have no table
+				continue
+			}
+			return sheet, excelize.Table{}, err
+		}
+		for _, table := range tables {
+			if strings.EqualFold(table.Name, name) {
+				return sheet, table, nil
+			}
+		}
+	}
+	return emptyString, excelize.Table{}, tableNotExistError{name: name}
+}
+
+// GetTypedRows provides a function to get the typed cells in a worksheet by
+// given worksheet name and range reference, for example "C10:H5000", or a
+// defined name which refers to a range. The whole column and row references
+// are clamped to the used range of the worksheet. All the rows in the
+// worksheet will be returned if the range reference is empty. Each cell
+// carries the cell type, the typed value built from the raw value, the raw
+// value, the formatted value and the style ID.
+//
+//export GetTypedRows
+func GetTypedRows(idx int, sheet, rangeRef *C.char) (res C.struct_TypedCellMatrixErrorResult) {
+	defer finalizeResult(&res)
+	var (
+		formatted, raw [][]string
+		col, row       = 1, 1
+		name, ref      = C.GoString(sheet), C.GoString(rangeRef)
+		ret            C.struct_TypedCellMatrixErrorResult
+	)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	file := f.(*excelize.File)
+	if ref == "" {
+		if formatted, err = file.GetRows(name); err != nil {
+			return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+		if raw, err = file.GetRows(name, excelize.Options{RawCellValue: true}); err != nil {
+			return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+	} else {
+		var coordinates []int
+		if name, coordinates, err = resolveRangeRef(file, name, ref); err != nil {
+			return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+		col, row = coordinates[0], coordinates[1]
+		if formatted, err = getRangeValues(file, name, coordinates, excelize.Options{}); err != nil {
+			return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+		if raw, err = getRangeValues(file, name, coordinates, excelize.Options{RawCellValue: true}); err != nil {
+			return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+	}
+	if ret, err = typedCells(file, name, col, row, formatted, raw); err != nil {
+		return C.struct_TypedCellMatrixErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	ret.Err = C.CString(emptyString)
+	return ret
+}
+
+// typedCells returns the typed cells matrix by given formatted and raw values
+// of the cells in a worksheet, the top left cell of the matrix is at the given
+// column and row number. Each cell carries the cell type, the typed value
+// built from the raw value, the raw value, the formatted value and the style
+// ID.
+func typedCells(file *excelize.File, name string, col, row int, formatted, raw [][]string) (ret C.struct_TypedCellMatrixErrorResult, err error) {
+	cellAt := func(rows [][]string, r, c int) string {
+		if r < len(rows) && c < len(rows[r]) {
+			return rows[r][c]
+		}
+		return emptyString
+	}
+	reader, err := newTypedValueReader(file)
+	if err != nil {
+		return ret, err
+	}
+	rowCount := max(len(formatted), len(raw))
+	styles, err := newSheetStyles(file, name, row, max(row+rowCount-1, row))
+	if err != nil {
+		return ret, err
+	}
+	ret.Row = (*C.struct_TypedCells)(C.calloc(C.size_t(rowCount), C.size_t(unsafe.Sizeof(C.struct_TypedCells{}))))
+	ret.RowLen = C.int(rowCount)
+	rows := unsafe.Slice(ret.Row, rowCount)
+	for r := range rows {
+		var cellCount int
+		if r < len(formatted) {
+			cellCount = len(formatted[r])
+		}
+		if r < len(raw) {
+			cellCount = max(cellCount, len(raw[r]))
+		}
+		rows[r].Cell = (*C.struct_TypedCell)(C.calloc(C.size_t(cellCount), C.size_t(unsafe.Sizeof(C.struct_TypedCell{}))))
+		rows[r].CellLen = C.int(cellCount)
+		cells := unsafe.Slice(rows[r].Cell, cellCount)
+		for c := range cells {
+			cell, _ := excelize.CoordinatesToCellName(col+c, row+r)
+			cellType, err := file.GetCellType(name, cell)
+			if err != nil {
+				freeResult(&ret)
+				return ret, err
+			}
+			styleID, err := styles.style(col+c, row+r)
+			if err != nil {
+				freeResult(&ret)
+				return ret, err
+			}
+			rawVal := cellAt(raw, r, c)
+			val, err := reader.value(cellType, styleID, rawVal)
+			if err != nil {
+				freeResult(&ret)
+				return ret, err
+			}
+			cells[c] = C.struct_TypedCell{
+				CellType:  C.int(cellType),
+				Value:     val,
+				Raw:       C.CString(rawVal),
+				Formatted: C.CString(cellAt(formatted, r, c)),
+				StyleID:   C.int(styleID),
+			}
+		}
+	}
+	return ret, nil
+}
+
 // GetWorkbookProps provides a function to gets workbook properties.
 //
 //export GetWorkbookProps
-func GetWorkbookProps(idx int) C.struct_GetWorkbookPropsResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_GetWorkbookPropsResult{err: C.CString(errFilePtr)}
+func GetWorkbookProps(idx int) (res C.struct_GetWorkbookPropsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_GetWorkbookPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	opts, err := f.(*excelize.File).GetWorkbookProps()
 	if err != nil {
-		return C.struct_GetWorkbookPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetWorkbookPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_WorkbookPropsOptions{}))
 	if err != nil {
-		return C.struct_GetWorkbookPropsResult{err: C.CString(err.Error())}
+		return C.struct_GetWorkbookPropsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetWorkbookPropsResult{opts: cVal.Elem().Interface().(C.struct_WorkbookPropsOptions), err: C.CString(emptyString)}
 }
@@ -2033,34 +5523,38 @@ func GetWorkbookProps(idx int) C.struct_GetWorkbookPropsResult {
 // name. Group worksheets must contain an active worksheet.
 //
 //export GroupSheets
-func GroupSheets(idx int, sheets **C.char, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func GroupSheets(idx int, sheets **C.char, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	array := make([]string, length)
 	for i, val := range unsafe.Slice(sheets, length) {
 		array[i] = C.GoString(val)
 	}
 	if err := f.(*excelize.File).GroupSheets(array); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // InsertCols provides a function to insert new columns before the given column
 // name and number of columns.
 //
 //export InsertCols
-func InsertCols(idx int, sheet, col *C.char, n int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func InsertCols(idx int, sheet, col *C.char, n int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).InsertCols(C.GoString(sheet), C.GoString(col), n); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // InsertPageBreak create a page break to determine where the printed page
@@ -2069,57 +5563,93 @@ func InsertCols(idx int, sheet, col *C.char, n int) *C.char {
 // and after the page break on another.
 //
 //export InsertPageBreak
-func InsertPageBreak(idx int, sheet, cell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func InsertPageBreak(idx int, sheet, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).InsertPageBreak(C.GoString(sheet), C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // InsertRows provides a function to insert new rows after the given Excel row
 // number starting from 1 and number of rows.
 //
 //export InsertRows
-func InsertRows(idx int, sheet *C.char, row, n int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func InsertRows(idx int, sheet *C.char, row, n int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).InsertRows(C.GoString(sheet), row, n); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // JoinCellName joins cell name from column name and row number.
 //
 //export JoinCellName
-func JoinCellName(col *C.char, row int) C.struct_StringErrorResult {
+func JoinCellName(col *C.char, row int) (res C.struct_StringErrorResult) {
+	defer finalizeResult(&res)
 	result, err := excelize.JoinCellName(C.GoString(col), row)
 	if err != nil {
-		return C.struct_StringErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringErrorResult{val: C.CString(result), err: C.CString(emptyString)}
 }
 
+// ListHandles provides a function to get the live handles of the workbooks,
+// rows iterators, stream writers and cols iterators, which have not been
+// released by the 'Close', 'RowsClose', 'StreamFlush' or 'ColsClose' functions.
+// This is useful to diagnose the handles leaks.
+//
+//export ListHandles
+func ListHandles() (res C.struct_ListHandlesResult) {
+	defer finalizeResult(&res)
+	type ListHandlesResult struct {
+		Files         []int
+		Rows          []int
+		StreamWriters []int
+		Cols          []int
+	}
+	result := ListHandlesResult{
+		Files:         files.handles(),
+		Rows:          rowsIterator.handles(),
+		StreamWriters: sw.handles(),
+		Cols:          colsIterator.handles(),
+	}
+	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_ListHandlesResult{}))
+	if err != nil {
+		return C.struct_ListHandlesResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	ret := cVal.Elem().Interface().(C.struct_ListHandlesResult)
+	ret.Err = C.CString(emptyString)
+	return ret
+}
+
 // MergeCell provides a function to merge cells by given range reference and
 // sheet name. Merging cells only keeps the upper-left cell value, and
 // discards the other values.
 //
 //export MergeCell
-func MergeCell(idx int, sheet, topLeftCell, bottomRightCell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func MergeCell(idx int, sheet, topLeftCell, bottomRightCell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).MergeCell(C.GoString(sheet), C.GoString(topLeftCell), C.GoString(bottomRightCell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // MoveSheet moves a sheet to a specified position in the workbook. The function
@@ -2129,15 +5659,17 @@ func MergeCell(idx int, sheet, topLeftCell, bottomRightCell *C.char) *C.char {
 // will be ungroup all sheets after moving.
 //
 //export MoveSheet
-func MoveSheet(idx int, source, target *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func MoveSheet(idx int, source, target *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).MoveSheet(C.GoString(source), C.GoString(target)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // NewConditionalStyle provides a function to create style for conditional
@@ -2145,20 +5677,22 @@ func MoveSheet(idx int, source, target *C.char) *C.char {
 // function.
 //
 //export NewConditionalStyle
-func NewConditionalStyle(idx int, style *C.struct_Style) C.struct_IntErrorResult {
+func NewConditionalStyle(idx int, style *C.struct_Style) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
 	var s excelize.Style
 	goVal, err := cValueToGo(reflect.ValueOf(*style), reflect.TypeOf(excelize.Style{}))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	s = goVal.Elem().Interface().(excelize.Style)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	styleID, err := f.(*excelize.File).NewConditionalStyle(&s)
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(styleID), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(styleID), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(styleID), err: C.CString(emptyString)}
 }
@@ -2166,15 +5700,9 @@ func NewConditionalStyle(idx int, style *C.struct_Style) C.struct_IntErrorResult
 // NewFile provides a function to create new file by default template.
 //
 //export NewFile
-func NewFile() int {
-	f, idx := excelize.NewFile(), 0
-	files.Range(func(_, _ interface{}) bool {
-		idx++
-		return true
-	})
-	idx++
-	files.Store(idx, f)
-	return idx
+func NewFile() (res int) {
+	defer finalizeResult(&res)
+	return files.store(excelize.NewFile())
 }
 
 // NewSheet provides the function to create a new sheet by given a worksheet
@@ -2183,14 +5711,16 @@ func NewFile() int {
 // `Sheet1` will be created.
 //
 //export NewSheet
-func NewSheet(idx int, sheet *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(-1), err: C.CString(errFilePtr)}
+func NewSheet(idx int, sheet *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	idx, err := f.(*excelize.File).NewSheet(C.GoString(sheet))
+	idx, err = f.(*excelize.File).NewSheet(C.GoString(sheet))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(idx), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
 }
@@ -2206,22 +5736,45 @@ func NewSheet(idx int, sheet *C.char) C.struct_IntErrorResult {
 // you can't get cell value at this time.
 //
 //export NewStreamWriter
-func NewStreamWriter(idx int, sheet *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+func NewStreamWriter(idx int, sheet *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	return newStreamWriter(idx, C.GoString(sheet), false)
+}
+
+// NewStreamWriterAppend returns stream writer struct by given worksheet name
+// used for appending data after the last row of a worksheet which already
+// contains data. The existing rows, merged cells, column settings and tables
+// of the worksheet will be kept, and the rows should be set starting from the
+// row returned by the 'StreamNextRow' function. When flushing, the ranges of
+// the tables which end at the last row of the worksheet will be extended to
+// the last row set by the stream writer, except the tables with the totals
+// row. The rows and the other settings of the stream writer are written by the
+// functions of the workbook when they are set, such as the 'SetCellValue',
+// 'SetColWidth' and 'MergeCell' functions.
+//
+//export NewStreamWriterAppend
+func NewStreamWriterAppend(idx int, sheet *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	return newStreamWriter(idx, C.GoString(sheet), true)
+}
+
+// newStreamWriter creates the stream writer for the worksheet and returns the
+// handle of it, the existing rows of the worksheet will be kept in append
+// mode.
+func newStreamWriter(idx int, sheet string, appendMode bool) C.struct_IntErrorResult {
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	streamWriter, err := f.(*excelize.File).NewStreamWriter(C.GoString(sheet))
+	w, err := openStreamWriter(f.(*excelize.File), sheet, appendMode)
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	swIdx := sw.storeChild(w, idx)
+	if h, ok := files.callbacks.Load(idx); ok {
+		sw.callbacks.Store(swIdx, &progressHandle{callback: h.(*progressHandle).callback})
 	}
-	var swIdx int
-	sw.Range(func(_, _ interface{}) bool {
-		swIdx++
-		return true
-	})
-	swIdx++
-	sw.Store(swIdx, streamWriter)
 	return C.struct_IntErrorResult{val: C.int(swIdx), err: C.CString(emptyString)}
 }
 
@@ -2229,39 +5782,36 @@ func NewStreamWriter(idx int, sheet *C.char) C.struct_IntErrorResult {
 // with a large data. This function is concurrency safe.
 //
 //export Rows
-func Rows(idx int, sheet *C.char) C.struct_IntErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+func Rows(idx int, sheet *C.char) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	rows, err := f.(*excelize.File).Rows(C.GoString(sheet))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	var rIdx int
-	rowsIterator.Range(func(_, _ interface{}) bool {
-		rIdx++
-		return true
-	})
-	rIdx++
-	rowsIterator.Store(rIdx, rows)
-	return C.struct_IntErrorResult{val: C.int(rIdx), err: C.CString(emptyString)}
+	return C.struct_IntErrorResult{val: C.int(rowsIterator.storeChild(&rowsReader{Rows: rows}, idx)), err: C.CString(emptyString)}
 }
 
 // RowsClose closes the open worksheet XML file in the system temporary
 // directory.
 //
 //export RowsClose
-func RowsClose(rIdx int) *C.char {
-	row, ok := rowsIterator.Load(rIdx)
-	if !ok {
-		return C.CString(errRowsIteratorPtr)
+func RowsClose(rIdx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(rowsIterator.parent(rIdx))()
+	defer rowsIterator.lock(rIdx)()
+	row, err := rowsIterator.release(rIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	defer rowsIterator.Delete(rIdx)
-	if err := row.(*excelize.Rows).Close(); err != nil {
-		return C.CString(err.Error())
+	if err := row.(*rowsReader).Close(); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // RowsColumns return the current row's column values. This fetches the
@@ -2269,22 +5819,25 @@ func RowsClose(rIdx int) *C.char {
 // skip empty rows in the tail of the worksheet.
 //
 //export RowsColumns
-func RowsColumns(rIdx int, opts *C.struct_Options) C.struct_StringArrayErrorResult {
+func RowsColumns(rIdx int, opts *C.struct_Options) (res C.struct_StringArrayErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(rowsIterator.parent(rIdx))()
+	defer rowsIterator.lock(rIdx)()
 	var options excelize.Options
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_StringArrayErrorResult{Err: C.CString(err.Error())}
+			return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
 	}
-	row, ok := rowsIterator.Load(rIdx)
-	if !ok {
-		return C.struct_StringArrayErrorResult{Err: C.CString(errRowsIteratorPtr)}
+	row, err := rowsIterator.load(rIdx)
+	if err != nil {
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
-	result, err := row.(*excelize.Rows).Columns(options)
+	result, err := row.(*rowsReader).Columns(options)
 	if err != nil {
-		return C.struct_StringArrayErrorResult{Err: C.CString(err.Error())}
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(result)) * C.size_t(unsafe.Sizeof(uintptr(0))))
 	for i, v := range result {
@@ -2296,30 +5849,36 @@ func RowsColumns(rIdx int, opts *C.struct_Options) C.struct_StringArrayErrorResu
 // RowsError will return the error when the error occurs.
 //
 //export RowsError
-func RowsError(rIdx int) *C.char {
-	row, ok := rowsIterator.Load(rIdx)
-	if !ok {
-		return C.CString(errRowsIteratorPtr)
+func RowsError(rIdx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(rowsIterator.parent(rIdx))()
+	defer rowsIterator.lock(rIdx)()
+	row, err := rowsIterator.load(rIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	err := row.(*excelize.Rows).Error()
+	err = row.(*rowsReader).Error()
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // RowsGetRowOpts will return the RowOpts of the current row.
 //
 //export RowsGetRowOpts
-func RowsGetRowOpts(rIdx int) C.struct_GetRowOptsResult {
-	row, ok := rowsIterator.Load(rIdx)
-	if !ok {
-		return C.struct_GetRowOptsResult{err: C.CString(errRowsIteratorPtr)}
+func RowsGetRowOpts(rIdx int) (res C.struct_GetRowOptsResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(rowsIterator.parent(rIdx))()
+	defer rowsIterator.lock(rIdx)()
+	row, err := rowsIterator.load(rIdx)
+	if err != nil {
+		return C.struct_GetRowOptsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	opts := row.(*excelize.Rows).GetRowOpts()
+	opts := row.(*rowsReader).GetRowOpts()
 	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.ValueOf(&C.struct_RowOpts{}))
 	if err != nil {
-		return C.struct_GetRowOptsResult{err: C.CString(err.Error())}
+		return C.struct_GetRowOptsResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_GetRowOptsResult{opts: cVal.Elem().Interface().(C.struct_RowOpts), err: C.CString(emptyString)}
 }
@@ -2327,33 +5886,94 @@ func RowsGetRowOpts(rIdx int) C.struct_GetRowOptsResult {
 // RowsNext will return true if it finds the next row element.
 //
 //export RowsNext
-func RowsNext(rIdx int) C.struct_BoolErrorResult {
-	row, ok := rowsIterator.Load(rIdx)
-	if !ok {
-		return C.struct_BoolErrorResult{val: C._Bool(false), err: C.CString(errRowsIteratorPtr)}
+func RowsNext(rIdx int) (res C.struct_BoolErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(rowsIterator.parent(rIdx))()
+	defer rowsIterator.lock(rIdx)()
+	row, err := rowsIterator.load(rIdx)
+	if err != nil {
+		return C.struct_BoolErrorResult{val: C._Bool(false), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_BoolErrorResult{val: C._Bool(row.(*rowsReader).next()), err: C.CString(emptyString)}
+}
+
+// RowsNextBatch advances the rows iterator by up to the given number of rows,
+// and returns the column values, the row numbers and the RowOpts of these rows
+// at once. Fewer rows will be returned at the end of the worksheet.
+//
+//export RowsNextBatch
+func RowsNextBatch(rIdx, n int, opts *C.struct_Options) (res C.struct_StringMatrixErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(rowsIterator.parent(rIdx))()
+	defer rowsIterator.lock(rIdx)()
+	var (
+		options excelize.Options
+		result  StringMatrixErrorResult
+	)
+	if n < 1 {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(excelize.ErrParameterInvalid), err: C.CString(excelize.ErrParameterInvalid.Error())}
+	}
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		options = goVal.Elem().Interface().(excelize.Options)
+	}
+	row, err := rowsIterator.load(rIdx)
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	rows := row.(*rowsReader)
+	for len(result.Row) < n && rows.next() {
+		columns, err := rows.Columns(options)
+		if err != nil {
+			return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		result.Row = append(result.Row, Cells{Cell: columns})
+		result.RowNum = append(result.RowNum, rows.rowNum)
+		result.RowOpts = append(result.RowOpts, rows.GetRowOpts())
+	}
+	cVal, err := goValueToC(reflect.ValueOf(result), reflect.ValueOf(&C.struct_StringMatrixErrorResult{}))
+	if err != nil {
+		return C.struct_StringMatrixErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.struct_BoolErrorResult{val: C._Bool(row.(*excelize.Rows).Next()), err: C.CString(emptyString)}
+	ret := cVal.Elem().Interface().(C.struct_StringMatrixErrorResult)
+	ret.err = C.CString(emptyString)
+	return ret
 }
 
 // StreamAddTable creates an Excel table for the StreamWriter using the given
-// cell range and format set.
+// cell range and format set. The stream writer in append mode can't add table
+// on the worksheet which already contains tables.
 //
 //export StreamAddTable
-func StreamAddTable(swIdx int, table *C.struct_Table) *C.char {
+func StreamAddTable(swIdx int, table *C.struct_Table) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
 	var tbl excelize.Table
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*table), reflect.TypeOf(excelize.Table{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	tbl = goVal.Elem().Interface().(excelize.Table)
-	if err := streamWriter.(*excelize.StreamWriter).AddTable(&tbl); err != nil {
-		return C.CString(err.Error())
+	if appendMode := w.(*streamWriter).appendMode; appendMode != nil && appendMode.hasTables {
+		return C.struct_ErrorResult{ErrCode: errorCode(errStreamAppendTable), err: C.CString(errStreamAppendTable.Error())}
+	}
+	if err := w.(*streamWriter).call(func(streamWriter *excelize.StreamWriter) error {
+		table := tbl
+		return streamWriter.AddTable(&table)
+	}, func(f *excelize.File, sheet string) error {
+		table := tbl
+		return f.AddTable(sheet, &table)
+	}); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // StreamInsertPageBreak creates a page break to determine where the printed
@@ -2362,15 +5982,22 @@ func StreamAddTable(swIdx int, table *C.struct_Table) *C.char {
 // break on another.
 //
 //export StreamInsertPageBreak
-func StreamInsertPageBreak(swIdx int, cell *C.char) *C.char {
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+func StreamInsertPageBreak(swIdx int, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	if err := streamWriter.(*excelize.StreamWriter).InsertPageBreak(C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+	ref := C.GoString(cell)
+	if err := w.(*streamWriter).call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.InsertPageBreak(ref)
+	}, func(f *excelize.File, sheet string) error {
+		return f.InsertPageBreak(sheet, ref)
+	}); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // StreamMergeCell provides a function to merge cells by a given range reference
@@ -2378,15 +6005,37 @@ func StreamInsertPageBreak(swIdx int, cell *C.char) *C.char {
 // existing merged cell.
 //
 //export StreamMergeCell
-func StreamMergeCell(swIdx int, topLeftCell, bottomRightCell *C.char) *C.char {
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+func StreamMergeCell(swIdx int, topLeftCell, bottomRightCell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	if err := streamWriter.(*excelize.StreamWriter).MergeCell(C.GoString(topLeftCell), C.GoString(bottomRightCell)); err != nil {
-		return C.CString(err.Error())
+	topLeft, bottomRight := C.GoString(topLeftCell), C.GoString(bottomRightCell)
+	if err := w.(*streamWriter).call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.MergeCell(topLeft, bottomRight)
+	}, func(f *excelize.File, sheet string) error {
+		return f.MergeCell(sheet, topLeft, bottomRight)
+	}); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// StreamNextRow returns the number of the row after the last row set by the
+// stream writer. For the stream writer in append mode, it returns the row
+// after the last row of the worksheet if no row has been appended yet.
+//
+//export StreamNextRow
+func StreamNextRow(swIdx int) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_IntErrorResult{val: C.int(w.(*streamWriter).lastRow + 1), err: C.CString(emptyString)}
 }
 
 // SetColOutlineLevel provides a function to set outline level of a single
@@ -2394,15 +6043,25 @@ func StreamMergeCell(swIdx int, topLeftCell, bottomRightCell *C.char) *C.char {
 // you must call the 'SetColOutlineLevel' function before the 'SetRow' function.
 //
 //export StreamSetColOutlineLevel
-func StreamSetColOutlineLevel(swIdx int, col, level int) *C.char {
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+func StreamSetColOutlineLevel(swIdx int, col, level int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	if err := streamWriter.(*excelize.StreamWriter).SetColOutlineLevel(col, uint8(level)); err != nil {
-		return C.CString(err.Error())
+	if err := w.(*streamWriter).call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.SetColOutlineLevel(col, uint8(level))
+	}, func(f *excelize.File, sheet string) error {
+		name, err := excelize.ColumnNumberToName(col)
+		if err != nil {
+			return err
+		}
+		return f.SetColOutlineLevel(sheet, name, uint8(level))
+	}); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // StreamSetColWidth provides a function to set the width of a single column or
@@ -2410,15 +6069,29 @@ func StreamSetColOutlineLevel(swIdx int, col, level int) *C.char {
 // the 'StreamSetColWidth' function before the 'StreamSetRow' function.
 //
 //export StreamSetColWidth
-func StreamSetColWidth(swIdx int, minVal, maxVal int, width float64) *C.char {
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+func StreamSetColWidth(swIdx int, minVal, maxVal int, width float64) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	if err := streamWriter.(*excelize.StreamWriter).SetColWidth(minVal, maxVal, width); err != nil {
-		return C.CString(err.Error())
+	if err := w.(*streamWriter).call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.SetColWidth(minVal, maxVal, width)
+	}, func(f *excelize.File, sheet string) error {
+		startCol, err := excelize.ColumnNumberToName(minVal)
+		if err != nil {
+			return err
+		}
+		endCol, err := excelize.ColumnNumberToName(maxVal)
+		if err != nil {
+			return err
+		}
+		return f.SetColWidth(sheet, startCol, endCol, width)
+	}); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // StreamSetPanes provides a function to create and remove freeze panes and
@@ -2426,76 +6099,173 @@ func StreamSetColWidth(swIdx int, minVal, maxVal int, width float64) *C.char {
 // call the 'StreamSetPanes' function before the 'StreamSetRow' function.
 //
 //export StreamSetPanes
-func StreamSetPanes(swIdx int, opts *C.struct_Panes) *C.char {
+func StreamSetPanes(swIdx int, opts *C.struct_Panes) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
 	var options excelize.Panes
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Panes{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.Panes)
-	if err := streamWriter.(*excelize.StreamWriter).SetPanes(&options); err != nil {
-		return C.CString(err.Error())
+	if err := w.(*streamWriter).call(func(streamWriter *excelize.StreamWriter) error {
+		panes := options
+		return streamWriter.SetPanes(&panes)
+	}, func(f *excelize.File, sheet string) error {
+		panes := options
+		return f.SetPanes(sheet, &panes)
+	}); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // StreamSetRow writes an array to stream rows by giving starting cell reference
-// and a pointer to an array of values. Note that you must call the 'StreamFlush'
-// function to end the streaming writing process.
+// and a pointer to an array of values. The row options are optional, which
+// set the height, style, visibility and outline level of the row. Note that
+// you must call the 'StreamFlush' function to end the streaming writing
+// process.
 //
 //export StreamSetRow
-func StreamSetRow(swIdx int, cell *C.char, row *C.struct_Interface, length int) *C.char {
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
-	}
+func StreamSetRow(swIdx int, cell *C.char, row *C.struct_Interface, length int, opts *C.struct_RowOpts) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
 	cells := make([]interface{}, length)
 	for i, val := range unsafe.Slice(row, length) {
 		cells[i] = cInterfaceToGo(val)
 	}
-	if err := streamWriter.(*excelize.StreamWriter).SetRow(C.GoString(cell), cells); err != nil {
-		return C.CString(err.Error())
+	return streamSetRow(swIdx, cell, cells, opts)
+}
+
+// StreamSetRowCells writes an array of cells to stream rows by giving starting
+// cell reference and a pointer to an array of cells, each cell specifies the
+// style, the formula, and the value or the rich text runs of it. The row
+// options are optional. Note that you must call the 'StreamFlush' function to
+// end the streaming writing process.
+//
+//export StreamSetRowCells
+func StreamSetRowCells(swIdx int, cell *C.char, row *C.struct_Cell, length int, opts *C.struct_RowOpts) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(sw.parent(swIdx))()
+	cells := make([]interface{}, length)
+	for i, val := range unsafe.Slice(row, length) {
+		goVal, err := cCellToGo(val)
+		if err != nil {
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		cells[i] = goVal
+	}
+	return streamSetRow(swIdx, cell, cells, opts)
+}
+
+// streamSetRow writes the cells to stream rows by giving starting cell
+// reference and the optional row options.
+func streamSetRow(swIdx int, cell *C.char, cells []interface{}, opts *C.struct_RowOpts) C.struct_ErrorResult {
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	var options []excelize.RowOpts
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.RowOpts{}))
+		if err != nil {
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		options = append(options, goVal.Elem().Interface().(excelize.RowOpts))
+	}
+	if err := w.(*streamWriter).setRow(C.GoString(cell), cells, options); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	if h, ok := sw.callbacks.Load(swIdx); ok {
+		h.(*progressHandle).rows.Add(1)
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// StreamDiscard discards the stream writer without flushing it, and releases
+// the handle of it. The workbook keeps the stream writer of the worksheet until
+// it was closed, so the stream writer is replaced by an empty one which has
+// been flushed, and the worksheet will lose its rows, merged cells and tables,
+// as flushing the stream writer would. The column settings, panes and page
+// breaks set by the stream writer are kept, the table parts added by it are
+// left unused, and the temporary file which it created for the rows over 16
+// MiB can't be removed by the excelize library. In append mode, the appended
+// rows are removed, and the tables added by the stream writer are deleted if
+// the worksheet didn't contain tables, but the other settings set by the stream
+// writer are kept.
+//
+//export StreamDiscard
+func StreamDiscard(swIdx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	parent := sw.parent(swIdx)
+	defer files.lock(parent)()
+	w, err := sw.release(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	// The stream writer of the closed workbook only needs to be released
+	f, err := files.load(parent)
+	if err != nil {
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
+	}
+	if err := w.(*streamWriter).discard(f.(*excelize.File)); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
-// StreamFlush ending the streaming writing process.
+// StreamFlush ending the streaming writing process. The handle of the stream
+// writer will be released after it was flushed, if the flushing failed or was
+// canceled, the stream writer can be flushed again or discarded.
 //
 //export StreamFlush
-func StreamFlush(swIdx int) *C.char {
-	streamWriter, ok := sw.Load(swIdx)
-	if !ok {
-		return C.CString(errStreamWriterPtr)
+func StreamFlush(swIdx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	parent := sw.parent(swIdx)
+	defer files.lock(parent)()
+	w, err := sw.load(swIdx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	defer sw.Delete(swIdx)
-	if err := streamWriter.(*excelize.StreamWriter).Flush(); err != nil {
-		return C.CString(err.Error())
+	f, err := files.load(parent)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	progress := sw.progress(swIdx, C.ProgressFlush, w.(*streamWriter).sheet)
+	if err := w.(*streamWriter).flush(f.(*excelize.File), progress); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	if _, err = sw.release(swIdx); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	_ = progress.report(C.ProgressStepSerialize, 0, 0, true)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // NewStyle provides a function to create the style for cells by given options.
 // Note that the color field uses RGB color code.
 //
 //export NewStyle
-func NewStyle(idx int, style *C.struct_Style) C.struct_IntErrorResult {
+func NewStyle(idx int, style *C.struct_Style) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
 	var s excelize.Style
 	goVal, err := cValueToGo(reflect.ValueOf(*style), reflect.TypeOf(excelize.Style{}))
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	s = goVal.Elem().Interface().(excelize.Style)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_IntErrorResult{val: C.int(0), err: C.CString(errFilePtr)}
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(0), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	styleID, err := f.(*excelize.File).NewStyle(&s)
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(styleID), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(styleID), ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_IntErrorResult{val: C.int(styleID), err: C.CString(emptyString)}
 }
@@ -2504,26 +6274,52 @@ func NewStyle(idx int, style *C.struct_Style) C.struct_IntErrorResult {
 // spreadsheet file struct for it.
 //
 //export OpenFile
-func OpenFile(filename *C.char, opts *C.struct_Options) C.struct_IntErrorResult {
+func OpenFile(filename *C.char, opts *C.struct_Options) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.Options
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_IntErrorResult{val: C.int(-1), err: C.CString(err.Error())}
+			return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
 	}
 	f, err := excelize.OpenFile(C.GoString(filename), options)
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(-1), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	idx := files.store(f)
+	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
+}
+
+// OpenFileWithProgress take the name of a spreadsheet file and returns a
+// populated spreadsheet file struct for it, as the OpenFile function does. The
+// progress callback receives the number of bytes read and then the parse step
+// of the workbook, and will be registered for the workbook unless it's NULL.
+//
+//export OpenFileWithProgress
+func OpenFileWithProgress(filename *C.char, opts *C.struct_Options, callback C.ProgressCallback) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	var options excelize.Options
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		options = goVal.Elem().Interface().(excelize.Options)
+	}
+	var progress *progressReporter
+	if callback != nil {
+		progress = &progressReporter{handle: &progressHandle{callback: callback}, phase: C.ProgressOpen}
+	}
+	f, err := openFile(C.GoString(filename), progress, options)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	idx := files.store(f)
+	if progress != nil {
+		files.callbacks.Store(idx, progress.handle)
 	}
-	var idx int
-	files.Range(func(_, _ interface{}) bool {
-		idx++
-		return true
-	})
-	idx++
-	files.Store(idx, f)
 	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
 }
 
@@ -2531,27 +6327,55 @@ func OpenFile(filename *C.char, opts *C.struct_Options) C.struct_IntErrorResult
 // file.
 //
 //export OpenReader
-func OpenReader(b *C.uchar, bLen C.int, opts *C.struct_Options) C.struct_IntErrorResult {
+func OpenReader(b *C.uchar, bLen C.int, opts *C.struct_Options) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.Options
 	if opts != nil {
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.struct_IntErrorResult{val: C.int(-1), err: C.CString(err.Error())}
+			return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
 	}
 	buf := C.GoBytes(unsafe.Pointer(b), bLen)
 	f, err := excelize.OpenReader(bytes.NewReader(buf), options)
 	if err != nil {
-		return C.struct_IntErrorResult{val: C.int(-1), err: C.CString(err.Error())}
+		return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	idx := files.store(f)
+	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
+}
+
+// OpenReaderWithProgress read data stream from io.Reader and return a
+// populated spreadsheet file, as the OpenReader function does. The progress
+// callback receives the parse step of the workbook, and will be registered for
+// the workbook unless it's NULL. The read step is not reported, since the data
+// stream is already in memory.
+//
+//export OpenReaderWithProgress
+func OpenReaderWithProgress(b *C.uchar, bLen C.int, opts *C.struct_Options, callback C.ProgressCallback) (res C.struct_IntErrorResult) {
+	defer finalizeResult(&res)
+	var options excelize.Options
+	if opts != nil {
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		options = goVal.Elem().Interface().(excelize.Options)
+	}
+	var progress *progressReporter
+	if callback != nil {
+		progress = &progressReporter{handle: &progressHandle{callback: callback}, phase: C.ProgressOpen}
+	}
+	buf := C.GoBytes(unsafe.Pointer(b), bLen)
+	f, err := openBytes(buf, progress, options)
+	if err != nil {
+		return C.struct_IntErrorResult{val: C.int(-1), ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	idx := files.store(f)
+	if progress != nil {
+		files.callbacks.Store(idx, progress.handle)
 	}
-	var idx int
-	files.Range(func(_, _ interface{}) bool {
-		idx++
-		return true
-	})
-	idx++
-	files.Store(idx, f)
 	return C.struct_IntErrorResult{val: C.int(idx), err: C.CString(emptyString)}
 }
 
@@ -2562,21 +6386,23 @@ func OpenReader(b *C.uchar, bLen C.int, opts *C.struct_Options) C.struct_IntErro
 // specified, will be using the XOR algorithm as default.
 //
 //export ProtectSheet
-func ProtectSheet(idx int, sheet *C.char, opts *C.struct_SheetProtectionOptions) *C.char {
+func ProtectSheet(idx int, sheet *C.char, opts *C.struct_SheetProtectionOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.SheetProtectionOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.SheetProtectionOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.SheetProtectionOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).ProtectSheet(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // ProtectWorkbook provides a function to prevent other users from viewing
@@ -2588,119 +6414,151 @@ func ProtectSheet(idx int, sheet *C.char, opts *C.struct_SheetProtectionOptions)
 // 2007 and later.
 //
 //export ProtectWorkbook
-func ProtectWorkbook(idx int, opts *C.struct_WorkbookProtectionOptions) *C.char {
+func ProtectWorkbook(idx int, opts *C.struct_WorkbookProtectionOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.WorkbookProtectionOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.WorkbookProtectionOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.WorkbookProtectionOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).ProtectWorkbook(&options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // RemoveCol provides a function to remove single column by given worksheet
 // name and column index.
 //
 //export RemoveCol
-func RemoveCol(idx int, sheet, col *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func RemoveCol(idx int, sheet, col *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).RemoveCol(C.GoString(sheet), C.GoString(col)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // RemovePageBreak remove a page break by given worksheet name and cell
 // reference.
 //
 //export RemovePageBreak
-func RemovePageBreak(idx int, sheet, cell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func RemovePageBreak(idx int, sheet, cell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).RemovePageBreak(C.GoString(sheet), C.GoString(cell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // RemoveRow provides a function to remove single row by given worksheet name
 // and Excel row number.
 //
 //export RemoveRow
-func RemoveRow(idx int, sheet *C.char, row int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func RemoveRow(idx int, sheet *C.char, row int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).RemoveRow(C.GoString(sheet), row); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // Save provides a function to override the spreadsheet with origin path.
 //
 //export Save
-func Save(idx int, opts *C.struct_Options) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func Save(idx int, opts *C.struct_Options) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		var options excelize.Options
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
-		if err := f.(*excelize.File).Save(options); err != nil {
-			return C.CString(err.Error())
+		if progress := files.progress(idx, C.ProgressSave, emptyString); progress != nil {
+			err = save(f.(*excelize.File), progress, options)
+		} else {
+			err = f.(*excelize.File).Save(options)
 		}
-		return C.CString(emptyString)
+		if err != nil {
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
-	if err := f.(*excelize.File).Save(); err != nil {
-		return C.CString(err.Error())
+	if progress := files.progress(idx, C.ProgressSave, emptyString); progress != nil {
+		err = save(f.(*excelize.File), progress)
+	} else {
+		err = f.(*excelize.File).Save()
+	}
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SaveAs provides a function to create or update to a spreadsheet at the
 // provided path.
 //
 //export SaveAs
-func SaveAs(idx int, name *C.char, opts *C.struct_Options) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SaveAs(idx int, name *C.char, opts *C.struct_Options) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		var options excelize.Options
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.Options)
-		if err := f.(*excelize.File).SaveAs(C.GoString(name), options); err != nil {
-			return C.CString(err.Error())
+		if progress := files.progress(idx, C.ProgressSave, emptyString); progress != nil {
+			err = saveAs(f.(*excelize.File), C.GoString(name), progress, options)
+		} else {
+			err = f.(*excelize.File).SaveAs(C.GoString(name), options)
+		}
+		if err != nil {
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
-	if err := f.(*excelize.File).SaveAs(C.GoString(name)); err != nil {
-		return C.CString(err.Error())
+	if progress := files.progress(idx, C.ProgressSave, emptyString); progress != nil {
+		err = saveAs(f.(*excelize.File), C.GoString(name), progress)
+	} else {
+		err = f.(*excelize.File).SaveAs(C.GoString(name))
 	}
-	return C.CString(emptyString)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SearchSheet provides a function to get cell reference by given worksheet name,
@@ -2710,14 +6568,16 @@ func SaveAs(idx int, name *C.char, opts *C.struct_Options) *C.char {
 // upper left cell of the merged range reference.
 //
 //export SearchSheet
-func SearchSheet(idx int, sheet, value *C.char, reg bool) C.struct_StringArrayErrorResult {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.struct_StringArrayErrorResult{Err: C.CString(errFilePtr)}
+func SearchSheet(idx int, sheet, value *C.char, reg bool) (res C.struct_StringArrayErrorResult) {
+	defer finalizeResult(&res)
+	defer files.rlock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	result, err := f.(*excelize.File).SearchSheet(C.GoString(sheet), C.GoString(value), reg)
 	if err != nil {
-		return C.struct_StringArrayErrorResult{Err: C.CString(err.Error())}
+		return C.struct_StringArrayErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
 	cArray := C.malloc(C.size_t(len(result)) * C.size_t(unsafe.Sizeof(uintptr(0))))
 	for i, v := range result {
@@ -2732,32 +6592,36 @@ func SearchSheet(idx int, sheet, value *C.char, reg bool) C.struct_StringArrayEr
 // to 0 and less than the total worksheet numbers.
 //
 //export SetActiveSheet
-func SetActiveSheet(idx, index int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetActiveSheet(idx, index int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	f.(*excelize.File).SetActiveSheet(index)
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetAppProps provides a function to set document application properties.
 //
 //export SetAppProps
-func SetAppProps(idx int, opts *C.struct_AppProperties) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetAppProps(idx int, opts *C.struct_AppProperties) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.AppProperties{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	appProps := goVal.Elem().Interface().(excelize.AppProperties)
 	if err := f.(*excelize.File).SetAppProps(&appProps); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCalcProps provides a function to sets calculation properties. Optional
@@ -2765,50 +6629,56 @@ func SetAppProps(idx int, opts *C.struct_AppProperties) *C.char {
 // value of "RefMode" property is: "A1" or "R1C1".
 //
 //export SetCalcProps
-func SetCalcProps(idx int, opts *C.struct_CalcPropsOptions) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCalcProps(idx int, opts *C.struct_CalcPropsOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.CalcPropsOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	props := goVal.Elem().Interface().(excelize.CalcPropsOptions)
 	if err := f.(*excelize.File).SetCalcProps(&props); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellBool provides a function to set bool type value of a cell by given
 // worksheet name, cell reference and cell value.
 //
 //export SetCellBool
-func SetCellBool(idx int, sheet, cell *C.char, value bool) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellBool(idx int, sheet, cell *C.char, value bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellBool(C.GoString(sheet), C.GoString(cell), value); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellDefault provides a function to set string type value of a cell as
 // default format without escaping the cell.
 //
 //export SetCellDefault
-func SetCellDefault(idx int, sheet, cell, value *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellDefault(idx int, sheet, cell, value *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellDefault(C.GoString(sheet), C.GoString(cell), C.GoString(value)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellFloat sets a floating point value into a cell. The precision
@@ -2818,15 +6688,17 @@ func SetCellDefault(idx int, sheet, cell, value *C.char) *C.char {
 // float32 or float64 was originally used for the value.
 //
 //export SetCellFloat
-func SetCellFloat(idx int, sheet, cell *C.char, value float64, precision, bitSize int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellFloat(idx int, sheet, cell *C.char, value float64, precision, bitSize int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellFloat(C.GoString(sheet), C.GoString(cell), value, precision, bitSize); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellFormula provides a function to set formula on the cell is taken
@@ -2838,27 +6710,29 @@ func SetCellFloat(idx int, sheet, cell *C.char, value float64, precision, bitSiz
 // please call "UpdateLinkedValue" after setting the cell formula functions.
 //
 //export SetCellFormula
-func SetCellFormula(idx int, sheet, cell, formula *C.char, opts *C.struct_FormulaOpts) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellFormula(idx int, sheet, cell, formula *C.char, opts *C.struct_FormulaOpts) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		var options excelize.FormulaOpts
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.FormulaOpts{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.FormulaOpts)
 		if err := f.(*excelize.File).SetCellFormula(C.GoString(sheet), C.GoString(cell), C.GoString(formula), options); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).SetCellFormula(C.GoString(sheet), C.GoString(cell), C.GoString(formula)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellHyperLink provides a function to set cell hyperlink by given
@@ -2871,80 +6745,88 @@ func SetCellFormula(idx int, sheet, cell, formula *C.char, opts *C.struct_Formul
 // `SetSheetRow`.
 //
 //export SetCellHyperLink
-func SetCellHyperLink(idx int, sheet, cell, link, linkType *C.char, opts *C.struct_HyperlinkOpts) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellHyperLink(idx int, sheet, cell, link, linkType *C.char, opts *C.struct_HyperlinkOpts) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if opts != nil {
 		var options excelize.HyperlinkOpts
 		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.HyperlinkOpts{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options = goVal.Elem().Interface().(excelize.HyperlinkOpts)
 		if err := f.(*excelize.File).SetCellHyperLink(C.GoString(sheet), C.GoString(cell), C.GoString(link), C.GoString(linkType), options); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).SetCellHyperLink(C.GoString(sheet), C.GoString(cell), C.GoString(link), C.GoString(linkType)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellInt provides a function to set int type value of a cell by given
 // worksheet name, cell reference and cell value.
 //
 //export SetCellInt
-func SetCellInt(idx int, sheet, cell *C.char, value int64) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellInt(idx int, sheet, cell *C.char, value int64) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellInt(C.GoString(sheet), C.GoString(cell), value); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellRichText provides a function to set cell with rich text by given
 // worksheet name, cell reference and rich text runs.
 //
 //export SetCellRichText
-func SetCellRichText(idx int, sheet, cell *C.char, runs *C.struct_RichTextRun, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellRichText(idx int, sheet, cell *C.char, runs *C.struct_RichTextRun, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	textRuns := make([]excelize.RichTextRun, length)
 	for i, val := range unsafe.Slice(runs, length) {
 		goVal, err := cValueToGo(reflect.ValueOf(val), reflect.TypeOf(excelize.RichTextRun{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		textRuns[i] = goVal.Elem().Interface().(excelize.RichTextRun)
 	}
 	if err := f.(*excelize.File).SetCellRichText(C.GoString(sheet), C.GoString(cell), textRuns); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellStr provides a function to set string type value of a cell. Total
 // number of characters that a cell can contain 32767 characters.
 //
 //export SetCellStr
-func SetCellStr(idx int, sheet, cell, value *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellStr(idx int, sheet, cell, value *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellStr(C.GoString(sheet), C.GoString(cell), C.GoString(value)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellStyle provides a function to add style attribute for cells by given
@@ -2954,15 +6836,17 @@ func SetCellStr(idx int, sheet, cell, value *C.char) *C.char {
 // styles for the cell, it won't append or merge style with existing styles.
 //
 //export SetCellStyle
-func SetCellStyle(idx int, sheet, topLeftCell, bottomRightCell *C.char, styleID int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellStyle(idx int, sheet, topLeftCell, bottomRightCell *C.char, styleID int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellStyle(C.GoString(sheet), C.GoString(topLeftCell), C.GoString(bottomRightCell), styleID); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCellValue provides a function to set the value of a cell. The specified
@@ -2975,15 +6859,17 @@ func SetCellStyle(idx int, sheet, topLeftCell, bottomRightCell *C.char, styleID
 // date-time number format style for the cell.
 //
 //export SetCellValue
-func SetCellValue(idx int, sheet, cell *C.char, value *C.struct_Interface) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCellValue(idx int, sheet, cell *C.char, value *C.struct_Interface) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCellValue(C.GoString(sheet), C.GoString(cell), cInterfaceToGo(*value)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetColOutlineLevel provides a function to set outline level of a single
@@ -2991,15 +6877,17 @@ func SetCellValue(idx int, sheet, cell *C.char, value *C.struct_Interface) *C.ch
 // 'level' is 1-7.
 //
 //export SetColOutlineLevel
-func SetColOutlineLevel(idx int, sheet, col *C.char, level int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetColOutlineLevel(idx int, sheet, col *C.char, level int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetColOutlineLevel(C.GoString(sheet), C.GoString(col), uint8(level)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetColStyle provides a function to set style of columns by given worksheet
@@ -3008,45 +6896,51 @@ func SetColOutlineLevel(idx int, sheet, col *C.char, level int) *C.char {
 // append or merge style with existing styles.
 //
 //export SetColStyle
-func SetColStyle(idx int, sheet, columns *C.char, styleID int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetColStyle(idx int, sheet, columns *C.char, styleID int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetColStyle(C.GoString(sheet), C.GoString(columns), styleID); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetColVisible provides a function to set visible columns by given worksheet
 // name, columns range and visibility. This function is concurrency safe.
 //
 //export SetColVisible
-func SetColVisible(idx int, sheet, columns *C.char, visible bool) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetColVisible(idx int, sheet, columns *C.char, visible bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetColVisible(C.GoString(sheet), C.GoString(columns), visible); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetColWidth provides a function to set the width of a single column or
 // multiple columns. This function is concurrency safe.
 //
 //export SetColWidth
-func SetColWidth(idx int, sheet, startCol, endCol *C.char, width float64) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetColWidth(idx int, sheet, startCol, endCol *C.char, width float64) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetColWidth(C.GoString(sheet), C.GoString(startCol), C.GoString(endCol), width); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetConditionalFormat provides a function to create conditional formatting
@@ -3055,23 +6949,25 @@ func SetColWidth(idx int, sheet, startCol, endCol *C.char, width float64) *C.cha
 // criteria.
 //
 //export SetConditionalFormat
-func SetConditionalFormat(idx int, sheet, rangeRef *C.char, opts *C.struct_ConditionalFormatOptions, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetConditionalFormat(idx int, sheet, rangeRef *C.char, opts *C.struct_ConditionalFormatOptions, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := make([]excelize.ConditionalFormatOptions, length)
 	for i, val := range unsafe.Slice(opts, length) {
 		goVal, err := cValueToGo(reflect.ValueOf(val), reflect.TypeOf(excelize.ConditionalFormatOptions{}))
 		if err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
 		options[i] = goVal.Elem().Interface().(excelize.ConditionalFormatOptions)
 	}
 	if err := f.(*excelize.File).SetConditionalFormat(C.GoString(sheet), C.GoString(rangeRef), options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetCustomProps provides a function to set custom file properties by given
@@ -3082,155 +6978,253 @@ func SetConditionalFormat(idx int, sheet, rangeRef *C.char, opts *C.struct_Condi
 // not of the correct type.
 //
 //export SetCustomProps
-func SetCustomProps(idx int, prop C.struct_CustomProperty) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetCustomProps(idx int, prop C.struct_CustomProperty) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetCustomProps(excelize.CustomProperty{
 		Name: C.GoString(prop.Name), Value: cInterfaceToGo(prop.Value),
 	}); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetDefaultFont provides the default font name currently set in the
 // workbook. The spreadsheet generated by excelize default font is Calibri.
 //
 //export SetDefaultFont
-func SetDefaultFont(idx int, fontName *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetDefaultFont(idx int, fontName *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetDefaultFont(C.GoString(fontName)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetDefinedName provides a function to set the defined names of the workbook
 // or worksheet. If not specified scope, the default scope is workbook.
 //
 //export SetDefinedName
-func SetDefinedName(idx int, definedName *C.struct_DefinedName) *C.char {
+func SetDefinedName(idx int, definedName *C.struct_DefinedName) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var df excelize.DefinedName
 	goVal, err := cValueToGo(reflect.ValueOf(*definedName), reflect.TypeOf(excelize.DefinedName{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	df = goVal.Elem().Interface().(excelize.DefinedName)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetDefinedName(&df); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetDocProps provides a function to set document core properties.
 //
 //export SetDocProps
-func SetDocProps(idx int, docProperties *C.struct_DocProperties) *C.char {
+func SetDocProps(idx int, docProperties *C.struct_DocProperties) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.DocProperties
 	goVal, err := cValueToGo(reflect.ValueOf(*docProperties), reflect.TypeOf(excelize.DocProperties{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.DocProperties)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetDocProps(&options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetHeaderFooter provides a function to set headers and footers by given
 // worksheet name and the control characters.
 //
 //export SetHeaderFooter
-func SetHeaderFooter(idx int, sheet *C.char, opts *C.struct_HeaderFooterOptions) *C.char {
+func SetHeaderFooter(idx int, sheet *C.char, opts *C.struct_HeaderFooterOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.HeaderFooterOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.HeaderFooterOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.HeaderFooterOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetHeaderFooter(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// SetLocking provides a function to enable or disable the read/write lock of
+// the workbook. The lock is enabled by default, so a workbook can be used
+// from many threads: the functions that only read the workbook take the read
+// lock, and the functions that modify it take the write lock. Callers that
+// guarantee the workbook will be used from a single thread can disable it to
+// avoid the locking overhead.
+//
+//export SetLocking
+func SetLocking(idx int, enabled bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	if err := files.setLocking(idx, enabled); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetPageLayout provides a function to sets worksheet page layout.
 //
 //export SetPageLayout
-func SetPageLayout(idx int, sheet *C.char, opts *C.struct_PageLayoutOptions) *C.char {
+func SetPageLayout(idx int, sheet *C.char, opts *C.struct_PageLayoutOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.PageLayoutOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.PageLayoutOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.PageLayoutOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetPageLayout(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetPageMargins provides a function to set worksheet page margins.
 //
 //export SetPageMargins
-func SetPageMargins(idx int, sheet *C.char, opts *C.struct_PageLayoutMarginsOptions) *C.char {
+func SetPageMargins(idx int, sheet *C.char, opts *C.struct_PageLayoutMarginsOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.PageLayoutMarginsOptions
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.PageLayoutMarginsOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.PageLayoutMarginsOptions)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetPageMargins(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetPanes provides a function to create and remove freeze panes and split panes
 // by given worksheet name and panes options.
 //
 //export SetPanes
-func SetPanes(idx int, sheet *C.char, opts *C.struct_Panes) *C.char {
+func SetPanes(idx int, sheet *C.char, opts *C.struct_Panes) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
 	var options excelize.Panes
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Panes{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options = goVal.Elem().Interface().(excelize.Panes)
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetPanes(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// SetProgressCallback provides a function to register the progress callback
+// of the workbook or remove it by a NULL callback. The callback receives the
+// progress events of saving the workbook and calculating the cell values, and
+// the stream writers created after it has been registered report the flushing
+// progress to it. Returning a non-zero value from the callback cancels the
+// operation, and the function returns the cancellation error.
+//
+//export SetProgressCallback
+func SetProgressCallback(idx int, callback C.ProgressCallback) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	if err := files.setProgressCallback(idx, callback); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// SetRange provides a function to write a block of cells by given worksheet
+// name, starting cell reference and a pointer to the row-major matrix of the
+// values with the number of rows and columns. The style will be applied to the
+// block if the style ID is not negative. This function is concurrency safe.
+//
+//export SetRange
+func SetRange(idx int, sheet, cell *C.char, values *C.struct_Interface, rows, cols int, styleID C.int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	if rows < 0 || cols < 0 {
+		return C.struct_ErrorResult{ErrCode: errorCode(excelize.ErrParameterInvalid), err: C.CString(excelize.ErrParameterInvalid.Error())}
+	}
+	if rows == 0 || cols == 0 {
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
+	}
+	col, row, err := excelize.CellNameToCoordinates(C.GoString(cell))
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	matrix := unsafe.Slice(values, rows*cols)
+	for r := 0; r < rows; r++ {
+		cells := make([]interface{}, cols)
+		for c, val := range matrix[r*cols : (r+1)*cols] {
+			cells[c] = cInterfaceToGo(val)
+		}
+		startCell, err := excelize.CoordinatesToCellName(col, row+r)
+		if err != nil {
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+		if err := f.(*excelize.File).SetSheetRow(C.GoString(sheet), startCell, &cells); err != nil {
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+		}
+	}
+	if styleID < 0 {
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
-	return C.CString(emptyString)
+	bottomRightCell, err := excelize.CoordinatesToCellName(col+cols-1, row+rows-1)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	if err := f.(*excelize.File).SetCellStyle(C.GoString(sheet), C.GoString(cell), bottomRightCell, int(styleID)); err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetRowHeight provides a function to set the height of a single row. If the
@@ -3238,15 +7232,17 @@ func SetPanes(idx int, sheet *C.char, opts *C.struct_Panes) *C.char {
 // -1, will unset the custom row height.
 //
 //export SetRowHeight
-func SetRowHeight(idx int, sheet *C.char, row int, height float64) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetRowHeight(idx int, sheet *C.char, row int, height float64) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetRowHeight(C.GoString(sheet), row, height); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetRowOutlineLevel provides a function to set outline level number of a
@@ -3254,15 +7250,17 @@ func SetRowHeight(idx int, sheet *C.char, row int, height float64) *C.char {
 // parameter 'level' is 1-7.
 //
 //export SetRowOutlineLevel
-func SetRowOutlineLevel(idx int, sheet *C.char, row, level int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetRowOutlineLevel(idx int, sheet *C.char, row, level int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetRowOutlineLevel(C.GoString(sheet), row, uint8(level)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetRowStyle provides a function to set the style of rows by given worksheet
@@ -3270,30 +7268,34 @@ func SetRowOutlineLevel(idx int, sheet *C.char, row, level int) *C.char {
 // styles for the rows, it won't append or merge style with existing styles.
 //
 //export SetRowStyle
-func SetRowStyle(idx int, sheet *C.char, start, end, styleID int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetRowStyle(idx int, sheet *C.char, start, end, styleID int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetRowStyle(C.GoString(sheet), start, end, styleID); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetRowVisible provides a function to set visible of a single row by given
 // worksheet name and Excel row number.
 //
 //export SetRowVisible
-func SetRowVisible(idx int, sheet *C.char, row int, visible bool) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetRowVisible(idx int, sheet *C.char, row int, visible bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetRowVisible(C.GoString(sheet), row, visible); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetBackground provides a function to set background picture by given
@@ -3301,15 +7303,17 @@ func SetRowVisible(idx int, sheet *C.char, row int, visible bool) *C.char {
 // ICO, JPEG, JPG, PNG, SVG, TIF, TIFF, WMF and WMZ.
 //
 //export SetSheetBackground
-func SetSheetBackground(idx int, sheet, picture *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetBackground(idx int, sheet, picture *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetSheetBackground(C.GoString(sheet), C.GoString(picture)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetBackgroundFromBytes provides a function to set background picture by
@@ -3317,35 +7321,39 @@ func SetSheetBackground(idx int, sheet, picture *C.char) *C.char {
 // BMP, EMF, EMZ, GIF, ICO, JPEG, JPG, PNG, SVG, TIF, TIFF, WMF and WMZ.
 //
 //export SetSheetBackgroundFromBytes
-func SetSheetBackgroundFromBytes(idx int, sheet, extension *C.char, picture *C.uchar, pictureLen C.int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetBackgroundFromBytes(idx int, sheet, extension *C.char, picture *C.uchar, pictureLen C.int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	buf := C.GoBytes(unsafe.Pointer(picture), pictureLen)
 	if err := f.(*excelize.File).SetSheetBackgroundFromBytes(C.GoString(sheet), C.GoString(extension), buf); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetCol writes an array to column by given worksheet name, starting
 // cell reference and a pointer to array type 'slice'.
 //
 //export SetSheetCol
-func SetSheetCol(idx int, sheet, cell *C.char, slice *C.struct_Interface, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetCol(idx int, sheet, cell *C.char, slice *C.struct_Interface, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cells := make([]interface{}, length)
 	for i, val := range unsafe.Slice(slice, length) {
 		cells[i] = cInterfaceToGo(val)
 	}
 	if err := f.(*excelize.File).SetSheetCol(C.GoString(sheet), C.GoString(cell), &cells); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetDimension provides the method to set or remove the used range of the
@@ -3355,15 +7363,17 @@ func SetSheetCol(idx int, sheet, cell *C.char, slice *C.struct_Interface, length
 // the used range of the worksheet.
 //
 //export SetSheetDimension
-func SetSheetDimension(idx int, sheet, rangeRef *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetDimension(idx int, sheet, rangeRef *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetSheetDimension(C.GoString(sheet), C.GoString(rangeRef)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetName provides a function to set the worksheet name by given source and
@@ -3373,34 +7383,38 @@ func SetSheetDimension(idx int, sheet, rangeRef *C.char) *C.char {
 // may be problem formula error or reference missing.
 //
 //export SetSheetName
-func SetSheetName(idx int, source, target *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetName(idx int, source, target *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetSheetName(C.GoString(source), C.GoString(target)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetProps provides a function to set worksheet properties.
 //
 //export SetSheetProps
-func SetSheetProps(idx int, sheet *C.char, opts *C.struct_SheetPropsOptions) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetProps(idx int, sheet *C.char, opts *C.struct_SheetPropsOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.SheetPropsOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := goVal.Elem().Interface().(excelize.SheetPropsOptions)
 	if err := f.(*excelize.File).SetSheetProps(C.GoString(sheet), &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetRow writes an array to row by given worksheet name, starting
@@ -3408,39 +7422,43 @@ func SetSheetProps(idx int, sheet *C.char, opts *C.struct_SheetPropsOptions) *C.
 // concurrency safe.
 //
 //export SetSheetRow
-func SetSheetRow(idx int, sheet, cell *C.char, row *C.struct_Interface, length int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetRow(idx int, sheet, cell *C.char, row *C.struct_Interface, length int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	cells := make([]interface{}, length)
 	for i, val := range unsafe.Slice(row, length) {
 		cells[i] = cInterfaceToGo(val)
 	}
 	if err := f.(*excelize.File).SetSheetRow(C.GoString(sheet), C.GoString(cell), &cells); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetView sets sheet view options. The viewIndex may be negative and if
 // so is counted backward (-1 is the last view).
 //
 //export SetSheetView
-func SetSheetView(idx int, sheet *C.char, viewIndex int, opts *C.struct_ViewOptions) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetView(idx int, sheet *C.char, viewIndex int, opts *C.struct_ViewOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.ViewOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := goVal.Elem().Interface().(excelize.ViewOptions)
 	if err := f.(*excelize.File).SetSheetView(C.GoString(sheet), viewIndex, &options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetSheetVisible provides a function to set worksheet visible by given
@@ -3449,43 +7467,48 @@ func SetSheetView(idx int, sheet *C.char, viewIndex int, opts *C.struct_ViewOpti
 // The third optional veryHidden parameter only works when visible was false.
 //
 //export SetSheetVisible
-func SetSheetVisible(idx int, sheet *C.char, visible, veryHidden bool) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetSheetVisible(idx int, sheet *C.char, visible, veryHidden bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).SetSheetVisible(C.GoString(sheet), visible, veryHidden); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SetWorkbookProps provides a function to sets workbook properties.
 //
 //export SetWorkbookProps
-func SetWorkbookProps(idx int, opts *C.struct_WorkbookPropsOptions) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func SetWorkbookProps(idx int, opts *C.struct_WorkbookPropsOptions) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.WorkbookPropsOptions{}))
 	if err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	options := goVal.Elem().Interface().(excelize.WorkbookPropsOptions)
 	if err := f.(*excelize.File).SetWorkbookProps(&options); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // SplitCellName splits cell name to column name and row number.
 //
 //export SplitCellName
-func SplitCellName(cell *C.char, row int) C.struct_StringIntErrorResult {
+func SplitCellName(cell *C.char, row int) (res C.struct_StringIntErrorResult) {
+	defer finalizeResult(&res)
 	col, row, err := excelize.SplitCellName(C.GoString(cell))
 	if err != nil {
-		return C.struct_StringIntErrorResult{err: C.CString(err.Error())}
+		return C.struct_StringIntErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	return C.struct_StringIntErrorResult{intVal: C.int(row), strVal: C.CString(col), err: C.CString(emptyString)}
 }
@@ -3493,29 +7516,33 @@ func SplitCellName(cell *C.char, row int) C.struct_StringIntErrorResult {
 // UngroupSheets provides a function to ungroup worksheets.
 //
 //export UngroupSheets
-func UngroupSheets(idx int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func UngroupSheets(idx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).UngroupSheets(); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // UnmergeCell provides a function to unmerge a given range reference.
 //
 //export UnmergeCell
-func UnmergeCell(idx int, sheet, topLeftCell, bottomRightCell *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func UnmergeCell(idx int, sheet, topLeftCell, bottomRightCell *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).UnmergeCell(C.GoString(sheet), C.GoString(topLeftCell), C.GoString(bottomRightCell)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // UnprotectSheet provides a function to remove protection for a sheet,
@@ -3523,21 +7550,23 @@ func UnmergeCell(idx int, sheet, topLeftCell, bottomRightCell *C.char) *C.char {
 // protection with password verification.
 //
 //export UnprotectSheet
-func UnprotectSheet(idx int, sheet, password *C.char, verify bool) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func UnprotectSheet(idx int, sheet, password *C.char, verify bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if verify {
 		if err := f.(*excelize.File).UnprotectSheet(C.GoString(sheet), C.GoString(password)); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).UnprotectSheet(C.GoString(sheet)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // UnprotectWorkbook provides a function to remove protection for workbook,
@@ -3545,36 +7574,40 @@ func UnprotectSheet(idx int, sheet, password *C.char, verify bool) *C.char {
 // password verification.
 //
 //export UnprotectWorkbook
-func UnprotectWorkbook(idx int, password *C.char, verify bool) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func UnprotectWorkbook(idx int, password *C.char, verify bool) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if verify {
 		if err := f.(*excelize.File).UnprotectWorkbook(C.GoString(password)); err != nil {
-			return C.CString(err.Error())
+			return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 		}
-		return C.CString(emptyString)
+		return C.struct_ErrorResult{err: C.CString(emptyString)}
 	}
 	if err := f.(*excelize.File).UnprotectWorkbook(); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // UnsetConditionalFormat provides a function to unset the conditional format
 // by given worksheet name and range reference.
 //
 //export UnsetConditionalFormat
-func UnsetConditionalFormat(idx int, sheet, rangeRef *C.char) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func UnsetConditionalFormat(idx int, sheet, rangeRef *C.char) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).UnsetConditionalFormat(C.GoString(sheet), C.GoString(rangeRef)); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
 }
 
 // UpdateLinkedValue fix linked values within a spreadsheet are not updating in
@@ -3582,15 +7615,46 @@ func UnsetConditionalFormat(idx int, sheet, rangeRef *C.char) *C.char {
 // cell have a linked value.
 //
 //export UpdateLinkedValue
-func UpdateLinkedValue(idx int) *C.char {
-	f, ok := files.Load(idx)
-	if !ok {
-		return C.CString(errFilePtr)
+func UpdateLinkedValue(idx int) (res C.struct_ErrorResult) {
+	defer finalizeResult(&res)
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
 	}
 	if err := f.(*excelize.File).UpdateLinkedValue(); err != nil {
-		return C.CString(err.Error())
+		return C.struct_ErrorResult{ErrCode: errorCode(err), err: C.CString(err.Error())}
+	}
+	return C.struct_ErrorResult{err: C.CString(emptyString)}
+}
+
+// WriteToBuffer provides a function to get the serialized workbook bytes in
+// memory without saving it to disk, it allocates space in memory. Be careful
+// when the file size is large.
+//
+//export WriteToBuffer
+func WriteToBuffer(idx int, opts *C.struct_Options) (res C.struct_BytesErrorResult) {
+	defer finalizeResult(&res)
+	var buf bytes.Buffer
+	defer files.lock(idx)()
+	f, err := files.load(idx)
+	if err != nil {
+		return C.struct_BytesErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+	}
+	if opts != nil {
+		var options excelize.Options
+		goVal, err := cValueToGo(reflect.ValueOf(*opts), reflect.TypeOf(excelize.Options{}))
+		if err != nil {
+			return C.struct_BytesErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+		options = goVal.Elem().Interface().(excelize.Options)
+		if _, err := f.(*excelize.File).WriteTo(&buf, options); err != nil {
+			return C.struct_BytesErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
+		}
+	} else if _, err := f.(*excelize.File).WriteTo(&buf); err != nil {
+		return C.struct_BytesErrorResult{ErrCode: errorCode(err), Err: C.CString(err.Error())}
 	}
-	return C.CString(emptyString)
+	return C.struct_BytesErrorResult{ArrLen: C.int(buf.Len()), Arr: (*C.uchar)(C.CBytes(buf.Bytes())), Err: C.CString(emptyString)}
 }
 
 func main() {
@@ -0,0 +1,765 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// converterTestCases defines the Go values converted to the C structures of
+// the parameters of the exported functions in the converter tests and
+// benchmarks.
+var converterTestCases = []struct {
+	name  string
+	cType reflect.Type
+	goVal interface{}
+}{
+	{
+		name:  "Style",
+		cType: reflect.TypeOf(NewStyle).In(1).Elem(),
+		goVal: excelize.Style{
+			Border: []excelize.Border{
+				{Type: "left", Color: "0000FF", Style: 3},
+				{Type: "top", Color: "00FF00", Style: 4},
+			},
+			Fill:          excelize.Fill{Type: "pattern", Color: []string{"E0EBF5"}, Pattern: 1},
+			Font:          &excelize.Font{Bold: true, Family: "Times New Roman", Size: 36, Color: "777777"},
+			Alignment:     &excelize.Alignment{Horizontal: "center", Indent: 1, WrapText: true},
+			Protection:    &excelize.Protection{Hidden: true, Locked: true},
+			NumFmt:        22,
+			DecimalPlaces: func() *int { v := 2; return &v }(),
+			CustomNumFmt:  func() *string { v := "0.00%"; return &v }(),
+		},
+	},
+	{
+		name:  "Comment",
+		cType: reflect.TypeOf(AddComment).In(2).Elem(),
+		goVal: excelize.Comment{
+			Cell:   "A3",
+			Author: "Excelize",
+			Paragraph: []excelize.RichTextRun{
+				{Text: "Excelize: ", Font: &excelize.Font{Bold: true}},
+				{Text: "This is a comment."},
+			},
+			Height: 40,
+			Width:  180,
+		},
+	},
+	{
+		name:  "ConditionalFormatOptions",
+		cType: reflect.TypeOf(SetConditionalFormat).In(3).Elem(),
+		goVal: excelize.ConditionalFormatOptions{
+			Type:     "cell",
+			Criteria: "between",
+			MinValue: "6",
+			MaxValue: "8",
+			Format:   func() *int { v := 1; return &v }(),
+		},
+	},
+}
+
+// TestConverter checks the Go values converted to the C structures and back
+// are equal to the original values, and the cached converters return the same
+// results for the repeated conversions.
+func TestConverter(t *testing.T) {
+	for _, tc := range converterTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				cVal, err := goValueToC(reflect.ValueOf(tc.goVal), reflect.New(tc.cType))
+				if err != nil {
+					t.Fatal(err)
+				}
+				goVal, err := cValueToGo(cVal.Elem(), reflect.TypeOf(tc.goVal))
+				freeCValue(cVal.Elem())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(goVal.Elem().Interface(), tc.goVal) {
+					t.Fatalf("expected %+v, got %+v", tc.goVal, goVal.Elem().Interface())
+				}
+			}
+		})
+	}
+}
+
+// TestConverterByName checks the C structures converted by the cached
+// converters hold the same values as the fields looked up by name, which the
+// converters did before the field indexes were cached.
+func TestConverterByName(t *testing.T) {
+	for _, tc := range converterTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cVal, err := goValueToC(reflect.ValueOf(tc.goVal), reflect.New(tc.cType))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer freeCValue(cVal.Elem())
+			assertConvertedByName(t, reflect.ValueOf(tc.goVal), cVal.Elem(), tc.name)
+		})
+	}
+}
+
+// TestConvertNilStructPointer checks the C pointers of the nil Go structure
+// pointers are set to nil, even if the C structure was not initialized.
+func TestConvertNilStructPointer(t *testing.T) {
+	paragraph, _ := reflect.TypeOf(AddComment).In(2).Elem().FieldByName("Paragraph")
+	cType := paragraph.Type.Elem()
+	cVal := reflect.New(cType)
+	garbage := unsafe.Slice((*byte)(cVal.UnsafePointer()), cType.Size())
+	for i := range garbage {
+		garbage[i] = 0xFF
+	}
+	if _, err := goValueToC(reflect.ValueOf(excelize.RichTextRun{Text: "text"}), cVal); err != nil {
+		t.Fatal(err)
+	}
+	defer freeCValue(cVal.Elem())
+	if font := cVal.Elem().FieldByName("Font"); !font.IsNil() {
+		t.Fatalf("expected nil font pointer, got %v", font.UnsafePointer())
+	}
+}
+
+// TestConvertBasePointer checks the pointers of the Go basic data types of
+// different sizes are converted to the C values and back.
+func TestConvertBasePointer(t *testing.T) {
+	calcID, iterateCount, iterateDelta, iterate := uint(math.MaxUint32), uint(100), math.Pi, true
+	calcMode := "manual"
+	goVal := excelize.CalcPropsOptions{
+		CalcID:       &calcID,
+		CalcMode:     &calcMode,
+		Iterate:      &iterate,
+		IterateCount: &iterateCount,
+		IterateDelta: &iterateDelta,
+	}
+	cVal, err := goValueToC(reflect.ValueOf(goVal), reflect.New(reflect.TypeOf(SetCalcProps).In(1).Elem()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer freeCValue(cVal.Elem())
+	assertConvertedByName(t, reflect.ValueOf(goVal), cVal.Elem(), "CalcPropsOptions")
+	result, err := cValueToGo(cVal.Elem(), reflect.TypeOf(goVal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Elem().Interface(), goVal) {
+		t.Fatalf("expected %+v, got %+v", goVal, result.Elem().Interface())
+	}
+}
+
+// assertConvertedByName checks the fields of the C structure looked up by the
+// names of the exported fields of the Go structure hold the converted values.
+func assertConvertedByName(t *testing.T, goVal, cVal reflect.Value, path string) {
+	t.Helper()
+	for i := 0; i < goVal.NumField(); i++ {
+		field := goVal.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := path + "." + field.Name
+		cField := cVal.FieldByName(field.Name)
+		if !cField.IsValid() {
+			t.Errorf("%s: missing field in the C structure", name)
+			continue
+		}
+		assertConvertedValue(t, goVal.Field(i), cField, cVal.FieldByName(field.Name+"Len"), name)
+	}
+}
+
+// assertConvertedValue checks the C value holds the converted Go value, the
+// length value is the length field of the C array.
+func assertConvertedValue(t *testing.T, goVal, cVal, cLen reflect.Value, name string) {
+	t.Helper()
+	switch goVal.Kind() {
+	case reflect.Ptr:
+		if goVal.IsNil() != cVal.IsNil() {
+			t.Errorf("%s: expected nil %t, got nil %t", name, goVal.IsNil(), cVal.IsNil())
+			return
+		}
+		if !goVal.IsNil() {
+			assertConvertedValue(t, goVal.Elem(), cVal.Elem(), reflect.Value{}, name)
+		}
+	case reflect.Struct:
+		assertConvertedByName(t, goVal, cVal, name)
+	case reflect.Slice:
+		if !cLen.IsValid() || int(cLen.Int()) != goVal.Len() {
+			t.Errorf("%s: unexpected length of the C array", name)
+			return
+		}
+		if goVal.Len() == 0 {
+			return
+		}
+		cArray := reflect.NewAt(reflect.ArrayOf(goVal.Len(), cVal.Type().Elem()), cVal.UnsafePointer()).Elem()
+		for i := 0; i < goVal.Len(); i++ {
+			assertConvertedValue(t, goVal.Index(i), cArray.Index(i), reflect.Value{}, fmt.Sprintf("%s[%d]", name, i))
+		}
+	case reflect.String:
+		var n int
+		for *(*byte)(unsafe.Add(cVal.UnsafePointer(), n)) != 0 {
+			n++
+		}
+		if got := string(unsafe.Slice((*byte)(cVal.UnsafePointer()), n)); got != goVal.String() {
+			t.Errorf("%s: expected %q, got %q", name, goVal.String(), got)
+		}
+	default:
+		if got := cVal.Convert(goVal.Type()).Interface(); got != goVal.Interface() {
+			t.Errorf("%s: expected %v, got %v", name, goVal.Interface(), got)
+		}
+	}
+}
+
+// TestCheckConverter checks the fields which can not be converted between the
+// Go and C structures are reported.
+func TestCheckConverter(t *testing.T) {
+	cType := reflect.TypeOf(AddComment).In(2).Elem()
+	if problems := checkConverter(reflect.TypeOf(excelize.Comment{}), cType, "Comment", map[converterKey]bool{}); len(problems) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	type comment struct {
+		Author    string
+		Paragraph []excelize.RichTextRun
+		Value     interface{}
+		Unknown   string
+	}
+	expected := []string{
+		"comment.Value: missing field in the C structure",
+		"comment.Unknown: missing field in the C structure",
+	}
+	problems := checkConverter(reflect.TypeOf(comment{}), cType, "comment", map[converterKey]bool{})
+	if !reflect.DeepEqual(problems, expected) {
+		t.Fatalf("expected %v, got %v", expected, problems)
+	}
+}
+
+// TestConvertNumber checks the numeric values are converted between the Go and
+// C types, and the overflows are reported as errors instead of truncating.
+func TestConvertNumber(t *testing.T) {
+	for _, tc := range []struct {
+		val      interface{}
+		typ      reflect.Type
+		expected interface{}
+		err      string
+	}{
+		{val: int64(-128), typ: reflect.TypeOf(int8(0)), expected: int8(-128)},
+		{val: int64(128), typ: reflect.TypeOf(int8(0)), err: "value 128 overflows int8"},
+		{val: int(65535), typ: reflect.TypeOf(uint16(0)), expected: uint16(65535)},
+		{val: int(-1), typ: reflect.TypeOf(uint16(0)), err: "value -1 overflows uint16"},
+		{val: uint64(math.MaxUint64), typ: reflect.TypeOf(int64(0)), err: "value 18446744073709551615 overflows int64"},
+		{val: uint64(math.MaxUint32), typ: reflect.TypeOf(uint32(0)), expected: uint32(math.MaxUint32)},
+		{val: uint(math.MaxUint32 + 1), typ: reflect.TypeOf(uint32(0)), err: "value 4294967296 overflows uint32"},
+		{val: float64(1.5), typ: reflect.TypeOf(float32(0)), expected: float32(1.5)},
+		{val: math.MaxFloat64, typ: reflect.TypeOf(float32(0)), err: "value 1.7976931348623157e+308 overflows float32"},
+		{val: int32(2), typ: reflect.TypeOf(float64(0)), expected: float64(2)},
+		{val: "1", typ: reflect.TypeOf(int(0)), err: errArgType.Error()},
+	} {
+		result, err := convertNumber(reflect.ValueOf(tc.val), tc.typ)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Interface() != tc.expected {
+			t.Fatalf("expected %v, got %v", tc.expected, result.Interface())
+		}
+	}
+}
+
+// TestConvertMap checks the map fields are converted to the C arrays of the
+// structures with the "Key" and "Value" fields and back.
+func TestConvertMap(t *testing.T) {
+	cString := reflect.TypeOf(CellNameToCoordinates).In(0)
+	cInt, _ := reflect.TypeOf(CheckConverters).Out(0).FieldByName("ArrLen")
+	cEntry := reflect.StructOf([]reflect.StructField{
+		{Name: "Key", Type: cString},
+		{Name: "Value", Type: cInt.Type},
+	})
+	cType := reflect.StructOf([]reflect.StructField{
+		{Name: "LevelsLen", Type: cInt.Type},
+		{Name: "Levels", Type: reflect.PointerTo(cEntry)},
+		{Name: "Outline", Type: reflect.TypeOf(int8(0))},
+	})
+	type options struct {
+		Levels  map[string]int
+		Outline int16
+	}
+	opts := options{Levels: map[string]int{"B": 2, "A": 1, "C": 3}, Outline: 7}
+	cVal, err := goValueToC(reflect.ValueOf(opts), reflect.New(cType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer freeCValue(cVal.Elem())
+	if l := cVal.Elem().FieldByName("LevelsLen").Int(); l != 3 {
+		t.Fatalf("expected 3 entries, got %d", l)
+	}
+	goVal, err := cValueToGo(cVal.Elem(), reflect.TypeOf(options{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(goVal.Elem().Interface(), opts) {
+		t.Fatalf("expected %+v, got %+v", opts, goVal.Elem().Interface())
+	}
+	if problems := checkConverter(reflect.TypeOf(options{}), cType, "options", map[converterKey]bool{}); len(problems) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	opts.Outline = 300
+	cOverflow, err := goValueToC(reflect.ValueOf(opts), reflect.New(cType))
+	defer freeCValue(cOverflow.Elem())
+	if err == nil || err.Error() != "value 300 overflows int8" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	opts.Levels = map[string]int{"A": math.MaxInt32 + 1}
+	cOverflow, err = goValueToC(reflect.ValueOf(opts), reflect.New(cType))
+	defer freeCValue(cOverflow.Elem())
+	if err == nil || err.Error() != "value 2147483648 overflows int32" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// TestExportedFunctions checks the result types of the exported functions are
+// defined by the Python package, except the deallocators without results, and
+// the package doesn't define the result types of the unknown functions.
+func TestExportedFunctions(t *testing.T) {
+	src, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pySrc, err := os.ReadFile("excelize.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restypes := map[string]bool{}
+	block := regexp.MustCompile(`(?s)\nrestypes = \{\n(.*?)\n\}\n`).FindSubmatch(pySrc)
+	if block == nil {
+		t.Fatal("missing result types in the Python package")
+	}
+	for _, match := range regexp.MustCompile(`(?m)^    "(\w+)": `).FindAllSubmatch(block[1], -1) {
+		restypes[string(match[1])] = true
+	}
+	for _, match := range regexp.MustCompile(`(?m)^//export (\w+)$`).FindAllStringSubmatch(string(src), -1) {
+		if strings.HasPrefix(match[1], "Free") {
+			continue
+		}
+		if !restypes[match[1]] {
+			t.Errorf("missing result type of the exported function %s", match[1])
+		}
+		delete(restypes, match[1])
+	}
+	for name := range restypes {
+		t.Errorf("unknown exported function %s", name)
+	}
+}
+
+// TestImageDecoders checks the image formats reported by the build
+// information are the image decoders registered by the blank imports.
+func TestImageDecoders(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoders []string
+	for _, spec := range file.Imports {
+		if spec.Name != nil && spec.Name.Name == "_" {
+			decoders = append(decoders, path.Base(strings.Trim(spec.Path.Value, `"`)))
+		}
+	}
+	sort.Strings(decoders)
+	if !reflect.DeepEqual(decoders, imageDecoders) {
+		t.Fatalf("expected image decoders %v, got %v", decoders, imageDecoders)
+	}
+}
+
+// TestJSONReadOnlyMethods checks the read-only methods called by CallJSON are
+// the methods of the workbook.
+func TestJSONReadOnlyMethods(t *testing.T) {
+	fileType := reflect.TypeOf(&excelize.File{})
+	for name := range jsonReadOnlyMethods {
+		if _, ok := fileType.MethodByName(name); !ok {
+			t.Errorf("unknown method %s of the workbook", name)
+		}
+	}
+}
+
+// TestStreamWriter checks the rows and the other calls are written straight
+// to the stream writer of the workbook, and the discarded stream writer is
+// replaced by an empty stream writer, so the workbook can still be saved.
+func TestStreamWriter(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	w, err := openStreamWriter(f, "Sheet1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.SetColWidth(1, 1, 20)
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	for row, values := range [][]interface{}{{"Name", "Date"}, {"a", date}, {excelize.Cell{Formula: "1+1"}, nil}} {
+		cell, _ := excelize.CoordinatesToCellName(1, row+1)
+		if err = w.setRow(cell, values, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.setRow("A2", nil, nil); err == nil || err.Error() != "row 2 has already been written" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if w.rows != 3 || w.lastRow != 3 {
+		t.Fatalf("unexpected %d rows and last row %d", w.rows, w.lastRow)
+	}
+	if err = w.call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.SetColWidth(2, 2, 20)
+	}, nil); err != excelize.ErrStreamSetColWidth {
+		t.Fatalf("expected %v, got %v", excelize.ErrStreamSetColWidth, err)
+	}
+	if err = w.call(func(streamWriter *excelize.StreamWriter) error {
+		return streamWriter.MergeCell("A4", "B4")
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.flush(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := f.GetCellValue("Sheet1", "B2"); val != "1/2/26 00:00" {
+		t.Fatalf("unexpected date %s", val)
+	}
+	if formula, _ := f.GetCellFormula("Sheet1", "A3"); formula != "1+1" {
+		t.Fatalf("unexpected formula %s", formula)
+	}
+	if width, _ := f.GetColWidth("Sheet1", "A"); width != 20 {
+		t.Fatalf("unexpected column width %v", width)
+	}
+	if mergeCells, _ := f.GetMergeCells("Sheet1"); len(mergeCells) != 1 {
+		t.Fatalf("unexpected merged cells %v", mergeCells)
+	}
+
+	if _, err = f.NewSheet("Sheet2"); err != nil {
+		t.Fatal(err)
+	}
+	if w, err = openStreamWriter(f, "Sheet2", false); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.setRow("A1", []interface{}{"discarded"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.discard(f); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := excelize.OpenReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer saved.Close()
+	if rows, err := saved.GetRows("Sheet2"); err != nil || len(rows) != 0 {
+		t.Fatalf("unexpected rows %v of the discarded stream writer, error %v", rows, err)
+	}
+	if val, _ := saved.GetCellValue("Sheet1", "A1"); val != "Name" {
+		t.Fatalf("unexpected value %s", val)
+	}
+}
+
+// TestStreamWriterAppend checks the stream writer in append mode writes the
+// rows after the existing rows of the worksheet, extends the range of the
+// table which ends at the last row of the worksheet when flushing, and removes
+// the appended rows when discarding.
+func TestStreamWriterAppend(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Value"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetSheetRow("Sheet1", "A2", &[]interface{}{"a", 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddTable("Sheet1", &excelize.Table{Range: "A1:B2", StyleName: "TableStyleMedium2"}); err != nil {
+		t.Fatal(err)
+	}
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := openStreamWriter(f, "Sheet1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.lastRow != 2 {
+		t.Fatalf("expected last row 2, got %d", w.lastRow)
+	}
+	if err = w.setRow("A2", []interface{}{"b"}, nil); err == nil || err.Error() != "row 2 has already been written" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err = w.setRow("A3", []interface{}{"b", excelize.Cell{StyleID: style, Formula: "B2+1"}}, []excelize.RowOpts{{Height: 30}}); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := f.GetRows("Sheet1"); !reflect.DeepEqual(rows, [][]string{{"Name", "Value"}, {"a", "1"}, {"b", ""}}) {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+	if formula, _ := f.GetCellFormula("Sheet1", "B3"); formula != "B2+1" {
+		t.Fatalf("unexpected formula %s", formula)
+	}
+	if styleID, _ := f.GetCellStyle("Sheet1", "B3"); styleID != style {
+		t.Fatalf("unexpected style %d", styleID)
+	}
+	if height, _ := f.GetRowHeight("Sheet1", 3); height != 30 {
+		t.Fatalf("unexpected row height %v", height)
+	}
+	if err = w.flush(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	tables, err := f.GetTables("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].Range != "A1:B3" || tables[0].StyleName != "TableStyleMedium2" {
+		t.Fatalf("unexpected tables %+v", tables)
+	}
+
+	if w, err = openStreamWriter(f, "Sheet1", true); err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range []string{"A4", "A5"} {
+		if err = w.setRow(cell, []interface{}{"c"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.discard(f); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := f.GetRows("Sheet1"); len(rows) != 3 {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+}
+
+// TestExtendTableRef checks only the range references of the table and the
+// auto filter with the same range are replaced in the table part, and the
+// tables which don't end at the given row or have the totals row are kept.
+func TestExtendTableRef(t *testing.T) {
+	table := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<table xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xr:uid="{00000000-0000-0000-0000-000000000001}" id="1" name="Table1" displayName="Table1" ref='A1:B2'>` +
+		`<autoFilter ref="A1:B2" xr:uid="{00000000-0000-0000-0000-000000000002}"><filterColumn colId="0"><filters><filter val="a"/></filters></filterColumn></autoFilter>` +
+		`<tableColumns count="2"><tableColumn id="1" name="Name"/><tableColumn id="2" name="Value"/></tableColumns>` +
+		`<tableStyleInfo name="TableStyleMedium2" showFirstColumn="0" showLastColumn="0" showRowStripes="1" showColumnStripes="0"/>` +
+		`<extLst><ext uri="{504A1905-F514-4f6f-8877-14C23A59335A}"><x14:table xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" altText="Table"/></ext></extLst></table>`
+	output, err := extendTableRef([]byte(table), 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(strings.Replace(table, "ref='A1:B2'", "ref='A1:B5'", 1), `ref="A1:B2"`, `ref="A1:B5"`, 1)
+	if string(output) != expected {
+		t.Fatalf("unexpected table part %s", output)
+	}
+	for _, content := range []string{
+		strings.Replace(table, "ref='A1:B2'", "ref='A1:B3'", 1),
+		strings.Replace(table, `id="1"`, `id="1" totalsRowCount="1"`, 1),
+	} {
+		if output, err = extendTableRef([]byte(content), 2, 5); err != nil || output != nil {
+			t.Fatalf("unexpected table part %s, error %v", output, err)
+		}
+	}
+	autoFilter := strings.Replace(table, `<autoFilter ref="A1:B2"`, `<autoFilter ref="A1:A2"`, 1)
+	if output, err = extendTableRef([]byte(autoFilter), 2, 5); err != nil || !strings.Contains(string(output), `<autoFilter ref="A1:A2"`) {
+		t.Fatalf("unexpected table part %s, error %v", output, err)
+	}
+}
+
+// BenchmarkGoValueToC benchmarks converting the Go values to C structures.
+func BenchmarkGoValueToC(b *testing.B) {
+	for _, tc := range converterTestCases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cVal, err := goValueToC(reflect.ValueOf(tc.goVal), reflect.New(tc.cType))
+				if err != nil {
+					b.Fatal(err)
+				}
+				freeCValue(cVal.Elem())
+			}
+		})
+	}
+}
+
+// BenchmarkCValueToGo benchmarks converting the C structures to Go values.
+func BenchmarkCValueToGo(b *testing.B) {
+	for _, tc := range converterTestCases {
+		b.Run(tc.name, func(b *testing.B) {
+			cVal, err := goValueToC(reflect.ValueOf(tc.goVal), reflect.New(tc.cType))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer freeCValue(cVal.Elem())
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := cValueToGo(cVal.Elem(), reflect.TypeOf(tc.goVal)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestErrorCode checks the error codes are resolved from the error values, the
+// wrapped errors keep their codes and the errors with the same messages but
+// different values are not matched.
+func TestErrorCode(t *testing.T) {
+	for sentinel, code := range errorCodes {
+		if got := errorCode(fmt.Errorf("wrapped: %w", sentinel)); got != code {
+			t.Errorf("unexpected error code %d of the wrapped error %v", got, sentinel)
+		}
+		if got := errorCode(errors.New(sentinel.Error())); got == code {
+			t.Errorf("unexpected error code %d of the error message %q", got, sentinel.Error())
+		}
+	}
+	notFound, released := errorCode(handleError{name: "file"}), errorCode(handleError{name: "file", released: true})
+	if notFound == released || errorCode(errors.New(handleError{name: "file"}.Error())) == notFound {
+		t.Errorf("unexpected error codes %d and %d of the handle errors", notFound, released)
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	_, err := f.GetCellValue("SheetN", "A1")
+	if got := errorCode(fmt.Errorf("wrapped: %w", err)); got == errorCode(errors.New("unknown")) {
+		t.Errorf("unexpected error code %d of the sheet not exist error", got)
+	}
+	if _, err = excelize.OpenFile("NotExist.xlsx"); errorCode(err) == errorCode(errors.New(err.Error())) {
+		t.Errorf("unexpected error code %d of the file not exist error", errorCode(err))
+	}
+}
+
+// TestErrorMessageCode checks the error codes of the excelize errors without
+// sentinel or type, which were matched by the messages.
+func TestErrorMessageCode(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	// The error codes were compared with the codes of the reference errors,
+	// since the test files can't use cgo
+	sheetNotExist, notExist := excelize.ErrSheetNotExist{SheetName: "SheetN"}, excelize.ErrDefinedNameScope
+	assert := func(want, err error) {
+		t.Helper()
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if got := errorCode(err); got != errorCode(want) || got == errorCode(errors.New("unknown")) {
+			t.Errorf("unexpected error code %d of the error %q", got, err.Error())
+		}
+	}
+	if err := f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Category", 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddChartSheet("Chart1", &excelize.Chart{
+		Type:   excelize.Col,
+		Series: []excelize.ChartSeries{{Categories: "Sheet1!$A$1", Values: "Sheet1!$B$1"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := f.GetCellValue("Chart1", "A1")
+	assert(sheetNotExist, err)
+	assert(notExist, f.DeleteTable("Table1"))
+	assert(notExist, f.DeleteSlicer("Slicer1"))
+	assert(notExist, f.DeletePivotTable("Sheet1", "PivotTable1"))
+	assert(notExist, tableNotExistError{name: "Table1"})
+	_, _, err = excelize.CellNameToCoordinates("A")
+	assert(excelize.ErrCoordinates, err)
+	_, err = excelize.CoordinatesToCellName(0, 1)
+	assert(excelize.ErrCoordinates, err)
+	_, err = excelize.JoinCellName("A", 0)
+	assert(excelize.ErrCoordinates, err)
+	_, err = excelize.ColumnNameToNumber("-")
+	assert(excelize.ErrCoordinates, err)
+	assert(excelize.ErrCoordinates, f.SetCellValue("Sheet1", "A", 1))
+	assert(excelize.ErrCoordinates, f.SetRowHeight("Sheet1", 0, 20))
+}
+
+// TestFindTable checks the tables were found by the case-insensitive names
+// in the workbook with the chart sheets.
+func TestFindTable(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Category", 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddChartSheet("Chart1", &excelize.Chart{
+		Type:   excelize.Col,
+		Series: []excelize.ChartSeries{{Categories: "Sheet1!$A$1", Values: "Sheet1!$B$1"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := f.NewSheet("Sheet2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.AddTable(f.GetSheetName(sheet), &excelize.Table{Range: "A1:B2", Name: "Table1"}); err != nil {
+		t.Fatal(err)
+	}
+	name, table, err := findTable(f, "TABLE1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Sheet2" || table.Range != "A1:B2" {
+		t.Errorf("unexpected table %s on the sheet %s", table.Range, name)
+	}
+	if _, _, err = findTable(f, "Table2"); !errors.As(err, new(tableNotExistError)) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// TestCalcSlots checks the calculations wait for the slots of the calculations
+// running in the background, and the slots are released after the
+// calculations returned.
+func TestCalcSlots(t *testing.T) {
+	f := excelize.NewFile()
+	idx := files.store(f)
+	defer files.release(idx)
+	if err := f.SetCellFormula("Sheet1", "A1", "=1+2"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < cap(calcSlots); i++ {
+		calcSlots <- struct{}{}
+	}
+	_, err := calcCellValueWithTimeout(idx, "Sheet1", "A1", nil, 10*time.Millisecond)
+	for i := 0; i < cap(calcSlots); i++ {
+		<-calcSlots
+	}
+	if err != errCalcTimeout {
+		t.Fatalf("unexpected error %v while waiting for a slot", err)
+	}
+	if val, err := calcCellValueWithTimeout(idx, "Sheet1", "A1", nil, 0); err != nil || val != "3" {
+		t.Fatalf("unexpected calculated value %q, %v", val, err)
+	}
+	if len(calcSlots) != 0 {
+		t.Fatalf("unexpected %d slots in use after the calculation returned", len(calcSlots))
+	}
+}
+
+// TestHandleParent checks the handles of the iterators and stream writers keep
+// the handle of their workbook until they were released.
+func TestHandleParent(t *testing.T) {
+	r := newHandleRegistry("rows iterator")
+	idx := r.storeChild(struct{}{}, 2)
+	if parent := r.parent(idx); parent != 2 {
+		t.Fatalf("unexpected parent handle %d", parent)
+	}
+	if _, err := r.release(idx); err != nil {
+		t.Fatal(err)
+	}
+	if parent := r.parent(idx); parent != 0 {
+		t.Fatalf("unexpected parent handle %d of the released handle", parent)
+	}
+}
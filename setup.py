@@ -14,6 +14,7 @@ from __future__ import annotations
 import sys
 from warnings import warn
 import os
+import re
 import shutil
 from setuptools import setup
 from setuptools.command.install import install
@@ -23,6 +24,10 @@ if sys.version_info < (3, 9):
     exit()
 
 
+with open("excelize.py", encoding="utf-8") as file:
+    version = re.search(r'^__version__ = "(.+)"$', file.read(), re.M).group(1)
+
+
 class CustomInstallCommand(install):
     def run(self):
         install.run(self)
@@ -42,7 +47,7 @@ class CustomInstallCommand(install):
 
 setup(
     name="excelize",
-    version="0.0.9",
+    version=version,
     license="BSD 3-Clause",
     license_files=("LICENSE"),
     description="A Python build of the Go Excelize library for reading and writing Microsoft Excel™ (XLAM / XLSM / XLSX / XLTM / XLTX) spreadsheets",
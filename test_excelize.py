@@ -15,9 +15,16 @@ from dataclasses import dataclass
 from unittest.mock import patch
 import datetime
 import random
+import re
+import threading
+import zipfile
 from typing import List, Optional
+from zoneinfo import ZoneInfo
 from ctypes import (
+    c_float,
     c_int,
+    c_ubyte,
+    c_uint,
     Structure,
     POINTER,
 )
@@ -326,6 +333,151 @@ class TestExcelize(unittest.TestCase):
             "expected type str for argument 'sheet', but got int",
         )
 
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["A1", "B1"]))
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A3", ["A3", 3]))
+        self.assertIsNone(f.set_row_height("Sheet1", 3, 30))
+        rows = f.rows("Sheet1")
+        self.assertEqual(
+            rows.next_batch(2),
+            [(1, ["A1", "B1"], excelize.RowOpts()), (2, [], excelize.RowOpts())],
+        )
+        self.assertEqual(
+            rows.next_batch(2), [(3, ["A3", "3"], excelize.RowOpts(height=30))]
+        )
+        self.assertEqual(rows.next_batch(2), [])
+        with self.assertRaises(RuntimeError) as context:
+            rows.next_batch(0)
+        self.assertEqual(str(context.exception), "parameter is invalid")
+        with self.assertRaises(TypeError) as context:
+            rows.next_batch("1")
+        self.assertEqual(
+            str(context.exception),
+            "expected type int for argument 'n', but got str",
+        )
+        self.assertIsNone(rows.close())
+        with self.assertRaises(RuntimeError) as context:
+            rows.next_batch(1)
+        self.assertEqual(
+            str(context.exception), "rows iterator pointer has been released"
+        )
+        self.assertIsNone(f.close())
+
+    def test_call_json(self):
+        f = excelize.new_file()
+        self.assertIsNone(f.call_json("SetCellValue", "Sheet1", "A1", 42))
+        self.assertEqual(f.call_json("GetCellValue", "Sheet1", "A1"), "42")
+        style = f.call_json(
+            "NewStyle",
+            excelize.Style(num_fmt=2, font=excelize.Font(bold=True)),
+        )
+        self.assertIsInstance(style, int)
+        result = f.call_json("GetStyle", style)
+        self.assertEqual(result["NumFmt"], 2)
+        self.assertTrue(result["Font"]["Bold"])
+        self.assertIsNone(f.call_json("SetCellStyle", "Sheet1", "A1", "A1", style))
+        self.assertEqual(f.get_cell_style("Sheet1", "A1"), style)
+        self.assertIsNone(
+            f.call_json(
+                "AddChart",
+                "Sheet1",
+                "C1",
+                {
+                    "Type": excelize.ChartType.Col,
+                    "Series": [{"Name": "Sheet1!$A$1", "Values": "Sheet1!$A$1"}],
+                },
+            )
+        )
+        self.assertEqual(f.call_json("GetRows", "Sheet1"), [["42.00"]])
+        self.assertEqual(
+            f.call_json("GetRows", "Sheet1", {"RawCellValue": True}), [["42"]]
+        )
+        self.assertEqual(f.call_json("GetCellHyperLink", "Sheet1", "A1"), [False, ""])
+        # The calculation reports the progress as the calc_cell_value function
+        events: List[excelize.ProgressEvent] = []
+        self.assertIsNone(f.set_progress_callback(events.append))
+        self.assertIsNone(f.set_cell_formula("Sheet1", "B1", "=A1*2"))
+        self.assertEqual(f.call_json("CalcCellValue", "Sheet1", "B1"), "84")
+        self.assertEqual(
+            f.call_json("CalcCellValue", "Sheet1", "B1", {"RawCellValue": True}), "84"
+        )
+        self.assertEqual(
+            events[-1],
+            excelize.ProgressEvent(
+                phase=excelize.ProgressPhase.ProgressCalc, sheet="Sheet1", done=True
+            ),
+        )
+        self.assertIsNone(f.set_progress_callback(None))
+        for method, args, err in [
+            ("Foo", [], 'unsupported method "Foo"'),
+            ("Close", [], 'unsupported method "Close"'),
+            (
+                "GetCellValue",
+                ["Sheet1"],
+                "method GetCellValue expects 2 arguments, but got 1",
+            ),
+            (
+                "GetCellValue",
+                [1, "A1"],
+                "invalid argument 1 of method GetCellValue: json: cannot unmarshal "
+                "number into Go value of type string",
+            ),
+            (
+                "CalcCellValue",
+                ["Sheet1"],
+                "method CalcCellValue expects 2 arguments, but got 1",
+            ),
+        ]:
+            with self.assertRaises(RuntimeError) as context:
+                f.call_json(method, *args)
+            self.assertEqual(str(context.exception), err)
+        with self.assertRaises(excelize.SheetNotExistError) as context:
+            f.call_json("GetCellValue", "SheetN", "A1")
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(TypeError) as context:
+            f.call_json(1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type str for argument 'method', but got int",
+        )
+        self.assertIsNone(f.close())
+        with self.assertRaises(excelize.HandleError) as context:
+            f.call_json("GetCellValue", "Sheet1", "A1")
+        self.assertEqual(str(context.exception), "file pointer has been released")
+
+    def test_cols(self):
+        f = excelize.new_file()
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["A1", "B1", "C1"]))
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A2", ["A2", None, 3.5]))
+        cols, result = f.cols("Sheet1"), []
+        while cols.next():
+            result.append(cols.rows())
+        self.assertIsNone(cols.error())
+        self.assertEqual(result, [["A1", "A2"], ["B1", ""], ["C1", "3.5"]])
+        self.assertIsNone(cols.close())
+        expected = "cols iterator pointer has been released"
+        with self.assertRaises(RuntimeError) as context:
+            cols.next()
+        self.assertEqual(str(context.exception), expected)
+        with self.assertRaises(RuntimeError) as context:
+            cols.rows()
+        self.assertEqual(str(context.exception), expected)
+        with self.assertRaises(RuntimeError) as context:
+            cols.error()
+        self.assertEqual(str(context.exception), expected)
+        with self.assertRaises(RuntimeError) as context:
+            cols.close()
+        self.assertEqual(str(context.exception), expected)
+        with self.assertRaises(RuntimeError) as context:
+            _ = f.cols("SheetN")
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(TypeError) as context:
+            _ = f.cols(1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type str for argument 'sheet', but got int",
+        )
+        self.assertIsNone(f.close())
+
     def test_stream_writer(self):
         f = excelize.new_file()
 
@@ -618,11 +770,30 @@ class TestExcelize(unittest.TestCase):
             f.set_cell_value("Sheet1", "A8", excelize.RichTextRun())
         self.assertEqual(
             str(context.exception),
-            "expected type bool, float, int, str, date, datetime or NoneType "
-            "for argument 'value', but got RichTextRun",
+            "expected type bool, float, int, str, date, datetime, timedelta or "
+            "NoneType for argument 'value', but got RichTextRun",
         )
 
         self.assertIsNone(f.set_cell_value("Sheet1", "A8", datetime.date(2016, 8, 30)))
+        for cell, value, expected in [
+            ("B1", 2**40, "1099511627776"),
+            ("B2", -(2**63), "-9223372036854775808"),
+            ("B3", 2**64 - 1, "18446744073709551615"),
+            ("B4", datetime.datetime(2048, 8, 30, 11, 51, 0), "54300.49375"),
+            (
+                "B5",
+                datetime.datetime(
+                    2048, 8, 30, 12, 51, 0, tzinfo=ZoneInfo("Europe/Berlin")
+                ),
+                "54300.535416666666",
+            ),
+            ("B6", datetime.timedelta(hours=36), "1.5"),
+        ]:
+            self.assertIsNone(f.set_cell_value("Sheet1", cell, value))
+            self.assertEqual(
+                f.get_cell_value("Sheet1", cell, excelize.Options(raw_cell_value=True)),
+                expected,
+            )
         self.assertIsNone(f.set_cell_bool("Sheet1", "A9", True))
         self.assertIsNone(f.set_cell_bool("Sheet1", "A10", False))
         with self.assertRaises(RuntimeError) as context:
@@ -1156,9 +1327,30 @@ class TestExcelize(unittest.TestCase):
             "expected type bytes for argument 'buffer', but got str",
         )
 
+        f = excelize.new_file()
+        self.assertIsNone(f.set_cell_value("Sheet1", "A1", "Hello"))
+        buffer = f.write_to_buffer()
+        self.assertIsNone(f.close())
+        f = excelize.open_reader(buffer)
+        self.assertEqual(f.get_cell_value("Sheet1", "A1"), "Hello")
+        buffer = f.write_to_buffer(excelize.Options(password="password"))
+        self.assertIsNone(f.close())
+        with self.assertRaises(RuntimeError) as context:
+            _ = excelize.open_reader(buffer)
+        self.assertEqual(str(context.exception), "zip: not a valid zip file")
+        f = excelize.open_reader(buffer, excelize.Options(password="password"))
+        self.assertEqual(f.get_cell_value("Sheet1", "A1"), "Hello")
+        with self.assertRaises(TypeError) as context:
+            f.write_to_buffer(1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type Options for argument 'opts', but got int",
+        )
+        self.assertIsNone(f.close())
+
     def test_none_file_pointer(self):
         f = excelize.new_file()
-        f.file_index = 100
+        f.file_index = 0
         expected = "can not find file pointer"
         with self.assertRaises(RuntimeError) as context:
             f.add_data_validation("Sheet1", excelize.DataValidation())
@@ -1337,10 +1529,13 @@ class TestExcelize(unittest.TestCase):
         with self.assertRaises(RuntimeError) as context:
             f.save()
         self.assertEqual(str(context.exception), expected)
+        with self.assertRaises(RuntimeError) as context:
+            f.write_to_buffer()
+        self.assertEqual(str(context.exception), expected)
 
         f = excelize.new_file()
         sw = f.new_stream_writer("Sheet1")
-        sw.sw_index = 100
+        sw.sw_index = 0
         sw_expected = "can not find stream writer pointer"
         with self.assertRaises(RuntimeError) as context:
             sw.add_table(excelize.Table())
@@ -1396,7 +1591,7 @@ class TestExcelize(unittest.TestCase):
 
         f = excelize.new_file()
         rows = f.rows("Sheet1")
-        rows.index = 100
+        rows.index = 0
         rows_expected = "can not find rows iterator pointer"
         with self.assertRaises(RuntimeError) as context:
             rows.close()
@@ -2343,6 +2538,369 @@ class TestExcelize(unittest.TestCase):
             "expected type Options for argument 'opts', but got int",
         )
 
+    def test_stream_writer_row_opts(self):
+        f = excelize.new_file()
+        style = f.new_style(excelize.Style(font=excelize.Font(bold=True)))
+        sw = f.new_stream_writer("Sheet1")
+        self.assertIsNone(
+            sw.set_row("A1", ["Group"], excelize.RowOpts(height=30, style_id=style))
+        )
+        for row in range(2, 5):
+            self.assertIsNone(
+                sw.set_row(
+                    f"A{row}",
+                    [row],
+                    excelize.RowOpts(hidden=True, outline_level=1),
+                )
+            )
+        with self.assertRaises(RuntimeError) as context:
+            sw.set_row("A5", [5], excelize.RowOpts(height=410))
+        self.assertEqual(
+            context.exception.code, excelize.ErrorCode.ErrCodeExceedsLimit
+        )
+        with self.assertRaises(TypeError) as context:
+            sw.set_row("A5", [5], 1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type RowOpts for argument 'opts', but got int",
+        )
+        self.assertIsNone(sw.flush())
+        path = os.path.join("test", "TestStreamWriterRowOpts.xlsx")
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+
+        f = excelize.open_file(path)
+        self.assertEqual(f.get_row_height("Sheet1", 1), 30)
+        self.assertEqual(f.get_cell_style("Sheet1", "A1"), style)
+        self.assertTrue(f.get_row_visible("Sheet1", 1))
+        for row in range(2, 5):
+            self.assertFalse(f.get_row_visible("Sheet1", row))
+            self.assertEqual(f.get_row_outline_level("Sheet1", row), 1)
+        self.assertEqual(f.get_rows("Sheet1"), [["Group"], ["2"], ["3"], ["4"]])
+        self.assertIsNone(f.close())
+
+    def test_stream_writer_cells(self):
+        f = excelize.new_file()
+        style = f.new_style(excelize.Style(num_fmt=4))
+        sw = f.new_stream_writer("Sheet1")
+        self.assertIsNone(sw.set_row("A1", [1024.5, 2048]))
+        runs = [
+            excelize.RichTextRun(text="Rich ", font=excelize.Font(bold=True)),
+            excelize.RichTextRun(text="Text"),
+        ]
+        self.assertIsNone(
+            sw.set_row(
+                "A2",
+                [
+                    excelize.Cell(style_id=style, formula="SUM(A1:B1)"),
+                    runs,
+                    excelize.Cell(style_id=style, value=10),
+                    None,
+                    "Total",
+                ],
+                excelize.RowOpts(height=20),
+            )
+        )
+        with self.assertRaises(TypeError) as context:
+            sw.set_row("A3", [excelize.Cell(style_id="1")])
+        self.assertEqual(
+            str(context.exception),
+            "expected type int for argument 'style_id', but got str",
+        )
+        with self.assertRaises(TypeError) as context:
+            sw.set_row("A3", [["Text"]])
+        self.assertEqual(
+            str(context.exception),
+            "expected type RichTextRun for argument 'rich_text', but got str",
+        )
+        with self.assertRaises(RuntimeError) as context:
+            sw.set_row("A", [excelize.Cell(value=1)])
+        self.assertEqual(
+            str(context.exception),
+            'cannot convert cell "A" to coordinates: invalid cell name "A"',
+        )
+        self.assertIsNone(sw.flush())
+        path = os.path.join("test", "TestStreamWriterCells.xlsx")
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+
+        f = excelize.open_file(path)
+        self.assertEqual(f.get_cell_formula("Sheet1", "A2"), "SUM(A1:B1)")
+        self.assertEqual(f.get_cell_style("Sheet1", "A2"), style)
+        self.assertEqual(f.calc_cell_value("Sheet1", "A2"), "3,072.50")
+        self.assertEqual(f.get_cell_rich_text("Sheet1", "B2")[0].text, "Rich ")
+        self.assertEqual(f.get_cell_rich_text("Sheet1", "B2")[1].text, "Text")
+        self.assertEqual(f.get_cell_value("Sheet1", "C2"), "10.00")
+        self.assertEqual(f.get_cell_value("Sheet1", "E2"), "Total")
+        self.assertEqual(f.get_row_height("Sheet1", 2), 20)
+        self.assertIsNone(f.close())
+
+    def test_stream_writer_discard(self):
+        path = os.path.join("test", "TestStreamWriterDiscard.xlsx")
+        tmp_dir = os.path.join("test", "TestStreamWriterDiscard")
+        os.makedirs(tmp_dir, exist_ok=True)
+        f = excelize.new_file()
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+
+        f = excelize.open_file(path, excelize.Options(tmp_dir=tmp_dir))
+        sw = f.new_stream_writer("Sheet1")
+        self.assertIsNone(sw.set_col_width(1, 2, 30))
+        self.assertIsNone(sw.insert_page_break("A10"))
+        self.assertIsNone(sw.set_row("A1", ["Column1", "Column2"]))
+        self.assertIsNone(sw.set_row("A2", [1, 2]))
+        self.assertIsNone(sw.merge_cell("A3", "B3"))
+        self.assertIsNone(sw.add_table(excelize.Table(name="Table1", range="A1:B2")))
+        self.assertIsNone(sw.discard())
+        with self.assertRaises(excelize.HandleError) as context:
+            sw.discard()
+        self.assertEqual(
+            str(context.exception), "stream writer pointer has been released"
+        )
+        # The rows, merged cells and tables are dropped, and the column
+        # settings set by the stream writer are kept
+        self.assertEqual(f.get_rows("Sheet1"), [])
+        self.assertEqual(f.get_col_width("Sheet1", "A"), 30)
+        self.assertEqual(f.get_merge_cells("Sheet1"), [])
+        self.assertEqual(f.get_tables("Sheet1"), [])
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+
+        f = excelize.open_file(path, excelize.Options(tmp_dir=tmp_dir))
+        self.assertEqual(f.get_rows("Sheet1"), [])
+        self.assertEqual(f.get_tables("Sheet1"), [])
+        # The stream writer can be created again on the discarded worksheet,
+        # and its temporary file is removed when the workbook is closed
+        sw = f.new_stream_writer("Sheet1")
+        self.assertIsNone(sw.set_row("A1", ["Column1", "Column2"]))
+        # Write enough rows to spill the stream data into a temporary file
+        for row in range(2, 17002):
+            self.assertIsNone(sw.set_row(f"A{row}", ["x" * 1000, row]))
+        self.assertEqual(len(os.listdir(tmp_dir)), 1)
+        self.assertIsNone(sw.add_table(excelize.Table(name="Table1", range="A1:B2")))
+        self.assertIsNone(sw.flush())
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+        self.assertEqual(os.listdir(tmp_dir), [])
+
+        f = excelize.open_file(path)
+        self.assertEqual(f.get_tables("Sheet1")[0].name, "Table1")
+        self.assertEqual(f.get_cell_value("Sheet1", "B17001"), "17001")
+        self.assertIsNone(f.close())
+        # The stream writer can be flushed again or discarded after the
+        # flushing failed
+        f = excelize.new_file()
+        for discard in [False, True]:
+            self.assertEqual(f.new_sheet("Sheet2"), 1)
+            sw = f.new_stream_writer("Sheet2")
+            self.assertIsNone(sw.set_row("A1", ["Column1"]))
+            self.assertIsNone(f.delete_sheet("Sheet2"))
+            with self.assertRaises(excelize.SheetNotExistError) as context:
+                sw.flush()
+            self.assertEqual(str(context.exception), "sheet Sheet2 does not exist")
+            self.assertEqual(f.new_sheet("Sheet2"), 1)
+            if discard:
+                self.assertIsNone(sw.discard())
+                self.assertEqual(f.get_rows("Sheet2"), [])
+            else:
+                self.assertIsNone(sw.flush())
+                self.assertEqual(f.get_rows("Sheet2"), [["Column1"]])
+            self.assertIsNone(f.delete_sheet("Sheet2"))
+        self.assertIsNone(f.close())
+        # Discard the stream writer after the workbook was closed
+        f = excelize.new_file()
+        sw = f.new_stream_writer("Sheet1")
+        self.assertIsNone(f.close())
+        self.assertIsNone(sw.discard())
+
+    def test_stream_writer_append(self):
+        path = os.path.join("test", "TestStreamWriterAppend.xlsx")
+        f = excelize.new_file()
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["Name", "Value", "Note"]))
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A2", ["a", 1, True]))
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A3", ["b", 2.5]))
+        self.assertIsNone(f.set_cell_formula("Sheet1", "B4", "SUM(B2:B3)"))
+        self.assertIsNone(
+            f.set_cell_rich_text(
+                "Sheet1",
+                "C3",
+                [
+                    excelize.RichTextRun(
+                        text="bold", font=excelize.Font(bold=True, family="Arial")
+                    ),
+                    excelize.RichTextRun(text=" text"),
+                ],
+            )
+        )
+        style = f.new_style(excelize.Style(font=excelize.Font(italic=True)))
+        self.assertIsNone(f.set_cell_style("Sheet1", "B3", "B3", style))
+        self.assertIsNone(f.set_col_width("Sheet1", "A", "A", 20))
+        self.assertIsNone(f.set_row_height("Sheet1", 2, 30))
+        self.assertIsNone(f.merge_cell("Sheet1", "D2", "E3"))
+        self.assertIsNone(f.add_table("Sheet1", excelize.Table(range="A1:C3")))
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+
+        f = excelize.open_file(path)
+        sw = f.new_stream_writer("Sheet1", append=True)
+        self.assertEqual(sw.next_row(), 5)
+        with self.assertRaises(RuntimeError):
+            sw.set_row("A4", ["c"])
+        with self.assertRaises(excelize.ExcelizeError) as context:
+            sw.add_table(excelize.Table(range="G1:H2"))
+        self.assertEqual(context.exception.code, excelize.ErrorCode.ErrCodeUnsupported)
+        self.assertIsNone(sw.set_row("A5", ["c", 3]))
+        self.assertIsNone(sw.set_row("A6", ["d", 4]))
+        self.assertEqual(sw.next_row(), 7)
+        self.assertIsNone(sw.flush())
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+
+        f = excelize.open_file(path)
+        self.assertEqual(
+            f.get_rows("Sheet1"),
+            [
+                ["Name", "Value", "Note"],
+                ["a", "1", "TRUE"],
+                ["b", "2.5", "bold text"],
+                ["", ""],
+                ["c", "3"],
+                ["d", "4"],
+            ],
+        )
+        self.assertEqual(f.get_cell_formula("Sheet1", "B4"), "SUM(B2:B3)")
+        self.assertEqual(f.get_cell_style("Sheet1", "B3"), style)
+        self.assertEqual(f.get_cell_rich_text("Sheet1", "C3")[0].font.bold, True)
+        self.assertEqual(f.get_col_width("Sheet1", "A"), 20)
+        self.assertEqual(f.get_row_height("Sheet1", 2), 30)
+        merge_cells = f.get_merge_cells("Sheet1")
+        self.assertEqual(
+            [(m.get_start_axis(), m.get_end_axis()) for m in merge_cells],
+            [("D2", "E3")],
+        )
+        # The table which doesn't end at the last row of the worksheet is kept
+        self.assertEqual(f.get_tables("Sheet1")[0].range, "A1:C3")
+        self.assertIsNone(f.close())
+
+        f = excelize.new_file()
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["Name", "Value"]))
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A2", ["a", 1]))
+        self.assertIsNone(
+            f.add_table("Sheet1", excelize.Table(name="Table1", range="A1:B2"))
+        )
+        self.assertIsNone(
+            f.set_cell_formula(
+                "Sheet1",
+                "D1",
+                "B1*2",
+                excelize.FormulaOpts(type="shared", ref="D1:D2"),
+            )
+        )
+        canceled = [True]
+        self.assertIsNone(f.set_progress_callback(lambda event: canceled[0]))
+        sw = f.new_stream_writer("Sheet1", append=True)
+        self.assertIsNone(
+            sw.set_row(f"A{sw.next_row()}", ["b", 2, datetime.date(2026, 1, 2)])
+        )
+        # The rows are written when they are set, and the tables aren't
+        # extended if the flushing was canceled
+        self.assertEqual(len(f.get_rows("Sheet1")), 3)
+        with self.assertRaises(excelize.CanceledError):
+            sw.flush()
+        self.assertEqual(f.get_tables("Sheet1")[0].range, "A1:B2")
+        canceled[0] = False
+        self.assertIsNone(sw.flush())
+        self.assertEqual(f.get_tables("Sheet1")[0].range, "A1:B3")
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+        f = excelize.open_file(path)
+        self.assertEqual(f.get_tables("Sheet1")[0].range, "A1:B3")
+        self.assertEqual(f.get_rows("Sheet1")[2], ["b", "2", "01-02-26"])
+        # The shared formula of the existing rows is kept
+        self.assertEqual(f.get_cell_formula("Sheet1", "D2"), "B2*2")
+        self.assertEqual(f.get_sheet_dimension("Sheet1"), "A1:D3")
+        # The appended rows are removed when discarding
+        sw = f.new_stream_writer("Sheet1", append=True)
+        self.assertIsNone(sw.set_row("A4", ["c", 3]))
+        self.assertIsNone(sw.set_row("A5", ["d", 4]))
+        self.assertIsNone(sw.discard())
+        self.assertEqual(len(f.get_rows("Sheet1")), 3)
+        self.assertEqual(f.get_tables("Sheet1")[0].range, "A1:B3")
+        self.assertIsNone(f.close())
+
+    def test_calc_cell_value_cancel(self):
+        f = excelize.new_file()
+        formula = "SUMPRODUCT(A1:A300000*B1:B300000)"
+        self.assertIsNone(f.set_cell_formula("Sheet1", "C1", formula))
+        self.assertIsNone(f.set_cell_formula("Sheet1", "C2", "1+2"))
+        self.assertEqual(f.calc_cell_value("Sheet1", "C2", timeout=10), "3")
+
+        # Interrupt the calculation once the timeout elapsed
+        with self.assertRaises(excelize.TimeoutExceededError) as context:
+            f.calc_cell_value("Sheet1", "C1", timeout=0.01)
+        self.assertEqual(
+            str(context.exception),
+            "calculation timed out, the calculation keeps running in the background "
+            "until it returns",
+        )
+        self.assertEqual(context.exception.code, excelize.ErrorCode.ErrCodeTimeout)
+
+        # Cancel the calculation in flight before it has been started
+        self.assertIsNone(f.set_progress_callback(lambda event: f.cancel_calc()))
+        with self.assertRaises(excelize.CanceledError) as context:
+            f.calc_cell_value("Sheet1", "C2", timeout=0)
+        self.assertEqual(str(context.exception), "calculation has been canceled")
+        self.assertEqual(context.exception.code, excelize.ErrorCode.ErrCodeCanceled)
+        # The calculations without timeout can't be canceled
+        self.assertEqual(f.calc_cell_value("Sheet1", "C2"), "3")
+        self.assertIsNone(f.set_progress_callback(None))
+        # The calculations started after the cancellation are not affected
+        self.assertEqual(f.calc_cell_value("Sheet1", "C2", timeout=0), "3")
+
+        # The interrupted calculation keeps the read lock of the workbook until
+        # it returns, which doesn't block the functions reading the workbook
+        events: List[excelize.ProgressEvent] = []
+        blocked = []
+
+        def progress(event: excelize.ProgressEvent) -> bool:
+            # Skip the first event, which is reported before the calculation
+            events.append(event)
+            if len(events) == 1:
+                return False
+            if blocked:
+                return True
+            thread = threading.Thread(target=f.get_cell_value, args=("Sheet1", "C2"))
+            thread.start()
+            thread.join(1)
+            blocked.append(thread.is_alive())
+            return True
+
+        self.assertIsNone(f.set_progress_callback(progress))
+        with self.assertRaises(excelize.CanceledError) as context:
+            f.calc_cell_value("Sheet1", "C1", timeout=0)
+        self.assertEqual(
+            str(context.exception),
+            "stopped waiting for the calculation, which keeps running in the "
+            "background until it returns",
+        )
+        self.assertEqual(blocked, [False])
+        self.assertIsNone(f.set_progress_callback(None))
+        # The functions modifying the workbook wait for the calculation
+        self.assertIsNone(f.set_cell_formula("Sheet1", "C3", "C2*2"))
+        self.assertEqual(f.calc_cell_value("Sheet1", "C3", timeout=0), "6")
+
+        with self.assertRaises(excelize.ParameterError):
+            f.calc_cell_value("Sheet1", "C2", timeout=-1)
+        with self.assertRaises(TypeError) as context:
+            f.calc_cell_value("Sheet1", "C2", timeout="1")
+        self.assertEqual(
+            str(context.exception),
+            "expected type int or float for argument 'timeout', but got str",
+        )
+        self.assertIsNone(f.close())
+        with self.assertRaises(excelize.HandleError):
+            f.cancel_calc()
+
     def test_cell_name_to_coordinates(self):
         col, row = excelize.cell_name_to_coordinates("Z3")
         self.assertEqual(col, 26)
@@ -2362,6 +2920,9 @@ class TestExcelize(unittest.TestCase):
             "expected type str for argument 'cell', but got int",
         )
 
+    def test_check_converters(self):
+        self.assertEqual(excelize.check_converters(), [])
+
     def test_cell_hyperlink(self):
         f = excelize.new_file()
         self.assertIsNone(f.set_cell_value("Sheet1", "A3", "HyperLink"))
@@ -2422,6 +2983,369 @@ class TestExcelize(unittest.TestCase):
         self.assertIsNone(f.save_as(os.path.join("test", "TestCellHyperLink.xlsx")))
         self.assertIsNone(f.close())
 
+    def test_cell_type(self):
+        f = excelize.new_file()
+        self.assertIsNone(
+            f.set_sheet_row(
+                "Sheet1",
+                "A1",
+                [1.5, True, "1/2/2024", datetime.datetime(2024, 1, 2), None],
+            )
+        )
+        for cell, cell_type, value in [
+            ("A1", excelize.CellType.CellTypeUnset, 1.5),
+            ("B1", excelize.CellType.CellTypeBool, True),
+            ("C1", excelize.CellType.CellTypeSharedString, "1/2/2024"),
+            ("D1", excelize.CellType.CellTypeUnset, datetime.datetime(2024, 1, 2)),
+            ("E1", excelize.CellType.CellTypeUnset, None),
+        ]:
+            self.assertEqual(f.get_cell_type("Sheet1", cell), cell_type)
+            self.assertEqual(f.get_cell_typed_value("Sheet1", cell), value)
+        # The number cells with a date or time number format return datetime
+        for cell, value, style in [
+            ("A2", 45293, excelize.Style(num_fmt=14)),
+            ("B2", 0.5, excelize.Style(custom_num_fmt="[$-409]h:mm AM/PM")),
+            ("C2", 1.5, excelize.Style(custom_num_fmt="[h]:mm")),
+            ("D2", 1.5, excelize.Style(custom_num_fmt='0.00 "days";[Red]-0.00')),
+            ("E2", 45293, excelize.Style(num_fmt=4)),
+        ]:
+            self.assertIsNone(f.set_cell_value("Sheet1", cell, value))
+            self.assertIsNone(
+                f.set_cell_style("Sheet1", cell, cell, f.new_style(style))
+            )
+        self.assertEqual(
+            [f.get_cell_typed_value("Sheet1", cell) for cell in ["A2", "B2", "C2"]],
+            [
+                datetime.datetime(2024, 1, 2),
+                datetime.datetime(1899, 12, 30, 12),
+                datetime.datetime(1899, 12, 31, 12),
+            ],
+        )
+        self.assertEqual(f.get_cell_typed_value("Sheet1", "D2"), 1.5)
+        self.assertEqual(f.get_cell_typed_value("Sheet1", "E2"), 45293.0)
+        # The error cell and the formula string cell have distinct types
+        path = os.path.join("test", "TestCellType.xlsx")
+        self.assertIsNone(f.set_cell_formula("Sheet1", "F1", "1/0"))
+        self.assertIsNone(f.set_cell_formula("Sheet1", "G1", 'CONCAT("a","b")'))
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+        with zipfile.ZipFile(path) as zf:
+            parts = {name: zf.read(name) for name in zf.namelist()}
+        sheet_xml = parts["xl/worksheets/sheet1.xml"]
+        for old, new in [
+            (b't="str"><f>1/0</f>', b't="e"><f>1/0</f><v>#DIV/0!</v>'),
+            (b"&#34;)</f>", b"&#34;)</f><v>ab</v>"),
+        ]:
+            self.assertIn(old, sheet_xml)
+            sheet_xml = sheet_xml.replace(old, new, 1)
+        parts["xl/worksheets/sheet1.xml"] = sheet_xml
+        with zipfile.ZipFile(path, "w") as zf:
+            for name, content in parts.items():
+                zf.writestr(name, content)
+        f = excelize.open_file(path)
+        for cell, cell_type, value_type, value in [
+            ("F1", excelize.CellType.CellTypeError, excelize.CellError, "#DIV/0!"),
+            ("G1", excelize.CellType.CellTypeFormula, excelize.CellFormula, "ab"),
+        ]:
+            self.assertEqual(f.get_cell_type("Sheet1", cell), cell_type)
+            typed_value = f.get_cell_typed_value("Sheet1", cell)
+            self.assertIs(type(typed_value), value_type)
+            self.assertEqual(typed_value, value)
+        rows = f.get_typed_rows("Sheet1", "F1:G1")
+        self.assertEqual(
+            [type(cell.value) for cell in rows[0]],
+            [excelize.CellError, excelize.CellFormula],
+        )
+        with self.assertRaises(RuntimeError) as context:
+            f.get_cell_type("SheetN", "A1")
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(RuntimeError) as context:
+            f.get_cell_typed_value("SheetN", "A1")
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(TypeError) as context:
+            f.get_cell_typed_value("Sheet1", 1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type str for argument 'cell', but got int",
+        )
+        self.assertIsNone(f.close())
+
+    def test_get_build_info(self):
+        info = excelize.get_build_info()
+        self.assertEqual(info.version, excelize.__version__)
+        self.assertTrue(info.go_version.startswith("go"))
+        self.assertNotEqual(info.excelize_version, "")
+        self.assertEqual(info.image_decoders, ["gif", "jpeg", "png", "tiff"])
+        self.assertEqual(info.abi_version, excelize.abi_version)
+        # The result types of all functions used by the wrapper are defined
+        with open(excelize.__file__, encoding="utf-8") as file:
+            used = set(re.findall(r"\blib\.([A-Z]\w+)", file.read()))
+        self.assertEqual(used - set(excelize.restypes), set())
+        self.assertIsNone(excelize.check_abi_version())
+        excelize.abi_version += 1
+        try:
+            with self.assertRaises(ImportError) as context:
+                excelize.check_abi_version()
+        finally:
+            excelize.abi_version -= 1
+        self.assertEqual(
+            str(context.exception),
+            "the shared library doesn't match the excelize Python package "
+            f"{excelize.__version__}, expected ABI version "
+            f"{excelize.abi_version + 1}, got {excelize.abi_version}",
+        )
+        # The missing functions, such as the GetBuildInfo of the earlier shared
+        # libraries, are reported without calling them
+        excelize.restypes["NotExist"] = types_go._ErrorResult
+        try:
+            with self.assertRaises(ImportError) as context:
+                excelize.check_abi_version()
+        finally:
+            del excelize.restypes["NotExist"]
+        self.assertEqual(
+            str(context.exception),
+            "the shared library doesn't match the excelize Python package "
+            f"{excelize.__version__}, missing functions: NotExist",
+        )
+
+    def test_get_range(self):
+        f = excelize.new_file()
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["A1", "B1", 1.25]))
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A2", ["A2", None, "C2"]))
+        self.assertIsNone(f.merge_cell("Sheet1", "A1", "A2"))
+        self.assertEqual(f.new_sheet("Sheet 2"), 1)
+        self.assertIsNone(f.set_cell_value("Sheet 2", "B2", "value"))
+        for defined_name in [
+            excelize.DefinedName(
+                name="Block", refers_to="'Sheet 2'!$B$2:$C$2", scope="Workbook"
+            ),
+            excelize.DefinedName(
+                name="Block", refers_to="Sheet1!$B$1:$C$1", scope="Sheet1"
+            ),
+        ]:
+            self.assertIsNone(f.set_defined_name(defined_name))
+        self.assertEqual(
+            f.get_range("Sheet1", "C2:A1"),
+            [["A1", "B1", "1.25"], ["A1", "", "C2"]],
+        )
+        self.assertEqual(f.get_range("Sheet1", "B1"), [["B1"]])
+        self.assertEqual(f.get_range("Sheet1", "A3:B4"), [["", ""], ["", ""]])
+        self.assertEqual(f.get_range("Sheet1", "block"), [["B1", "1.25"]])
+        self.assertEqual(f.get_range("Sheet 2", "Block"), [["value", ""]])
+        # The whole column and row references are clamped to the used range
+        self.assertEqual(f.get_range("Sheet1", "C:B"), [["B1", "1.25"], ["", "C2"]])
+        self.assertEqual(f.get_range("Sheet1", "$2:$2"), [["A1", "", "C2"]])
+        self.assertEqual(f.get_range("Sheet 2", "A:A"), [[""], [""]])
+        self.assertEqual(f.get_range("Sheet 2", "3:4"), [["", ""], ["", ""]])
+        self.assertIsNone(
+            f.set_defined_name(
+                excelize.DefinedName(
+                    name="Amounts", refers_to="Sheet1!$C:$C", scope="Workbook"
+                )
+            )
+        )
+        self.assertEqual(f.get_range("Sheet 2", "Amounts"), [["1.25"], ["C2"]])
+        self.assertEqual(
+            [[cell.value for cell in row] for row in f.get_typed_rows("Sheet1", "1:1")],
+            [["A1", "B1", 1.25]],
+        )
+        self.assertEqual(f.get_range("Sheet1", "A:A"), f.get_range("Sheet1", "A1:A2"))
+        for range_ref, err in [
+            (
+                "A:XFE",
+                "the column number must be greater than or equal to 1 and less "
+                "than or equal to 16384",
+            ),
+            ("0:1", "invalid cell reference [1, 0]"),
+        ]:
+            with self.assertRaises(RuntimeError) as context:
+                f.get_range("Sheet1", range_ref)
+            self.assertEqual(str(context.exception), err)
+        self.assertEqual(
+            f.get_range("Sheet1", "C1:C1", excelize.Options(raw_cell_value=True)),
+            [["1.25"]],
+        )
+        self.assertEqual(
+            [[cell.raw for cell in row] for row in f.get_typed_rows("Sheet1", "Block")],
+            [["B1", "1.25"]],
+        )
+        with self.assertRaises(RuntimeError) as context:
+            f.get_range("SheetN", "A1:B2")
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(RuntimeError) as context:
+            f.get_range("Sheet1", "A1:B2:C3")
+        self.assertEqual(str(context.exception), "parameter is invalid")
+        with self.assertRaises(TypeError) as context:
+            f.get_range("Sheet1", 1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type str for argument 'range_ref', but got int",
+        )
+        self.assertIsNone(f.close())
+
+    def test_set_range(self):
+        f = excelize.new_file()
+        style = f.new_style(excelize.Style(font=excelize.Font(bold=True)))
+        self.assertIsNone(
+            f.set_range("Sheet1", "B2", [[1, "a", True], [2.5], [None, None, "c"]])
+        )
+        self.assertEqual(
+            f.get_range("Sheet1", "B2:D4"),
+            [["1", "a", "TRUE"], ["2.5", "", ""], ["", "", "c"]],
+        )
+        self.assertEqual(f.get_cell_style("Sheet1", "D4"), 0)
+        self.assertIsNone(f.set_range("Sheet1", "B3", [["x", "y"]], style))
+        self.assertEqual(f.get_range("Sheet1", "B3:D3"), [["x", "y", ""]])
+        self.assertEqual(
+            [f.get_cell_style("Sheet1", cell) for cell in ["B3", "C3", "D3"]],
+            [style, style, 0],
+        )
+        self.assertIsNone(f.set_range("Sheet1", "A1", []))
+        with self.assertRaises(RuntimeError) as context:
+            f.set_range("SheetN", "A1", [[1]])
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(RuntimeError) as context:
+            f.set_range("Sheet1", "A", [[1]])
+        self.assertEqual(
+            str(context.exception),
+            'cannot convert cell "A" to coordinates: invalid cell name "A"',
+        )
+        with self.assertRaises(TypeError) as context:
+            f.set_range("Sheet1", "A1", [[1], 2])
+        self.assertEqual(
+            str(context.exception),
+            "expected type list for argument 'values', but got int",
+        )
+        with self.assertRaises(TypeError) as context:
+            f.set_range("Sheet1", "A1", [[1]], "1")
+        self.assertEqual(
+            str(context.exception),
+            "expected type int for argument 'style_id', but got str",
+        )
+        self.assertIsNone(f.close())
+
+    def test_typed_rows(self):
+        f = excelize.new_file()
+        self.assertIsNone(
+            f.set_sheet_row(
+                "Sheet1", "A1", [1.5, True, "text", datetime.datetime(2024, 1, 2)]
+            )
+        )
+        self.assertIsNone(f.set_cell_value("Sheet1", "B3", 2))
+        rows = f.get_typed_rows("Sheet1")
+        self.assertEqual([len(row) for row in rows], [4, 0, 2])
+        self.assertEqual(
+            rows[0],
+            [
+                excelize.TypedCell(
+                    cell_type=excelize.CellType.CellTypeUnset,
+                    value=1.5,
+                    raw="1.5",
+                    formatted="1.5",
+                ),
+                excelize.TypedCell(
+                    cell_type=excelize.CellType.CellTypeBool,
+                    value=True,
+                    raw="1",
+                    formatted="TRUE",
+                ),
+                excelize.TypedCell(
+                    cell_type=excelize.CellType.CellTypeSharedString,
+                    value="text",
+                    raw="text",
+                    formatted="text",
+                ),
+                excelize.TypedCell(
+                    cell_type=excelize.CellType.CellTypeUnset,
+                    value=datetime.datetime(2024, 1, 2),
+                    raw="45293",
+                    formatted=f.get_cell_value("Sheet1", "D1"),
+                    style_id=1,
+                ),
+            ],
+        )
+        self.assertEqual(
+            f.get_typed_rows("Sheet1", "B3:A2"),
+            [
+                [excelize.TypedCell(), excelize.TypedCell()],
+                [
+                    excelize.TypedCell(),
+                    excelize.TypedCell(value=2.0, raw="2", formatted="2"),
+                ],
+            ],
+        )
+        # The style of the row and the column are used for the cell without
+        # style, reading the styles doesn't add empty rows and cells
+        style_id = f.new_style(excelize.Style(num_fmt=2))
+        self.assertIsNone(f.set_col_style("Sheet1", "H", style_id))
+        self.assertIsNone(
+            f.set_row_style("Sheet1", 5, 5, f.new_style(excelize.Style(num_fmt=3)))
+        )
+        rows = f.get_typed_rows("Sheet1", "G4:H20")
+        self.assertEqual([cell.style_id for cell in rows[0]], [0, style_id])
+        self.assertEqual([cell.style_id for cell in rows[1]], [style_id + 1] * 2)
+        path = os.path.join("test", "TestTypedRows.xlsx")
+        self.assertIsNone(f.save_as(path))
+        with zipfile.ZipFile(path) as zf:
+            sheet_xml = zf.read("xl/worksheets/sheet1.xml")
+        self.assertEqual(re.findall(rb'<row r="(\d+)"', sheet_xml)[-1], b"5")
+        for cell in [b"G4", b"H4", b"G5", b"H20"]:
+            self.assertNotIn(b'r="' + cell + b'"', sheet_xml)
+        with self.assertRaises(RuntimeError) as context:
+            f.get_typed_rows("SheetN")
+        self.assertEqual(str(context.exception), "sheet SheetN does not exist")
+        with self.assertRaises(RuntimeError) as context:
+            f.get_typed_rows("Sheet1", "A1:B2:C3")
+        self.assertEqual(str(context.exception), "parameter is invalid")
+        with self.assertRaises(TypeError) as context:
+            f.get_typed_rows("Sheet1", 1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type str for argument 'range_ref', but got int",
+        )
+        self.assertIsNone(f.close())
+
+    def test_table_rows(self):
+        path = os.path.join("test", "TestTableRows.xlsx")
+        f = excelize.new_file()
+        self.assertEqual(f.new_sheet("Sheet2"), 1)
+        self.assertIsNone(f.set_sheet_row("Sheet2", "B2", ["Name", "Amount", "Paid"]))
+        self.assertIsNone(f.set_sheet_row("Sheet2", "B3", ["a", 1.5, True]))
+        self.assertIsNone(f.set_sheet_row("Sheet2", "B4", ["b", 2]))
+        self.assertIsNone(f.set_sheet_row("Sheet2", "B5", ["Total", 3.5]))
+        self.assertIsNone(
+            f.add_table("Sheet2", excelize.Table(name="Sales", range="B2:D4"))
+        )
+        self.assertIsNone(f.save_as(path))
+        self.assertIsNone(f.close())
+        # Add the totals row to the table
+        with zipfile.ZipFile(path) as zf:
+            parts = {name: zf.read(name) for name in zf.namelist()}
+        parts["xl/tables/table1.xml"] = parts["xl/tables/table1.xml"].replace(
+            b'ref="B2:D4"><', b'ref="B2:D5" totalsRowCount="1"><', 1
+        )
+        with zipfile.ZipFile(path, "w") as zf:
+            for name, content in parts.items():
+                zf.writestr(name, content)
+
+        f = excelize.open_file(path)
+        table = f.get_table_rows("sales")
+        self.assertEqual(table.sheet, "Sheet2")
+        self.assertEqual(table.range, "B2:D5")
+        self.assertEqual(table.header, ["Name", "Amount", "Paid"])
+        self.assertEqual(
+            [[cell.value for cell in row] for row in table.rows],
+            [["a", 1.5, True], ["b", 2.0, None]],
+        )
+        self.assertEqual(table.rows[0][2].cell_type, excelize.CellType.CellTypeBool)
+        with self.assertRaises(excelize.ExcelizeError) as context:
+            f.get_table_rows("Table1")
+        self.assertEqual(str(context.exception), "table Table1 does not exist")
+        self.assertEqual(context.exception.code, excelize.ErrorCode.ErrCodeNotExist)
+        with self.assertRaises(TypeError):
+            f.get_table_rows(1)
+        self.assertIsNone(f.close())
+
     def test_cell_rich_text(self):
         f = excelize.new_file()
         self.assertIsNone(f.set_row_height("Sheet1", 1, 35))
@@ -2663,10 +3587,25 @@ class TestExcelize(unittest.TestCase):
             excelize.CustomProperty(
                 name="Date Prop", value=datetime.datetime(2016, 8, 30, 11, 51, 0)
             ),
+            excelize.CustomProperty(
+                name="Aware Date Prop",
+                value=datetime.datetime(
+                    2048,
+                    8,
+                    30,
+                    11,
+                    51,
+                    0,
+                    tzinfo=datetime.timezone(datetime.timedelta(hours=-5)),
+                ),
+            ),
         ]
         for prop in props:
             f.set_custom_props(prop)
         self.assertEqual(props, f.get_custom_props())
+        with self.assertRaises(RuntimeError) as context:
+            f.set_custom_props(excelize.CustomProperty(name="Prop", value=2**40))
+        self.assertEqual(str(context.exception), "parameter is invalid")
 
         with self.assertRaises(RuntimeError) as context:
             f.set_custom_props(excelize.CustomProperty(name=None, value=1))
@@ -2809,6 +3748,45 @@ class TestExcelize(unittest.TestCase):
             "expected type str for argument 'col', but got int",
         )
 
+    def test_list_handles(self):
+        f = excelize.new_file()
+        rows = f.rows("Sheet1")
+        sw = f.new_stream_writer("Sheet1")
+        cols = f.cols("Sheet1")
+        handles = excelize.list_handles()
+        self.assertIn(f.file_index, handles.files)
+        self.assertIn(rows.index, handles.rows)
+        self.assertIn(sw.sw_index, handles.stream_writers)
+        self.assertIn(cols.index, handles.cols)
+
+        self.assertIsNone(rows.close())
+        self.assertIsNone(sw.flush())
+        self.assertIsNone(cols.close())
+        self.assertIsNone(f.close())
+        handles = excelize.list_handles()
+        self.assertNotIn(f.file_index, handles.files or [])
+        self.assertNotIn(rows.index, handles.rows or [])
+        self.assertNotIn(sw.sw_index, handles.stream_writers or [])
+        self.assertNotIn(cols.index, handles.cols or [])
+
+        g = excelize.new_file()
+        self.assertNotEqual(g.file_index, f.file_index)
+        with self.assertRaises(RuntimeError) as context:
+            f.get_cell_value("Sheet1", "A1")
+        self.assertEqual(str(context.exception), "file pointer has been released")
+        self.assertEqual(str(f.close()), "file pointer has been released")
+        with self.assertRaises(RuntimeError) as context:
+            rows.next()
+        self.assertEqual(
+            str(context.exception), "rows iterator pointer has been released"
+        )
+        with self.assertRaises(RuntimeError) as context:
+            sw.flush()
+        self.assertEqual(
+            str(context.exception), "stream writer pointer has been released"
+        )
+        self.assertIsNone(g.close())
+
     def test_merge_cell(self):
         f = excelize.new_file()
         self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["A1", "B1"]))
@@ -3023,6 +4001,379 @@ class TestExcelize(unittest.TestCase):
         self.assertIsNone(f.save_as(os.path.join("test", "TestWorkbookProps.xlsx")))
         self.assertIsNone(f.close())
 
+    def test_free_result(self):
+        f = excelize.new_file()
+        self.assertIsNone(f.set_sheet_row("Sheet1", "A1", ["A", "B", "C"]))
+        excelize.lib.GetRows.restype = types_go._StringMatrixErrorResult
+        res = excelize.lib.GetRows(f.file_index, "Sheet1".encode(), None)
+        self.assertEqual(res.RowLen, 1)
+        self.assertEqual(res.Row[0].CellLen, 3)
+        self.assertIsNone(excelize.free_result(res))
+        self.assertEqual(res.RowLen, 0)
+        self.assertFalse(res.Row)
+        self.assertIsNone(res.err)
+        # Release the result which has been released is a no-op
+        self.assertIsNone(excelize.free_result(res))
+        self.assertIsNone(f.close())
+
+    def test_error_code(self):
+        f = excelize.new_file()
+        for func, args, error_class, code in [
+            (
+                f.get_cell_value,
+                ["SheetN", "A1"],
+                excelize.SheetNotExistError,
+                excelize.ErrorCode.ErrCodeSheetNotExist,
+            ),
+            (
+                f.set_cell_value,
+                ["SheetN", "A1", 1],
+                excelize.SheetNotExistError,
+                excelize.ErrorCode.ErrCodeSheetNotExist,
+            ),
+            (
+                f.get_cell_value,
+                ["Sheet1", "A"],
+                excelize.CoordinatesError,
+                excelize.ErrorCode.ErrCodeCoordinates,
+            ),
+            (
+                f.set_row_height,
+                ["Sheet1", 0, 10],
+                excelize.CoordinatesError,
+                excelize.ErrorCode.ErrCodeCoordinates,
+            ),
+            (
+                f.get_range,
+                ["Sheet1", "A1:B2:C3"],
+                excelize.ParameterError,
+                excelize.ErrorCode.ErrCodeParameterInvalid,
+            ),
+            (
+                f.set_sheet_name,
+                ["Sheet1", ""],
+                excelize.ExcelizeError,
+                excelize.ErrorCode.ErrCodeSheetName,
+            ),
+            (
+                excelize.open_file,
+                [os.path.join("test", "NotExist.xlsx")],
+                excelize.ExcelizeError,
+                excelize.ErrorCode.ErrCodeFileNotExist,
+            ),
+        ]:
+            with self.assertRaises(error_class) as context:
+                func(*args)
+            self.assertIsInstance(context.exception, RuntimeError)
+            self.assertEqual(context.exception.code, code)
+        self.assertIsNone(f.close())
+        with self.assertRaises(excelize.HandleError) as context:
+            f.get_cell_value("Sheet1", "A1")
+        self.assertEqual(
+            context.exception.code, excelize.ErrorCode.ErrCodeHandleReleased
+        )
+        for message, error_class, code in [
+            (
+                "the supplied open workbook password is not correct",
+                excelize.WorkbookPasswordError,
+                excelize.ErrorCode.ErrCodeWorkbookPassword,
+            ),
+            (
+                "can not find file pointer",
+                excelize.HandleError,
+                excelize.ErrorCode.ErrCodeHandleNotFound,
+            ),
+            ("unknown", excelize.ExcelizeError, excelize.ErrorCode.ErrCodeUnknown),
+        ]:
+            err = excelize.new_error(message, code)
+            self.assertIs(type(err), error_class)
+            self.assertEqual(err.code, code)
+        self.assertEqual(
+            excelize.new_error("unknown", 100).code, excelize.ErrorCode.ErrCodeUnknown
+        )
+
+    def test_recover_panic(self):
+        f = excelize.new_file()
+        expected = "runtime error: invalid memory address or nil pointer dereference"
+        excelize.lib.SetSheetView.restype = types_go._ErrorResult
+        res = excelize.lib.SetSheetView(f.file_index, "Sheet1".encode(), 0, None)
+        try:
+            self.assertEqual(res.ErrCode, excelize.ErrorCode.ErrCodePanic)
+            self.assertTrue(res.err.decode().startswith(expected))
+            self.assertIn("main.SetSheetView", res.err.decode())
+        finally:
+            excelize.free_result(res)
+        excelize.lib.NewStyle.restype = types_go._IntErrorResult
+        res = excelize.lib.NewStyle(f.file_index, None)
+        try:
+            self.assertEqual(res.val, 0)
+            self.assertEqual(res.ErrCode, excelize.ErrorCode.ErrCodePanic)
+            self.assertTrue(res.err.decode().startswith(expected))
+        finally:
+            excelize.free_result(res)
+        # The workbook is still usable after the panic was recovered
+        self.assertIn(f.file_index, excelize.list_handles().files)
+        self.assertIsNone(f.set_cell_value("Sheet1", "A1", 1))
+        self.assertEqual(f.get_cell_value("Sheet1", "A1"), "1")
+        self.assertIsNone(f.close())
+
+    def test_locking(self):
+        f = excelize.new_file()
+        errors = []
+
+        def write(col: str):
+            try:
+                for row in range(1, 51):
+                    f.set_cell_value("Sheet1", f"{col}{row}", row)
+                    f.insert_rows("Sheet2", 1, 1)
+            except Exception as err:
+                errors.append(err)
+
+        def read():
+            try:
+                for row in range(1, 51):
+                    f.get_cell_value("Sheet1", f"A{row}")
+                    f.get_rows("Sheet1")
+                    f.calc_cell_value("Sheet1", "A1")
+            except Exception as err:
+                errors.append(err)
+
+        self.assertEqual(f.new_sheet("Sheet2"), 1)
+        threads = [threading.Thread(target=write, args=(col,)) for col in "ABC"]
+        threads += [threading.Thread(target=read) for _ in range(3)]
+        for thread in threads:
+            thread.start()
+        for thread in threads:
+            thread.join()
+        self.assertEqual(errors, [])
+        self.assertEqual(len(f.get_rows("Sheet1")), 50)
+        self.assertEqual(f.get_cell_value("Sheet1", "C50"), "50")
+        self.assertEqual(f.get_sheet_dimension("Sheet2"), "A1")
+        self.assertIsNone(f.save_as(os.path.join("test", "TestLocking.xlsx")))
+
+        # Disable the lock for single-threaded use
+        self.assertIsNone(f.set_locking(False))
+        self.assertIsNone(f.set_cell_value("Sheet1", "D1", 1))
+        self.assertEqual(f.get_cell_value("Sheet1", "D1"), "1")
+        self.assertIsNone(f.set_locking(True))
+        with self.assertRaises(TypeError) as context:
+            f.set_locking(1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type bool for argument 'enabled', but got int",
+        )
+        self.assertIsNone(f.close())
+
+    def test_locking_parent(self):
+        f = excelize.new_file()
+        self.assertIsNone(f.set_cell_value("Sheet1", "A1", 1))
+        self.assertEqual(f.new_sheet("Sheet2"), 1)
+        sw = f.new_stream_writer("Sheet2")
+        rows = f.rows("Sheet1")
+        threads = [
+            threading.Thread(target=sw.set_row, args=("A1", [1])),
+            threading.Thread(target=rows.next),
+        ]
+        blocked = []
+
+        def callback(event: excelize.ProgressEvent):
+            # The stream writer and the iterator wait for the workbook, which
+            # is locked while saving it
+            if not event.done and not blocked:
+                for thread in threads:
+                    thread.start()
+                    thread.join(0.2)
+                    blocked.append(thread.is_alive())
+
+        self.assertIsNone(f.set_progress_callback(callback))
+        self.assertIsNone(f.save_as(os.path.join("test", "TestLockingParent.xlsx")))
+        for thread in threads:
+            thread.join()
+        self.assertEqual(blocked, [True, True])
+        self.assertEqual(rows.columns(), ["1"])
+        self.assertIsNone(rows.close())
+        self.assertIsNone(sw.flush())
+        self.assertEqual(f.get_cell_value("Sheet2", "A1"), "1")
+        self.assertIsNone(f.close())
+
+    def test_progress_callback(self):
+        f = excelize.new_file()
+        events: List[excelize.ProgressEvent] = []
+        self.assertIsNone(f.set_progress_callback(events.append))
+        self.assertIsNone(f.set_cell_formula("Sheet1", "A1", "=1+2"))
+        self.assertEqual(f.calc_cell_value("Sheet1", "A1"), "3")
+        self.assertEqual(
+            events[-1],
+            excelize.ProgressEvent(
+                phase=excelize.ProgressPhase.ProgressCalc, sheet="Sheet1", done=True
+            ),
+        )
+
+        # Report the number of bytes written when saving the workbook
+        events.clear()
+        path = os.path.join("test", "TestProgressCallback.xlsx")
+        canceled_path = os.path.join("test", "TestProgressCallbackCanceled.xlsx")
+        self.assertIsNone(f.save_as(path))
+        size = os.path.getsize(path)
+        self.assertTrue(
+            all(e.phase == excelize.ProgressPhase.ProgressSave for e in events)
+        )
+        self.assertEqual(
+            [events[0].step, events[-1].step],
+            [
+                excelize.ProgressStep.ProgressStepSerialize,
+                excelize.ProgressStep.ProgressStepWrite,
+            ],
+        )
+        self.assertEqual(events[0].bytes_processed, 0)
+        self.assertTrue(events[-1].done)
+        self.assertEqual(events[-1].bytes_processed, size)
+        self.assertEqual(events[-1].bytes_total, size)
+        # Report the serialize step in chunks for the large workbook
+        f2 = excelize.new_file()
+        self.assertIsNone(
+            f2.set_range("Sheet1", "A1", [[i, i * 2, i * 3] for i in range(20000)])
+        )
+        self.assertIsNone(f2.set_progress_callback(events.append))
+        events.clear()
+        self.assertIsNone(f2.save_as(path))
+        self.assertTrue(
+            any(
+                e.step == excelize.ProgressStep.ProgressStepSerialize
+                and e.bytes_processed > 0
+                for e in events
+            )
+        )
+        self.assertIsNone(
+            f2.set_progress_callback(lambda event: event.bytes_processed > 0)
+        )
+        with self.assertRaises(excelize.CanceledError):
+            f2.save_as(canceled_path)
+        self.assertFalse(os.path.exists(canceled_path))
+        self.assertIsNone(f2.close())
+        # Save the workbook without the origin path
+        events.clear()
+        f2 = excelize.new_file()
+        self.assertIsNone(f2.set_progress_callback(events.append))
+        with self.assertRaises(RuntimeError) as context:
+            f2.save()
+        self.assertEqual(
+            str(context.exception),
+            "no path defined for file, consider File.WriteTo or File.Write",
+        )
+        self.assertEqual(events, [])
+        self.assertIsNone(f2.close())
+
+        # Report the rows written by the stream writer when flushing it
+        events.clear()
+        sw = f.new_stream_writer("Sheet1")
+        for row in range(1, 4):
+            self.assertIsNone(sw.set_row(f"A{row}", [row]))
+        self.assertIsNone(sw.flush())
+        self.assertEqual(
+            [(e.phase, e.step, e.sheet, e.rows_processed, e.done) for e in events],
+            [
+                (
+                    excelize.ProgressPhase.ProgressFlush,
+                    excelize.ProgressStep.ProgressStepSerialize,
+                    "Sheet1",
+                    3,
+                    False,
+                ),
+                (
+                    excelize.ProgressPhase.ProgressFlush,
+                    excelize.ProgressStep.ProgressStepSerialize,
+                    "Sheet1",
+                    3,
+                    True,
+                ),
+            ],
+        )
+
+        # Cancel the operations by the callback
+        for callback in [
+            lambda event: True,
+            lambda event: event.step == excelize.ProgressStep.ProgressStepWrite,
+            lambda event: event.bytes_processed > 0,
+            lambda event: 1 / 0,
+        ]:
+            self.assertIsNone(f.set_progress_callback(callback))
+            with self.assertRaises(excelize.CanceledError) as context:
+                f.save_as(canceled_path)
+            self.assertEqual(
+                str(context.exception), "operation canceled by the progress callback"
+            )
+            self.assertEqual(context.exception.code, excelize.ErrorCode.ErrCodeCanceled)
+            self.assertFalse(os.path.exists(canceled_path))
+        self.assertEqual(f.new_sheet("Sheet2"), 1)
+        sw = f.new_stream_writer("Sheet2")
+        with self.assertRaises(excelize.CanceledError):
+            sw.flush()
+        # The stream writer is still available after the flushing was canceled
+        self.assertIsNone(sw.set_row("A1", [1]))
+        self.assertIsNone(f.set_progress_callback(None))
+        self.assertIsNone(f.save_as(path))
+        with self.assertRaises(TypeError) as context:
+            f.set_progress_callback(1)
+        self.assertEqual(
+            str(context.exception),
+            "expected type Callable for argument 'callback', but got int",
+        )
+        self.assertIsNone(f.close())
+        with self.assertRaises(excelize.HandleError):
+            f.set_progress_callback(events.append)
+
+        # Report the number of bytes read when opening the workbook
+        events.clear()
+        size = os.path.getsize(path)
+        f = excelize.open_file(path, progress=events.append)
+        self.assertTrue(
+            all(e.phase == excelize.ProgressPhase.ProgressOpen for e in events)
+        )
+        self.assertEqual(
+            [(e.step, e.bytes_processed, e.bytes_total, e.done) for e in events],
+            [
+                (excelize.ProgressStep.ProgressStepRead, 0, size, False),
+                (excelize.ProgressStep.ProgressStepParse, 0, size, False),
+                (excelize.ProgressStep.ProgressStepParse, size, size, True),
+            ],
+        )
+        # The callback was registered for the opened workbook
+        events.clear()
+        self.assertIsNone(f.save())
+        self.assertTrue(
+            all(e.phase == excelize.ProgressPhase.ProgressSave for e in events)
+        )
+        self.assertEqual(events[-1].step, excelize.ProgressStep.ProgressStepWrite)
+        self.assertEqual(events[-1].bytes_processed, os.path.getsize(path))
+        self.assertIsNone(f.close())
+        with open(path, "rb") as file:
+            buffer = file.read()
+        events.clear()
+        f, size = excelize.open_reader(buffer, progress=events.append), len(buffer)
+        self.assertEqual(
+            [(e.step, e.bytes_processed, e.bytes_total, e.done) for e in events],
+            [
+                (excelize.ProgressStep.ProgressStepParse, 0, size, False),
+                (excelize.ProgressStep.ProgressStepParse, size, size, True),
+            ],
+        )
+        self.assertIsNone(f.close())
+        f = excelize.open_reader(buffer)
+        self.assertIsNone(f.close())
+        # Cancel opening the workbook before and after reading it
+        for callback in [
+            lambda event: True,
+            lambda event: event.step == excelize.ProgressStep.ProgressStepParse,
+        ]:
+            with self.assertRaises(excelize.CanceledError):
+                excelize.open_file(path, progress=callback)
+            with self.assertRaises(excelize.CanceledError):
+                excelize.open_reader(buffer, progress=callback)
+        with self.assertRaises(excelize.HandleError) as context:
+            f.set_locking(False)
+        self.assertEqual(str(context.exception), "file pointer has been released")
+
     def test_type_convert(self):
         class _T2(Structure):
             _fields_ = [
@@ -3066,3 +4417,68 @@ class TestExcelize(unittest.TestCase):
             str(context.exception),
             "unsupported interface type code: -1",
         )
+        for value in [
+            2**40,
+            -(2**63),
+            2**64 - 1,
+            datetime.datetime(2048, 8, 30, 11, 51, 0, 123456),
+            datetime.datetime(2016, 8, 30, 11, 51, 0, tzinfo=ZoneInfo("Asia/Shanghai")),
+            datetime.datetime(
+                2016,
+                8,
+                30,
+                11,
+                51,
+                0,
+                tzinfo=datetime.timezone(datetime.timedelta(hours=-5)),
+            ),
+            datetime.timedelta(days=2, hours=3, microseconds=5),
+        ]:
+            self.assertEqual(
+                excelize.c_value_to_py_interface(
+                    excelize.py_value_to_c_interface(value)
+                ),
+                value,
+            )
+        naive = datetime.datetime(2016, 8, 30, 11, 51, 0, 123456)
+        interface = excelize.py_datetime_to_interface(naive)
+        self.assertEqual(interface.location, "Local")
+        self.assertEqual(interface.integer, int(naive.timestamp()))
+        self.assertEqual(interface.nanosecond, 123456000)
+        for location, expected in [
+            (None, datetime.datetime(1970, 1, 2)),
+            (b"UTC", datetime.datetime.fromtimestamp(86400)),
+            (b"Local", datetime.datetime.fromtimestamp(86400)),
+        ]:
+            val = types_go._Interface(Type=6, Integer=86400, Location=location)
+            self.assertEqual(excelize.c_value_to_py_interface(val), expected)
+        with self.assertRaises(OverflowError) as context:
+            excelize.py_value_to_c_interface(2**64)
+        self.assertEqual(
+            str(context.exception),
+            "integer 18446744073709551616 out of 64-bit range",
+        )
+        for value, c_type, err in [
+            (2**31, c_int, "integer 2147483648 out of 32-bit range"),
+            (-(2**31) - 1, c_int, "integer -2147483649 out of 32-bit range"),
+            (-1, c_uint, "integer -1 out of unsigned 32-bit range"),
+            (256, c_ubyte, "integer 256 out of unsigned 8-bit range"),
+            (1e39, c_float, "float 1e+39 out of 32-bit range"),
+        ]:
+            with self.assertRaises(OverflowError) as context:
+                excelize.py_to_base_ctype(value, c_type)
+            self.assertEqual(str(context.exception), err)
+        self.assertEqual(excelize.py_to_base_ctype(2**32 - 1, c_uint).value, 2**32 - 1)
+        self.assertEqual(excelize.py_to_base_ctype(-(2**31), c_int).value, -(2**31))
+        f = excelize.new_file()
+        with self.assertRaises(OverflowError) as context:
+            f.new_style(excelize.Style(num_fmt=2**31))
+        self.assertEqual(
+            str(context.exception), "integer 2147483648 out of 32-bit range"
+        )
+        with self.assertRaises(OverflowError) as context:
+            f.set_calc_props(excelize.CalcPropsOptions(iterate_count=-1))
+        self.assertEqual(
+            str(context.exception), "integer -1 out of unsigned 32-bit range"
+        )
+        self.assertIsNone(f.close())
@@ -15,14 +15,42 @@
 #include <stdlib.h>
 #include <time.h>
 
+// ErrorCode defines the stable numeric codes of the errors returned by the
+// functions, the code is stored in the ErrCode field of each result structure.
+enum ErrorCode
+{
+    ErrCodeNone = 0,
+    ErrCodeUnknown = 1,
+    ErrCodePanic = 2,
+    ErrCodeHandleNotFound = 3,
+    ErrCodeHandleReleased = 4,
+    ErrCodeFileNotExist = 5,
+    ErrCodeSheetNotExist = 6,
+    ErrCodeParameterInvalid = 7,
+    ErrCodeParameterRequired = 8,
+    ErrCodeCoordinates = 9,
+    ErrCodeSheetName = 10,
+    ErrCodeWorkbookPassword = 11,
+    ErrCodeProtection = 12,
+    ErrCodeUnsupported = 13,
+    ErrCodeExceedsLimit = 14,
+    ErrCodeNotExist = 15,
+    ErrCodeCanceled = 16,
+    ErrCodeTimeout = 17
+};
+
 struct Interface
 {
     int Type;
-    int Integer;
+    long long Integer;
     int32_t Integer32;
     char *String;
     double Float64;
     bool Boolean;
+    unsigned long long UInteger64;
+    int Nanosecond;
+    int Offset;
+    char *Location;
 };
 
 // Options define the options for opening and reading the spreadsheet.
@@ -116,14 +144,6 @@ struct CustomProperty
     struct Interface Value;
 };
 
-// Cell can be used directly in StreamWriter.SetRow to specify a style and
-// a value.
-struct Cell
-{
-    int StyleID;
-    char *Formula;
-    struct Interface Value;
-};
 
 // DataValidation directly maps the settings of the data validation rule.
 struct DataValidation {
@@ -378,6 +398,18 @@ struct RichTextRun
     char *Text;
 };
 
+// Cell can be used directly in StreamWriter.SetRow to specify a style, a
+// formula and a value. The value will be the rich text runs if the RichText
+// is not empty.
+struct Cell
+{
+    int StyleID;
+    char *Formula;
+    struct Interface Value;
+    int RichTextLen;
+    struct RichTextRun *RichText;
+};
+
 // Comment directly maps the comment information.
 struct Comment
 {
@@ -829,9 +861,16 @@ struct WorkbookProtectionOptions
     bool LockWindows;
 };
 
+struct ErrorResult
+{
+    int ErrCode;
+    char *err;
+};
+
 struct StringErrorResult
 {
     char *val;
+    int ErrCode;
     char *err;
 };
 
@@ -839,24 +878,35 @@ struct StringIntErrorResult
 {
     char *strVal;
     int intVal;
+    int ErrCode;
     char *err;
 };
 
 struct IntErrorResult
 {
     int val;
+    int ErrCode;
     char *err;
 };
 
 struct BoolErrorResult
 {
     bool val;
+    int ErrCode;
     char *err;
 };
 
 struct Float64ErrorResult
 {
     double val;
+    int ErrCode;
+    char *err;
+};
+
+struct InterfaceErrorResult
+{
+    struct Interface val;
+    int ErrCode;
     char *err;
 };
 
@@ -864,6 +914,15 @@ struct StringArrayErrorResult
 {
     int ArrLen;
     char **Arr;
+    int ErrCode;
+    char *Err;
+};
+
+struct BytesErrorResult
+{
+    int ArrLen;
+    unsigned char *Arr;
+    int ErrCode;
     char *Err;
 };
 
@@ -876,6 +935,7 @@ struct IntStringResult
 struct GetCalcPropsResult
 {
     struct CalcPropsOptions opts;
+    int ErrCode;
     char *err;
 };
 
@@ -883,6 +943,7 @@ struct GetCellHyperLinkResult
 {
     bool link;
     char *target;
+    int ErrCode;
     char *err;
 };
 
@@ -890,12 +951,14 @@ struct CellNameToCoordinatesResult
 {
     int col;
     int row;
+    int ErrCode;
     char *err;
 };
 
 struct GetAppPropsResult
 {
     struct AppProperties opts;
+    int ErrCode;
     char *err;
 };
 
@@ -909,13 +972,42 @@ struct StringMatrixErrorResult
 {
     int RowLen;
     struct Cells *Row;
+    int RowNumLen;
+    int *RowNum;
+    int RowOptsLen;
+    struct RowOpts *RowOpts;
+    int ErrCode;
     char *err;
 };
 
+struct TypedCell
+{
+    int CellType;
+    struct Interface Value;
+    char *Raw;
+    char *Formatted;
+    int StyleID;
+};
+
+struct TypedCells
+{
+    int CellLen;
+    struct TypedCell *Cell;
+};
+
+struct TypedCellMatrixErrorResult
+{
+    int RowLen;
+    struct TypedCells *Row;
+    int ErrCode;
+    char *Err;
+};
+
 struct GetCellRichTextResult
 {
     int RunsLen;
     struct RichTextRun *Runs;
+    int ErrCode;
     char *Err;
 };
 
@@ -923,6 +1015,7 @@ struct GetDataValidationsResult
 {
     int DvsLen;
     struct DataValidation *Dvs;
+    int ErrCode;
     char *Err;
 };
 
@@ -930,37 +1023,55 @@ struct GetSlicersResult
 {
     int SlicersLen;
     struct SlicerOptions *Slicers;
+    int ErrCode;
     char *Err;
 };
 
 struct GetStyleResult
 {
     struct Style style;
+    int ErrCode;
     char *err;
 };
 
+struct GetTableRowsResult
+{
+    char *Sheet;
+    char *Range;
+    int HeaderLen;
+    char **Header;
+    int RowLen;
+    struct TypedCells *Row;
+    int ErrCode;
+    char *Err;
+};
+
 struct GetTablesResult
 {
     int TablesLen;
     struct Table *Tables;
+    int ErrCode;
     char *Err;
 };
 
 struct GetWorkbookPropsResult
 {
     struct WorkbookPropsOptions opts;
+    int ErrCode;
     char *err;
 };
 
 struct GetSheetPropsResult
 {
     struct SheetPropsOptions opts;
+    int ErrCode;
     char *err;
 };
 
 struct GetSheetProtectionResult
 {
     struct SheetProtectionOptions opts;
+    int ErrCode;
     char *err;
 };
 
@@ -968,12 +1079,14 @@ struct GetSheetMapResult
 {
     int ArrLen;
     struct IntStringResult *Arr;
+    int ErrCode;
     char *Err;
 };
 
 struct GetSheetViewResult
 {
     struct ViewOptions opts;
+    int ErrCode;
     char *err;
 };
 
@@ -982,6 +1095,7 @@ struct GetCommentsResult
 {
     int CommentsLen;
     struct Comment *Comments;
+    int ErrCode;
     char *Err;
 };
 
@@ -989,6 +1103,7 @@ struct GetCustomPropsResult
 {
     int CustomPropsLen;
     struct CustomProperty *CustomProps;
+    int ErrCode;
     char *Err;
 };
 
@@ -996,12 +1111,14 @@ struct GetDefinedNameResult
 {
     int DefinedNamesLen;
     struct DefinedName *DefinedNames;
+    int ErrCode;
     char *Err;
 };
 
 struct GetDocPropsResult
 {
     struct DocProperties opts;
+    int ErrCode;
     char *err;
 };
 
@@ -1009,18 +1126,21 @@ struct GetFormControlsResult
 {
     int FormControlsLen;
     struct FormControl *FormControls;
+    int ErrCode;
     char *Err;
 };
 
 struct GetPageLayoutResult
 {
     struct PageLayoutOptions opts;
+    int ErrCode;
     char *err;
 };
 
 struct GetPageMarginsResult
 {
     struct PageLayoutMarginsOptions opts;
+    int ErrCode;
     char *err;
 };
 
@@ -1028,6 +1148,7 @@ struct GetPicturesResult
 {
     int PicturesLen;
     struct Picture *Pictures;
+    int ErrCode;
     char *Err;
 };
 
@@ -1035,11 +1156,88 @@ struct GetPivotTablesResult
 {
     int PivotTablesLen;
     struct PivotTableOptions *PivotTables;
+    int ErrCode;
     char *Err;
 };
 
 struct GetRowOptsResult
 {
     struct RowOpts opts;
+    int ErrCode;
     char *err;
 };
+
+struct ListHandlesResult
+{
+    int FilesLen;
+    int *Files;
+    int RowsLen;
+    int *Rows;
+    int StreamWritersLen;
+    int *StreamWriters;
+    int ColsLen;
+    int *Cols;
+    int ErrCode;
+    char *Err;
+};
+
+struct BuildInfoResult
+{
+    char *Version;
+    int ABIVersion;
+    char *ExcelizeVersion;
+    char *GoVersion;
+    int ImageDecodersLen;
+    char **ImageDecoders;
+    int ErrCode;
+    char *Err;
+};
+
+// ProgressPhase defines the long-running operations reported to the progress
+// callbacks.
+enum ProgressPhase
+{
+    ProgressOpen = 1,
+    ProgressSave = 2,
+    ProgressFlush = 3,
+    ProgressCalc = 4
+};
+
+// ProgressStep defines the steps of opening and saving a workbook and flushing
+// a stream writer reported to the progress callbacks.
+enum ProgressStep
+{
+    ProgressStepRead = 1,
+    ProgressStepParse = 2,
+    ProgressStepSerialize = 3,
+    ProgressStepWrite = 4
+};
+
+// ProgressEvent directly maps the progress of a long-running operation. The
+// Step is the current step of the operation, or 0 for the calculations. The
+// BytesProcessed is the number of bytes read or parsed when opening a
+// workbook, or the number of bytes serialized or written when saving it, and
+// the BytesTotal is 0 if the size is unknown. The RowsProcessed is the number
+// of rows written by the stream writer. The Done is true for the last event of
+// an operation.
+struct ProgressEvent
+{
+    int Phase;
+    int Step;
+    char *Sheet;
+    long long RowsProcessed;
+    long long BytesProcessed;
+    long long BytesTotal;
+    bool Done;
+};
+
+// ProgressCallback is the callback receiving the progress events, returning a
+// non-zero value cancels the operation.
+typedef int (*ProgressCallback)(struct ProgressEvent *event);
+
+// invokeProgressCallback calls the progress callback with the event, since the
+// C function pointers can not be called from Go directly.
+static inline int invokeProgressCallback(ProgressCallback callback, struct ProgressEvent *event)
+{
+    return callback(event);
+}
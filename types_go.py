@@ -18,8 +18,11 @@ from ctypes import (
     c_int,
     c_int32,
     c_long,
+    c_longlong,
     c_ubyte,
     c_uint,
+    c_ulonglong,
+    CFUNCTYPE,
     Structure,
     POINTER,
 )
@@ -28,11 +31,15 @@ from ctypes import (
 class _Interface(Structure):
     _fields_ = [
         ("Type", c_int),
-        ("Integer", c_int),
+        ("Integer", c_longlong),
         ("Integer32", c_int32),
         ("String", c_char_p),
         ("Float64", c_double),
         ("Boolean", c_bool),
+        ("UInteger64", c_ulonglong),
+        ("Nanosecond", c_int),
+        ("Offset", c_int),
+        ("Location", c_char_p),
     ]
 
 
@@ -88,14 +95,6 @@ class _CustomProperty(Structure):
     ]
 
 
-class _Cell(Structure):
-    _fields_ = [
-        ("StyleID", c_int),
-        ("Formula", c_char_p),
-        ("Value", _Interface),
-    ]
-
-
 class _DataValidation(Structure):
     _fields_ = [
         ("AllowBlank", c_bool),
@@ -347,6 +346,16 @@ class _RichTextRun(Structure):
     ]
 
 
+class _Cell(Structure):
+    _fields_ = [
+        ("StyleID", c_int),
+        ("Formula", c_char_p),
+        ("Value", _Interface),
+        ("RichTextLen", c_int),
+        ("RichText", POINTER(_RichTextRun)),
+    ]
+
+
 class _Comment(Structure):
     _fields_ = [
         ("Author", c_char_p),
@@ -794,9 +803,17 @@ class _WorkbookProtectionOptions(Structure):
     ]
 
 
+class _ErrorResult(Structure):
+    _fields_ = [
+        ("ErrCode", c_int),
+        ("err", c_char_p),
+    ]
+
+
 class _StringErrorResult(Structure):
     _fields_ = [
         ("val", c_char_p),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -805,6 +822,7 @@ class _StringIntErrorResult(Structure):
     _fields_ = [
         ("strVal", c_char_p),
         ("intVal", c_int),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -812,6 +830,7 @@ class _StringIntErrorResult(Structure):
 class _IntErrorResult(Structure):
     _fields_ = [
         ("val", c_int),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -819,6 +838,7 @@ class _IntErrorResult(Structure):
 class _BoolErrorResult(Structure):
     _fields_ = [
         ("val", c_bool),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -826,6 +846,7 @@ class _BoolErrorResult(Structure):
 class _Float64ErrorResult(Structure):
     _fields_ = [
         ("val", c_double),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -837,10 +858,28 @@ class _IntStringResult(Structure):
     ]
 
 
+class _InterfaceErrorResult(Structure):
+    _fields_ = [
+        ("val", _Interface),
+        ("ErrCode", c_int),
+        ("err", c_char_p),
+    ]
+
+
 class _StringArrayErrorResult(Structure):
     _fields_ = [
         ("ArrLen", c_int),
         ("Arr", POINTER(POINTER(c_char))),
+        ("ErrCode", c_int),
+        ("Err", c_char_p),
+    ]
+
+
+class _BytesErrorResult(Structure):
+    _fields_ = [
+        ("ArrLen", c_int),
+        ("Arr", POINTER(c_ubyte)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -849,6 +888,7 @@ class _CellNameToCoordinatesResult(Structure):
     _fields_ = [
         ("col", c_int),
         ("row", c_int),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -856,6 +896,7 @@ class _CellNameToCoordinatesResult(Structure):
 class _GetAppPropsResult(Structure):
     _fields_ = [
         ("opts", _AppProperties),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -863,6 +904,7 @@ class _GetAppPropsResult(Structure):
 class _GetCalcPropsResult(Structure):
     _fields_ = [
         ("opts", _CalcPropsOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -871,6 +913,7 @@ class _GetCellHyperLinkResult(Structure):
     _fields_ = [
         ("link", c_bool),
         ("target", c_char_p),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -886,14 +929,46 @@ class _StringMatrixErrorResult(Structure):
     _fields_ = [
         ("RowLen", c_int),
         ("Row", POINTER(_Cells)),
+        ("RowNumLen", c_int),
+        ("RowNum", POINTER(c_int)),
+        ("RowOptsLen", c_int),
+        ("RowOpts", POINTER(_RowOpts)),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
 
+class _TypedCell(Structure):
+    _fields_ = [
+        ("CellType", c_int),
+        ("Value", _Interface),
+        ("Raw", c_char_p),
+        ("Formatted", c_char_p),
+        ("StyleID", c_int),
+    ]
+
+
+class _TypedCells(Structure):
+    _fields_ = [
+        ("CellLen", c_int),
+        ("Cell", POINTER(_TypedCell)),
+    ]
+
+
+class _TypedCellMatrixErrorResult(Structure):
+    _fields_ = [
+        ("RowLen", c_int),
+        ("Row", POINTER(_TypedCells)),
+        ("ErrCode", c_int),
+        ("Err", c_char_p),
+    ]
+
+
 class _GetCellRichTextResult(Structure):
     _fields_ = [
         ("RunsLen", c_int),
         ("Runs", POINTER(_RichTextRun)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -902,6 +977,7 @@ class _GetDataValidationsResult(Structure):
     _fields_ = [
         ("DvsLen", c_int),
         ("Dvs", POINTER(_DataValidation)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -910,6 +986,7 @@ class _GetSlicersResult(Structure):
     _fields_ = [
         ("SlicersLen", c_int),
         ("Slicers", POINTER(_SlicerOptions)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -917,14 +994,29 @@ class _GetSlicersResult(Structure):
 class _GetStyleResult(Structure):
     _fields_ = [
         ("style", _Style),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
 
+class _GetTableRowsResult(Structure):
+    _fields_ = [
+        ("Sheet", c_char_p),
+        ("Range", c_char_p),
+        ("HeaderLen", c_int),
+        ("Header", POINTER(POINTER(c_char))),
+        ("RowLen", c_int),
+        ("Row", POINTER(_TypedCells)),
+        ("ErrCode", c_int),
+        ("Err", c_char_p),
+    ]
+
+
 class _GetTablesResult(Structure):
     _fields_ = [
         ("TablesLen", c_int),
         ("Tables", POINTER(_Table)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -932,6 +1024,7 @@ class _GetTablesResult(Structure):
 class _GetWorkbookPropsResult(Structure):
     _fields_ = [
         ("opts", _WorkbookPropsOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -939,6 +1032,7 @@ class _GetWorkbookPropsResult(Structure):
 class _GetSheetPropsResult(Structure):
     _fields_ = [
         ("opts", _SheetPropsOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -946,6 +1040,7 @@ class _GetSheetPropsResult(Structure):
 class _GetSheetProtectionResult(Structure):
     _fields_ = [
         ("opts", _SheetProtectionOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -954,6 +1049,7 @@ class _GetSheetMapResult(Structure):
     _fields_ = [
         ("ArrLen", c_int),
         ("Arr", POINTER(_IntStringResult)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -961,6 +1057,7 @@ class _GetSheetMapResult(Structure):
 class _GetSheetViewResult(Structure):
     _fields_ = [
         ("opts", _ViewOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -969,6 +1066,7 @@ class _GetCommentsResult(Structure):
     _fields_ = [
         ("CommentsLen", c_int),
         ("Comments", POINTER(_Comment)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -977,6 +1075,7 @@ class _GetCustomPropsResult(Structure):
     _fields_ = [
         ("CustomPropsLen", c_int),
         ("CustomProps", POINTER(_CustomProperty)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -985,6 +1084,7 @@ class _GetDefinedNameResult(Structure):
     _fields_ = [
         ("DefinedNamesLen", c_int),
         ("DefinedNames", POINTER(_DefinedName)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -992,6 +1092,7 @@ class _GetDefinedNameResult(Structure):
 class _GetDocPropsResult(Structure):
     _fields_ = [
         ("opts", _DocProperties),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -1000,6 +1101,7 @@ class _GetFormControlsResult(Structure):
     _fields_ = [
         ("FormControlsLen", c_int),
         ("FormControls", POINTER(_FormControl)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -1007,6 +1109,7 @@ class _GetFormControlsResult(Structure):
 class _GetPageLayoutResult(Structure):
     _fields_ = [
         ("opts", _PageLayoutOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -1014,6 +1117,7 @@ class _GetPageLayoutResult(Structure):
 class _GetPageMarginsResult(Structure):
     _fields_ = [
         ("opts", _PageLayoutMarginsOptions),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
 
@@ -1022,6 +1126,7 @@ class _GetPicturesResult(Structure):
     _fields_ = [
         ("PicturesLen", c_int),
         ("Pictures", POINTER(_Picture)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -1030,6 +1135,7 @@ class _GetPivotTablesResult(Structure):
     _fields_ = [
         ("PivotTablesLen", c_int),
         ("PivotTables", POINTER(_PivotTableOptions)),
+        ("ErrCode", c_int),
         ("Err", c_char_p),
     ]
 
@@ -1037,5 +1143,49 @@ class _GetPivotTablesResult(Structure):
 class _GetRowOptsResult(Structure):
     _fields_ = [
         ("opts", _RowOpts),
+        ("ErrCode", c_int),
         ("err", c_char_p),
     ]
+
+
+class _ListHandlesResult(Structure):
+    _fields_ = [
+        ("FilesLen", c_int),
+        ("Files", POINTER(c_int)),
+        ("RowsLen", c_int),
+        ("Rows", POINTER(c_int)),
+        ("StreamWritersLen", c_int),
+        ("StreamWriters", POINTER(c_int)),
+        ("ColsLen", c_int),
+        ("Cols", POINTER(c_int)),
+        ("ErrCode", c_int),
+        ("Err", c_char_p),
+    ]
+
+
+class _BuildInfoResult(Structure):
+    _fields_ = [
+        ("Version", c_char_p),
+        ("ABIVersion", c_int),
+        ("ExcelizeVersion", c_char_p),
+        ("GoVersion", c_char_p),
+        ("ImageDecodersLen", c_int),
+        ("ImageDecoders", POINTER(POINTER(c_char))),
+        ("ErrCode", c_int),
+        ("Err", c_char_p),
+    ]
+
+
+class _ProgressEvent(Structure):
+    _fields_ = [
+        ("Phase", c_int),
+        ("Step", c_int),
+        ("Sheet", c_char_p),
+        ("RowsProcessed", c_longlong),
+        ("BytesProcessed", c_longlong),
+        ("BytesTotal", c_longlong),
+        ("Done", c_bool),
+    ]
+
+
+_ProgressCallback = CFUNCTYPE(c_int, POINTER(_ProgressEvent))
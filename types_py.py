@@ -12,7 +12,7 @@ amounts of data. This library needs Python version 3.9 or later.
 
 from dataclasses import dataclass, field
 from enum import IntEnum
-from datetime import datetime, date
+from datetime import datetime, date, timedelta
 from typing import List, Optional, Union
 
 
@@ -92,6 +92,32 @@ class CellType(IntEnum):
     CellTypeSharedString = 7
 
 
+class ErrorCode(IntEnum):
+    """
+    ErrorCode defines the stable numeric codes of the errors returned by the
+    functions.
+    """
+
+    ErrCodeNone = 0
+    ErrCodeUnknown = 1
+    ErrCodePanic = 2
+    ErrCodeHandleNotFound = 3
+    ErrCodeHandleReleased = 4
+    ErrCodeFileNotExist = 5
+    ErrCodeSheetNotExist = 6
+    ErrCodeParameterInvalid = 7
+    ErrCodeParameterRequired = 8
+    ErrCodeCoordinates = 9
+    ErrCodeSheetName = 10
+    ErrCodeWorkbookPassword = 11
+    ErrCodeProtection = 12
+    ErrCodeUnsupported = 13
+    ErrCodeExceedsLimit = 14
+    ErrCodeNotExist = 15
+    ErrCodeCanceled = 16
+    ErrCodeTimeout = 17
+
+
 class FormControlType(IntEnum):
     """
     FormControlType is the type of supported form controls.
@@ -331,6 +357,30 @@ class PivotTableShowValuesAsType(IntEnum):
     PivotTableShowValuesAsIndex = 14
 
 
+class ProgressPhase(IntEnum):
+    """
+    ProgressPhase defines the long-running operations reported to the progress
+    callbacks.
+    """
+
+    ProgressOpen = 1
+    ProgressSave = 2
+    ProgressFlush = 3
+    ProgressCalc = 4
+
+
+class ProgressStep(IntEnum):
+    """
+    ProgressStep defines the steps of opening and saving a workbook and flushing
+    a stream writer reported to the progress callbacks.
+    """
+
+    ProgressStepRead = 1
+    ProgressStepParse = 2
+    ProgressStepSerialize = 3
+    ProgressStepWrite = 4
+
+
 @dataclass
 class Interface:
     type: int = 0
@@ -338,6 +388,10 @@ class Interface:
     string: str = ""
     float64: float = 0
     boolean: bool = False
+    u_integer64: int = 0
+    nanosecond: int = 0
+    offset: int = 0
+    location: str = ""
 
 
 @dataclass
@@ -733,6 +787,39 @@ class Cells:
 @dataclass
 class StringMatrixErrorResult:
     row: Optional[List[Cells]] = None
+    row_num: Optional[List[int]] = None
+    row_opts: Optional[List[RowOpts]] = None
+
+
+class CellError(str):
+    """
+    CellError is the typed value of an error cell, the value is the error code
+    of the cell, such as `#DIV/0!`.
+    """
+
+
+class CellFormula(str):
+    """
+    CellFormula is the typed value of a formula string cell, the value is the
+    cached string result of the formula.
+    """
+
+
+@dataclass
+class TypedCell:
+    cell_type: CellType = CellType.CellTypeUnset
+    value: Union[bool, float, str, datetime, None] = None
+    raw: str = ""
+    formatted: str = ""
+    style_id: int = 0
+
+
+@dataclass
+class TableRows:
+    sheet: str = ""
+    range: str = ""
+    header: Optional[List[str]] = None
+    rows: Optional[List[List[TypedCell]]] = None
 
 
 @dataclass
@@ -809,6 +896,14 @@ class RichTextRun:
     text: str = ""
 
 
+@dataclass
+class Cell:
+    style_id: int = 0
+    formula: str = ""
+    value: Union[bool, float, int, str, date, datetime, timedelta, None] = None
+    rich_text: Optional[List[RichTextRun]] = None
+
+
 @dataclass
 class GetCellRichTextResult:
     runs: Optional[List[RichTextRun]] = None
@@ -1382,3 +1477,31 @@ class GetPicturesResult:
 class GetPivotTablesResult:
     pivot_tables: Optional[List[PivotTableOptions]] = None
     err: str = ""
+
+
+@dataclass
+class ListHandlesResult:
+    files: Optional[List[int]] = None
+    rows: Optional[List[int]] = None
+    stream_writers: Optional[List[int]] = None
+    cols: Optional[List[int]] = None
+
+
+@dataclass
+class BuildInfoResult:
+    version: str = ""
+    abi_version: int = 0
+    excelize_version: str = ""
+    go_version: str = ""
+    image_decoders: Optional[List[str]] = None
+
+
+@dataclass
+class ProgressEvent:
+    phase: ProgressPhase = ProgressPhase.ProgressOpen
+    step: Optional[ProgressStep] = None
+    sheet: str = ""
+    rows_processed: int = 0
+    bytes_processed: int = 0
+    bytes_total: int = 0
+    done: bool = False